@@ -0,0 +1,644 @@
+// Package backup Snapshot the CMS of a site, every collection's schema & items, into a directory or tar.gz archive and
+// restore the items into the same, or another, site. Restoring remaps the item IDs in reference fields to the IDs of
+// the restored items.
+//
+// A snapshot holds:
+//
+//	manifest.json                The Manifest.
+//	collections/<ID>.json        The collection along with its fields.
+//	items/<ID>.ndjson            The collection's items, one per line.
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/internal/object"
+)
+
+const (
+	// FormatVersion Version of the snapshot layout, recorded in the manifest.
+	FormatVersion = 1
+	// DefaultMaxPages Number of additional pages of items requested per collection.
+	DefaultMaxPages = 100
+
+	manifestFile = "manifest.json"
+	// Layout of the snapshot names created within a backup directory.
+	snapshotLayout = "20060102T150405Z"
+)
+
+// Manifest Describes a snapshot.
+type Manifest struct {
+	Version     int                  `json:"version"`
+	SiteID      string               `json:"siteId"`
+	SiteName    string               `json:"siteName,omitempty"`
+	CreatedOn   time.Time            `json:"createdOn"`
+	Collections []ManifestCollection `json:"collections"`
+}
+
+// ManifestCollection A collection in the snapshot and the files holding it.
+type ManifestCollection struct {
+	ID         string `json:"_id"`
+	Name       string `json:"name"`
+	Slug       string `json:"slug"`
+	Items      int    `json:"items"`
+	SchemaFile string `json:"schemaFile"`
+	ItemsFile  string `json:"itemsFile"`
+}
+
+// RestoreResult What Restore did.
+type RestoreResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	// Errors Items that could not be restored, which do not stop the restore.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Backups Snapshots & restores sites.
+type Backups struct {
+	// MaxPages Number of additional pages of items to request. See webflowAPI.Interface.GetAllItemsInCollectionByID.
+	// Backup errors for collections with more pages.
+	MaxPages int
+	// Live Publish restored items immediately.
+	Live bool
+
+	api webflowAPI.Interface
+	// Override for the clock. Use only for internal testing of the pkg.
+	now func() time.Time
+}
+
+// New Create backups of the site the API is configured for.
+func New(api webflowAPI.Interface) *Backups {
+	return &Backups{
+		MaxPages: DefaultMaxPages,
+		api:      api,
+		now:      time.Now,
+	}
+}
+
+// Backup Snapshot the site's collections. When dest ends with .tar.gz or .tgz the snapshot is written to that archive;
+// otherwise it is written to a new timestamped directory within dest. siteID must be the site the API is configured
+// for and is recorded in the manifest. Returns the path of the snapshot.
+func (b *Backups) Backup(siteID, dest string) (string, error) {
+	manifest := &Manifest{
+		Version:     FormatVersion,
+		SiteID:      siteID,
+		CreatedOn:   b.now().UTC(),
+		Collections: []ManifestCollection{},
+	}
+
+	if sites, err := b.api.GetAllSites(); err == nil {
+		for _, site := range *sites {
+			if site.ID == siteID {
+				manifest.SiteName = site.Name
+			}
+		}
+	}
+
+	files := map[string][]byte{}
+
+	collections, err := b.api.GetAllCollections()
+	if err != nil {
+		return "", err
+	}
+
+	for _, info := range *collections {
+		// The collections list does not include the fields.
+		collection, err := b.api.GetCollectionByID(info.ID)
+		if err != nil {
+			return "", fmt.Errorf("unable to read collection '%s'; error: %+v", info.Slug, err)
+		}
+		if collection == nil {
+			return "", fmt.Errorf("collection '%s' not found", info.Slug)
+		}
+
+		items, err := b.items(info.ID)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the items of collection '%s'; error: %+v", info.Slug, err)
+		}
+
+		entry := ManifestCollection{
+			ID:         info.ID,
+			Name:       info.Name,
+			Slug:       info.Slug,
+			Items:      len(items),
+			SchemaFile: path.Join("collections", info.ID+".json"),
+			ItemsFile:  path.Join("items", info.ID+".ndjson"),
+		}
+
+		if files[entry.SchemaFile], err = json.MarshalIndent(collection, "", "  "); err != nil {
+			return "", err
+		}
+
+		buf := &bytes.Buffer{}
+		for _, item := range items {
+			if err := json.Compact(buf, item); err != nil {
+				return "", fmt.Errorf("unable to encode an item of collection '%s'; error: %+v", info.Slug, err)
+			}
+			buf.WriteString("\n")
+		}
+		files[entry.ItemsFile] = buf.Bytes()
+
+		manifest.Collections = append(manifest.Collections, entry)
+	}
+
+	if files[manifestFile], err = json.MarshalIndent(manifest, "", "  "); err != nil {
+		return "", err
+	}
+
+	if isArchive(dest) {
+		return dest, writeArchive(dest, files, manifest.CreatedOn)
+	}
+
+	dir := filepath.Join(dest, manifest.CreatedOn.Format(snapshotLayout))
+	return dir, writeDir(dir, files)
+}
+
+// items Read every item of the collection. Errors when fewer items are read than the API reports, e.g. past MaxPages
+// or as items are deleted mid-read, so a snapshot is never silently missing items.
+func (b *Backups) items(collectionID string) ([][]byte, error) {
+	items := [][]byte{}
+	paginator := webflowAPI.NewPaginator(b.api, webflowAPI.NewCursor(collectionID))
+
+	// At least one page is requested.
+	for page := 0; !paginator.Done() && (page == 0 || page <= b.MaxPages); page++ {
+		pageItems, err := paginator.Next()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, pageItems...)
+	}
+
+	if total := paginator.Cursor().Total; len(items) != total {
+		return nil, fmt.Errorf("read %d of the %d items; raise MaxPages, or retry once the collection is not changing", len(items), total)
+	}
+
+	return items, nil
+}
+
+// ReadManifest Read the manifest of a snapshot directory or archive.
+func ReadManifest(src string) (*Manifest, error) {
+	s, err := openSnapshot(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.manifest, nil
+}
+
+// ReadItems Read the items of a collection, given by slug or ID, from a snapshot directory or archive.
+func ReadItems(src, collection string) ([][]byte, error) {
+	s, err := openSnapshot(src)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range s.manifest.Collections {
+		if entry.Slug != collection && entry.ID != collection {
+			continue
+		}
+
+		items := [][]byte{}
+		for _, line := range bytes.Split(s.files[entry.ItemsFile], []byte("\n")) {
+			if len(bytes.TrimSpace(line)) > 0 {
+				items = append(items, line)
+			}
+		}
+		return items, nil
+	}
+
+	return nil, fmt.Errorf("the backup has no collection '%s'", collection)
+}
+
+// Restore Recreate the items of a snapshot in the site the API is configured for. Collections are matched by slug
+// and must already exist. Items are matched by slug: existing items are updated, others are created. Reference
+// fields are remapped to the IDs of the restored items.
+func (b *Backups) Restore(src string) (*RestoreResult, error) {
+	s, err := openSnapshot(src)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := b.api.GetAllCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	plans := []*restorePlan{}
+	for _, entry := range s.manifest.Collections {
+		plan, err := s.plan(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, target := range *targets {
+			if target.Slug == entry.Slug {
+				plan.targetID = target.ID
+			}
+		}
+		if plan.targetID == "" {
+			return nil, fmt.Errorf("the site has no collection with the slug '%s'", entry.Slug)
+		}
+
+		if plan.existing, err = b.slugs(plan.targetID); err != nil {
+			return nil, err
+		}
+
+		plans = append(plans, plan)
+	}
+
+	result := &RestoreResult{}
+	// Item IDs of the snapshot mapped to those of the restored items.
+	ids := map[string]string{}
+
+	// Referenced collections are restored first so most references can be written with the item; the others, e.g.
+	// self references, are patched in once every item exists.
+	deferred := []*deferredRefs{}
+	for _, plan := range orderPlans(plans) {
+		for _, item := range plan.items {
+			oldID, _ := item["_id"].(string)
+			fields, later := plan.fields(item, ids)
+
+			newID, err := b.writeItem(plan, fields, result)
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s/%s: %+v", plan.entry.Slug, item["slug"], err))
+				continue
+			}
+			ids[oldID] = newID
+
+			if len(later) > 0 {
+				deferred = append(deferred, &deferredRefs{plan: plan, itemID: newID, slug: item["slug"], refs: later})
+			}
+		}
+	}
+
+	for _, d := range deferred {
+		fields := map[string]interface{}{}
+		for slug, ref := range d.refs {
+			fields[slug] = remap(ref, ids)
+		}
+		if _, err := b.api.PatchItem(d.plan.targetID, d.itemID, fields, b.Live); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s/%s: unable to restore references; error: %+v", d.plan.entry.Slug, d.slug, err))
+		}
+	}
+
+	return result, nil
+}
+
+// writeItem Update the item with the same slug, or create it, returning its ID.
+func (b *Backups) writeItem(plan *restorePlan, fields map[string]interface{}, result *RestoreResult) (string, error) {
+	slug, _ := fields["slug"].(string)
+	if id, ok := plan.existing[slug]; ok {
+		if _, err := b.api.UpdateItem(plan.targetID, id, fields, b.Live); err != nil {
+			return "", err
+		}
+		result.Updated++
+		return id, nil
+	}
+
+	item, err := b.api.CreateItem(plan.targetID, fields, b.Live)
+	if err != nil {
+		return "", err
+	}
+
+	created := struct {
+		ID string `json:"_id"`
+	}{}
+	if err := json.Unmarshal(item, &created); err != nil {
+		return "", err
+	}
+	result.Created++
+	plan.existing[slug] = created.ID
+
+	return created.ID, nil
+}
+
+// slugs The IDs of a collection's items by slug.
+func (b *Backups) slugs(collectionID string) (map[string]string, error) {
+	items, err := b.api.GetAllItemsInCollectionByID(collectionID, b.MaxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	slugs := map[string]string{}
+	for _, raw := range items {
+		item := struct {
+			ID   string `json:"_id"`
+			Slug string `json:"slug"`
+		}{}
+		if err := json.Unmarshal(raw, &item); err == nil {
+			slugs[item.Slug] = item.ID
+		}
+	}
+
+	return slugs, nil
+}
+
+// restorePlan A collection of the snapshot and where it is restored to.
+type restorePlan struct {
+	entry    ManifestCollection
+	schema   *webflowAPI.Collection
+	items    []map[string]interface{}
+	targetID string
+	// existing IDs of the target collection's items by slug.
+	existing map[string]string
+}
+
+// deferredRefs References written once every item exists.
+type deferredRefs struct {
+	plan   *restorePlan
+	itemID string
+	slug   interface{}
+	refs   map[string]interface{}
+}
+
+// fields The item's fields without metadata, with references remapped. References to items not yet restored are
+// returned separately.
+func (plan *restorePlan) fields(item map[string]interface{}, ids map[string]string) (map[string]interface{}, map[string]interface{}) {
+	refTypes := map[string]bool{}
+	for _, field := range plan.schema.Fields {
+		if field.Type == webflowAPI.FieldTypeItemRef || field.Type == webflowAPI.FieldTypeItemRefSet {
+			refTypes[field.Slug] = true
+		}
+	}
+
+	fields := map[string]interface{}{}
+	later := map[string]interface{}{}
+	for key, val := range item {
+		switch {
+		case object.IsMetadata(key):
+		case refTypes[key] && val != nil:
+			if resolved(val, ids) {
+				fields[key] = remap(val, ids)
+			} else {
+				later[key] = val
+			}
+		default:
+			fields[key] = val
+		}
+	}
+
+	return fields, later
+}
+
+// orderPlans Put the collections that others reference first. Cycles keep the snapshot's order.
+func orderPlans(plans []*restorePlan) []*restorePlan {
+	byID := map[string]*restorePlan{}
+	for _, plan := range plans {
+		byID[plan.entry.ID] = plan
+	}
+
+	ordered := []*restorePlan{}
+	state := map[string]int{} // 1 visiting, 2 done
+	var visit func(plan *restorePlan)
+	visit = func(plan *restorePlan) {
+		if state[plan.entry.ID] != 0 {
+			return
+		}
+		state[plan.entry.ID] = 1
+		for _, field := range plan.schema.Fields {
+			if field.Validations == nil {
+				continue
+			}
+			if ref, ok := byID[field.Validations.CollectionID]; ok {
+				visit(ref)
+			}
+		}
+		state[plan.entry.ID] = 2
+		ordered = append(ordered, plan)
+	}
+	for _, plan := range plans {
+		visit(plan)
+	}
+
+	return ordered
+}
+
+// resolved Whether every item a reference names has been restored.
+func resolved(ref interface{}, ids map[string]string) bool {
+	switch v := ref.(type) {
+	case string:
+		_, ok := ids[v]
+		return ok
+	case []interface{}:
+		for _, id := range v {
+			if !resolved(id, ids) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// remap Replace the snapshot's item IDs in a reference with those of the restored items. References to items that
+// were not restored are dropped.
+func remap(ref interface{}, ids map[string]string) interface{} {
+	switch v := ref.(type) {
+	case string:
+		if id, ok := ids[v]; ok {
+			return id
+		}
+		return nil
+	case []interface{}:
+		remapped := []interface{}{}
+		for _, id := range v {
+			if newID := remap(id, ids); newID != nil {
+				remapped = append(remapped, newID)
+			}
+		}
+		return remapped
+	}
+
+	return ref
+}
+
+// snapshot The files of a snapshot directory or archive.
+type snapshot struct {
+	manifest *Manifest
+	files    map[string][]byte
+}
+
+// openSnapshot Read a snapshot directory or archive.
+func openSnapshot(src string) (*snapshot, error) {
+	var files map[string][]byte
+	var err error
+	if isArchive(src) {
+		files, err = readArchive(src)
+	} else {
+		files, err = readDir(src)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &snapshot{manifest: &Manifest{}, files: files}
+	data, ok := files[manifestFile]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a backup; it has no %s", src, manifestFile)
+	}
+	if err := json.Unmarshal(data, s.manifest); err != nil {
+		return nil, fmt.Errorf("unable to decode the manifest; error: %+v", err)
+	}
+	if s.manifest.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported backup version %d", s.manifest.Version)
+	}
+
+	return s, nil
+}
+
+// plan Read a collection's schema & items from the snapshot.
+func (s *snapshot) plan(entry ManifestCollection) (*restorePlan, error) {
+	plan := &restorePlan{entry: entry, schema: &webflowAPI.Collection{}, items: []map[string]interface{}{}}
+
+	if err := json.Unmarshal(s.files[entry.SchemaFile], plan.schema); err != nil {
+		return nil, fmt.Errorf("unable to decode the schema of collection '%s'; error: %+v", entry.Slug, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(s.files[entry.ItemsFile]))
+	for {
+		item := map[string]interface{}{}
+		if err := decoder.Decode(&item); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to decode the items of collection '%s'; error: %+v", entry.Slug, err)
+		}
+		plan.items = append(plan.items, item)
+	}
+
+	return plan, nil
+}
+
+// isArchive Whether the path names a tar.gz archive rather than a directory.
+func isArchive(p string) bool {
+	return strings.HasSuffix(p, ".tar.gz") || strings.HasSuffix(p, ".tgz")
+}
+
+// writeDir Write the files to a new directory.
+func writeDir(dir string, files map[string][]byte) error {
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("the backup %s already exists", dir)
+	}
+
+	for name, data := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(p, data, 0600); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readDir Read the files of a snapshot directory.
+func readDir(dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)], err = ioutil.ReadFile(p)
+		return err
+	})
+
+	return files, err
+}
+
+// writeArchive Write the files to a tar.gz archive, the manifest first then the others by name, each stamped with the
+// given time.
+func writeArchive(p string, files map[string][]byte, modTime time.Time) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	// The manifest first, then the rest in order, so the same snapshot is always the same archive.
+	names := []string{}
+	for name := range files {
+		if name != manifestFile {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	names = append([]string{manifestFile}, names...)
+
+	for _, name := range names {
+		header := &tar.Header{Name: name, Mode: 0600, Size: int64(len(files[name])), ModTime: modTime}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	return f.Sync()
+}
+
+// readArchive Read the files of a tar.gz archive.
+func readArchive(p string) (map[string][]byte, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if files[path.Clean(header.Name)], err = ioutil.ReadAll(tr); err != nil {
+			return nil, err
+		}
+	}
+}
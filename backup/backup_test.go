@@ -0,0 +1,157 @@
+package backup
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/memory"
+)
+
+// newTarget Create an empty site with the source's collections under new IDs.
+func newTarget(t *testing.T, source *memory.Store) *memory.Store {
+	target := memory.New("targetsiteid")
+
+	collections, _ := source.GetAllCollections()
+	for _, info := range *collections {
+		collection, _ := source.GetCollectionByID(info.ID)
+		collection.ID = "new-" + collection.ID
+		for i, field := range collection.Fields {
+			if field.Validations != nil && field.Validations.CollectionID != "" {
+				validations := *field.Validations
+				validations.CollectionID = "new-" + validations.CollectionID
+				collection.Fields[i].Validations = &validations
+			}
+		}
+		target.AddCollection(*collection)
+	}
+
+	return target
+}
+
+func decodeItem(t *testing.T, raw []byte) map[string]interface{} {
+	item := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &item); err != nil {
+		t.Fatalf("Unable to decode the item %s: %+v", raw, err)
+	}
+	return item
+}
+
+func TestBackupRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webflow-backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	source, err := memory.Load("mysiteid", "testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dest := range []string{dir, filepath.Join(dir, "site.tar.gz")} {
+		b := New(source)
+		b.now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+		snapshot, err := b.Backup("mysiteid", dest)
+		if err != nil {
+			t.Fatalf("Backup() is expected to snapshot the site to %s: %+v", dest, err)
+		}
+
+		manifest, err := ReadManifest(snapshot)
+		if err != nil || manifest.SiteName != "My Site" || len(manifest.Collections) != 2 || manifest.Collections[0].Items != 2 {
+			t.Fatalf("Backup() is expected to write a manifest of the collections! Got %+v; error %+v.", manifest, err)
+		}
+
+		if items, err := ReadItems(snapshot, "authors"); err != nil || len(items) != 2 {
+			t.Errorf("ReadItems() is expected to read the collection's items from the backup! Got %d; error %+v.", len(items), err)
+		}
+
+		target := newTarget(t, source)
+		result, err := New(target).Restore(snapshot)
+		if err != nil || result.Created != 4 || len(result.Errors) != 0 {
+			t.Fatalf("Restore() is expected to create every item in the target site! Got %+v; error %+v.", result, err)
+		}
+
+		grace, _ := target.GetItem("", "", "new-authors1", "Grace", "")
+		first, _ := target.GetItem("", "", "new-posts1", "Hello, world", "")
+		second, _ := target.GetItem("", "", "new-posts1", "Second", "")
+		graceID, secondID := decodeItem(t, grace)["_id"], decodeItem(t, second)["_id"]
+
+		post := decodeItem(t, first)
+		editors, _ := post["editors"].([]interface{})
+		if len(editors) != 2 || editors[1] != graceID {
+			t.Errorf("Restore() is expected to remap references to the restored items! Got %+v.", post)
+		}
+		if post["related"] != secondID {
+			t.Errorf("Restore() is expected to remap references to items restored later! Got %+v; expected %s.", post["related"], secondID)
+		}
+
+		// Restoring again updates rather than duplicates.
+		result, err = New(target).Restore(snapshot)
+		if err != nil || result.Updated != 4 || result.Created != 0 {
+			t.Errorf("Restore() is expected to update the items with matching slugs! Got %+v; error %+v.", result, err)
+		}
+	}
+}
+
+func TestRestoreMissingCollection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webflow-backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	source, _ := memory.Load("mysiteid", "testdata/site.json")
+	snapshot, err := New(source).Backup("mysiteid", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := memory.New("targetsiteid")
+	target.AddCollection(webflowAPI.Collection{ID: "x", Slug: "authors"})
+	if _, err := New(target).Restore(snapshot); err == nil {
+		t.Error("Restore() is expected to error when the site lacks a collection of the backup.")
+	}
+}
+
+func TestBackupComplete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webflow-backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	source, err := memory.Load("mysiteid", "testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.PageSize = 1
+
+	b := New(source)
+	b.now = func() time.Time { return time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	// The same snapshot is the same archive.
+	archives := [][]byte{}
+	for _, name := range []string{"a.tar.gz", "b.tar.gz"} {
+		snapshot, err := b.Backup("mysiteid", filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Backup() is expected to read every page of items: %+v", err)
+		}
+		data, _ := ioutil.ReadFile(snapshot)
+		archives = append(archives, data)
+	}
+	if string(archives[0]) != string(archives[1]) {
+		t.Error("Backup() is expected to write reproducible archives.")
+	}
+
+	// Collections with more pages than MaxPages are not silently truncated.
+	b.MaxPages = 0
+	if _, err := b.Backup("mysiteid", filepath.Join(dir, "c.tar.gz")); err == nil {
+		t.Error("Backup() is expected to error when it cannot read every item.")
+	}
+}
@@ -0,0 +1,87 @@
+package webflowAPI
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// Sort asking for the most recently updated items first.
+const changedSort = "-updated-on"
+
+// ItemsChangedSince The raw JSON of the collection's items created or updated after the given time. Every page is read
+// unless sorted is set, when the items are requested most recently updated first and paging stops at the first page
+// holding an older item. Webflow does not document sorting items, so only set sorted when the API is known to sort
+// them; once items are seen out of order, every page is read.
+func ItemsChangedSince(api Interface, collectionID string, since time.Time, sorted bool) ([][]byte, error) {
+	items := [][]byte{}
+	inOrder := true
+	var previous time.Time
+
+	offset := 0
+	for page := 0; page <= changesMaxPages; page++ {
+		queryParams := map[string]string{
+			"offset": strconv.Itoa(offset),
+			"limit":  strconv.Itoa(DefaultPageLimit),
+		}
+		if sorted {
+			queryParams["sort"] = changedSort
+		}
+
+		collectionItems := &CollectionItems{}
+		if err := api.MethodGet(fmt.Sprintf(listCollectionItemsURL, collectionID), queryParams, collectionItems); err != nil {
+			return nil, err
+		}
+
+		older := false
+		gjson.ParseBytes(collectionItems.Items).ForEach(func(key, value gjson.Result) bool {
+			updated := value.Get("updated-on").Time()
+			created := value.Get("created-on").Time()
+
+			if updated.After(since) || created.After(since) {
+				items = append(items, []byte(value.Raw))
+			} else {
+				older = true
+			}
+
+			// Stop early only while the items really are newest first.
+			if !previous.IsZero() && updated.After(previous) {
+				inOrder = false
+			}
+			previous = updated
+
+			return true
+		})
+
+		offset = collectionItems.Offset + collectionItems.Count
+		if offset >= collectionItems.Total || collectionItems.Count == 0 || sorted && inOrder && older {
+			break
+		}
+	}
+
+	return items, nil
+}
+
+// DeletedItemIDs Which of the known item IDs, e.g. those of an earlier sync, are no longer in the collection.
+func DeletedItemIDs(api Interface, collectionID string, knownIDs []string) ([]string, error) {
+	items, err := api.GetAllItemsInCollectionByID(collectionID, changesMaxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]bool{}
+	for _, item := range items {
+		current[gjson.GetBytes(item, "_id").String()] = true
+	}
+
+	deleted := []string{}
+	for _, id := range knownIDs {
+		if !current[id] {
+			deleted = append(deleted, id)
+		}
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+// metadataField Item metadata declared by every generated struct, in order.
+type metadataField struct {
+	name, slug, goType, doc string
+}
+
+var (
+	metadataFields = []metadataField{
+		{"ID", "_id,omitempty", "string", "ID of the item; empty until created."},
+		{"CollectionID", "_cid,omitempty", "string", "ID of the item's collection."},
+		{"Archived", "_archived", "bool", "Whether the item is archived."},
+		{"Draft", "_draft", "bool", "Whether the item is a draft, so not published."},
+		{"CreatedOn", "created-on,omitempty", "*time.Time", ""},
+		{"CreatedBy", "created-by,omitempty", "string", ""},
+		{"UpdatedOn", "updated-on,omitempty", "*time.Time", ""},
+		{"UpdatedBy", "updated-by,omitempty", "string", ""},
+		{"PublishedOn", "published-on,omitempty", "*time.Time", ""},
+		{"PublishedBy", "published-by,omitempty", "string", ""},
+	}
+
+	// Words written in upper case in Go identifiers.
+	initialisms = map[string]bool{"api": true, "html": true, "http": true, "id": true, "seo": true, "url": true, "uri": true}
+)
+
+// generator Writes the Go source for a set of collections.
+type generator struct {
+	buf bytes.Buffer
+	// slugs Collection slugs by ID, to describe references.
+	slugs map[string]string
+	// usesAPI Whether a type of the webflowAPI pkg is used, so it must be imported.
+	usesAPI bool
+}
+
+// generate Go source declaring a struct for the items of each collection, with constants for its ID & options.
+func generate(pkg, source string, collections []webflowAPI.Collection) ([]byte, error) {
+	g := &generator{slugs: map[string]string{}}
+	for _, collection := range collections {
+		g.slugs[collection.ID] = collection.Slug
+	}
+
+	sorted := append([]webflowAPI.Collection{}, collections...)
+	sort.SliceStable(sorted, func(i, j int) bool { return structName(sorted[i]) < structName(sorted[j]) })
+
+	body := &bytes.Buffer{}
+	for _, collection := range sorted {
+		g.buf.Reset()
+		g.collection(collection)
+		body.Write(g.buf.Bytes())
+	}
+
+	src := &bytes.Buffer{}
+	fmt.Fprintf(src, "// Code generated by webflow-gen from %s; DO NOT EDIT.\n\npackage %s\n\n", source, pkg)
+	if g.usesAPI {
+		fmt.Fprint(src, "import (\n\t\"time\"\n\n\t\"github.com/redeemed2011/webflowAPI\"\n)\n")
+	} else {
+		fmt.Fprint(src, "import \"time\"\n")
+	}
+	src.Write(body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format the generated source; error: %+v", err)
+	}
+
+	return formatted, nil
+}
+
+// collection Write the constants & struct of a collection.
+func (g *generator) collection(collection webflowAPI.Collection) {
+	name := structName(collection)
+
+	fmt.Fprintf(&g.buf, "\n// %sCollectionID ID of the %s collection.\n", name, collection.Name)
+	fmt.Fprintf(&g.buf, "const %sCollectionID = %q\n", name, collection.ID)
+
+	used := map[string]bool{}
+	for _, meta := range metadataFields {
+		used[meta.name] = true
+	}
+
+	type field struct {
+		name string
+		def  webflowAPI.CollectionField
+	}
+	fields := []field{}
+	for _, def := range collection.Fields {
+		if isMetadata(def.Slug) {
+			continue
+		}
+		fieldName := goName(def.Slug)
+		if used[fieldName] {
+			fieldName += "Field"
+		}
+		for i := 2; used[fieldName]; i++ {
+			fieldName = fmt.Sprintf("%s%d", goName(def.Slug), i)
+		}
+		used[fieldName] = true
+		fields = append(fields, field{fieldName, def})
+	}
+
+	for _, f := range fields {
+		if f.def.Type != webflowAPI.FieldTypeOption || f.def.Validations == nil || len(f.def.Validations.Options) == 0 {
+			continue
+		}
+		fmt.Fprintf(&g.buf, "\n// Options of %s.%s.\nconst (\n", name, f.name)
+		consts := map[string]bool{}
+		for _, option := range f.def.Validations.Options {
+			constName := name + f.name + goName(option.Name)
+			for i := 2; consts[constName]; i++ {
+				constName = fmt.Sprintf("%s%s%s%d", name, f.name, goName(option.Name), i)
+			}
+			consts[constName] = true
+			fmt.Fprintf(&g.buf, "\t%s = %q\n", constName, option.ID)
+		}
+		fmt.Fprint(&g.buf, ")\n")
+	}
+
+	fmt.Fprintf(&g.buf, "\n// %s Item of the %s collection (%s).\ntype %s struct {\n", name, collection.Name, collection.Slug, name)
+	for _, meta := range metadataFields {
+		if meta.doc != "" {
+			fmt.Fprintf(&g.buf, "\t// %s %s\n", meta.name, meta.doc)
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s `json:\"%s\"`\n", meta.name, meta.goType, meta.slug)
+	}
+	fmt.Fprint(&g.buf, "\n")
+	for _, f := range fields {
+		goType := g.goType(f.def)
+		tag := f.def.Slug
+		if !f.def.Required {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.buf, "\t// %s %s\n", f.name, g.describe(f.def))
+		fmt.Fprintf(&g.buf, "\t%s %s `json:\"%s\"`\n", f.name, goType, tag)
+	}
+	fmt.Fprint(&g.buf, "}\n")
+}
+
+// goType The Go type holding a field's value. Optional dates, images & files are pointers so they may be left out.
+func (g *generator) goType(def webflowAPI.CollectionField) string {
+	optional := ""
+	if !def.Required {
+		optional = "*"
+	}
+
+	switch def.Type {
+	case webflowAPI.FieldTypeNumber:
+		if def.Validations != nil && def.Validations.DecimalPlaces != nil && *def.Validations.DecimalPlaces == 0 {
+			return "int64"
+		}
+		return "float64"
+	case webflowAPI.FieldTypeBool:
+		return "bool"
+	case webflowAPI.FieldTypeDate:
+		return optional + "time.Time"
+	case webflowAPI.FieldTypeImageRef:
+		g.usesAPI = true
+		return optional + "webflowAPI.Image"
+	case webflowAPI.FieldTypeExtFileRef:
+		g.usesAPI = true
+		return optional + "webflowAPI.File"
+	case webflowAPI.FieldTypeSet:
+		g.usesAPI = true
+		return "[]webflowAPI.Image"
+	case webflowAPI.FieldTypeItemRefSet:
+		return "[]string"
+	}
+
+	// Text, links, options, references & users are strings.
+	return "string"
+}
+
+// describe The doc comment of a field: its type & name, what its value holds, then its help text.
+func (g *generator) describe(def webflowAPI.CollectionField) string {
+	doc := fmt.Sprintf("%s field %q", def.Type, def.Name)
+
+	collection := ""
+	if def.Validations != nil && def.Validations.CollectionID != "" {
+		collection = def.Validations.CollectionID
+		if slug := g.slugs[collection]; slug != "" {
+			collection = slug
+		}
+	}
+
+	switch def.Type {
+	case webflowAPI.FieldTypeItemRef:
+		doc += fmt.Sprintf("; the ID of an item of %s", collection)
+	case webflowAPI.FieldTypeItemRefSet:
+		doc += fmt.Sprintf("; the IDs of items of %s", collection)
+	case webflowAPI.FieldTypeOption:
+		doc += "; the ID of the chosen option"
+	case webflowAPI.FieldTypeRichText:
+		doc += "; HTML"
+	}
+	doc += "."
+
+	if help := strings.Join(strings.Fields(def.HelpText), " "); help != "" {
+		doc += " " + help
+		if !strings.HasSuffix(help, ".") {
+			doc += "."
+		}
+	}
+
+	return doc
+}
+
+// structName Go name of a collection's items: its singular name, or its name.
+func structName(collection webflowAPI.Collection) string {
+	name := goName(collection.SingularName)
+	if name == "" {
+		name = goName(collection.Name)
+	}
+	if name == "" {
+		name = "Collection" + goName(collection.ID)
+	}
+
+	return name
+}
+
+// goName An exported Go identifier from a slug or name, e.g. "post-body" is PostBody & "image-url" is ImageURL.
+func goName(s string) string {
+	words := strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+
+	name := ""
+	for _, word := range words {
+		if initialisms[strings.ToLower(word)] {
+			name += strings.ToUpper(word)
+			continue
+		}
+		runes := []rune(word)
+		name += string(unicode.ToUpper(runes[0])) + string(runes[1:])
+	}
+
+	if name != "" && !unicode.IsLetter([]rune(name)[0]) {
+		name = "F" + name
+	}
+
+	return name
+}
+
+// isMetadata Whether a schema field is item metadata, declared by every struct.
+func isMetadata(slug string) bool {
+	for _, meta := range metadataFields {
+		if strings.SplitN(meta.slug, ",", 2)[0] == slug {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,148 @@
+// Command webflow-gen Generate Go structs for the items of a site's collections, so models never drift from the CMS.
+// Each collection gets a struct with the item metadata & a typed field per schema field, with JSON tags & doc comments,
+// plus constants for its ID & the IDs of its options. Use it with go generate, like the moq directive of webflowAPI:
+//
+//	//go:generate webflow-gen -schema schema.json -out models.go
+//	//go:generate webflow-gen -collections posts,authors -out models.go
+//
+// The schema is read from the saved schema file (see schema.Load) or, without one, from the API using the token & site
+// ID in the flags or the WEBFLOW_TOKEN & WEBFLOW_SITE_ID environment variables. The package defaults to $GOPACKAGE,
+// which go generate sets.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/schema"
+)
+
+const usage = `usage: webflow-gen [flags]
+
+Generate Go structs for the items of a site's collections.
+
+flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv, os.Stdout, os.Stderr))
+}
+
+// run Execute the command line, returning the exit status.
+func run(args []string, getenv func(string) string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("webflow-gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	schemaFile := fs.String("schema", "", "Saved schema file to read rather than the API.")
+	token := fs.String("token", "", "API token. Defaults to $WEBFLOW_TOKEN.")
+	siteID := fs.String("site", "", "Site ID. Defaults to $WEBFLOW_SITE_ID.")
+	baseURL := fs.String("base-url", "", "API base URL.")
+	only := fs.String("collections", "", "Comma separated slugs, names or IDs of the collections to generate. Defaults to all.")
+	pkg := fs.String("package", getenv("GOPACKAGE"), "Package of the generated file. Defaults to $GOPACKAGE.")
+	out := fs.String("out", "", "File to write. Defaults to stdout.")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *pkg == "" {
+		fmt.Fprintln(stderr, "error: no package; use -package or run from go generate")
+		return 2
+	}
+
+	source := *schemaFile
+	var collections []webflowAPI.Collection
+	var err error
+	if source != "" {
+		collections, err = schema.Load(source)
+	} else {
+		source = "the Webflow API"
+		collections, err = fetch(firstOf(*token, getenv("WEBFLOW_TOKEN")), firstOf(*siteID, getenv("WEBFLOW_SITE_ID")), *baseURL)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	if collections, err = filter(collections, *only); err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	src, err := generate(*pkg, source, collections)
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	if *out == "" {
+		_, err = stdout.Write(src)
+	} else {
+		err = ioutil.WriteFile(*out, src, 0644)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// fetch Read the site's collections through the API.
+func fetch(token, siteID, baseURL string) ([]webflowAPI.Collection, error) {
+	if token == "" || siteID == "" {
+		return nil, errors.New("no schema file, API token or site ID; use -schema, or -token & -site")
+	}
+
+	profile := &webflowAPI.Profile{Token: token, SiteID: siteID, BaseURL: baseURL}
+	api, err := profile.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.Fetch(api)
+}
+
+// filter The collections named in the comma separated list, by slug, name or ID, in the list's order; all when empty.
+func filter(collections []webflowAPI.Collection, list string) ([]webflowAPI.Collection, error) {
+	if list == "" {
+		return collections, nil
+	}
+
+	filtered := []webflowAPI.Collection{}
+	for _, ref := range strings.Split(list, ",") {
+		ref = strings.TrimSpace(ref)
+		found := false
+		for _, collection := range collections {
+			if collection.Slug == ref || collection.Name == ref || collection.ID == ref {
+				filtered = append(filtered, collection)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("collection '%s' not found", ref)
+		}
+	}
+
+	return filtered, nil
+}
+
+// firstOf The first non-empty value.
+func firstOf(vals ...string) string {
+	for _, val := range vals {
+		if val != "" {
+			return val
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/redeemed2011/webflowAPI/schema"
+	"github.com/redeemed2011/webflowAPI/webflowtest"
+)
+
+// runGen Run the generator, returning the exit status, stdout & stderr.
+func runGen(env map[string]string, args ...string) (int, string, string) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	status := run(args, func(key string) string { return env[key] }, stdout, stderr)
+
+	return status, stdout.String(), stderr.String()
+}
+
+func TestGenerate(t *testing.T) {
+	status, stdout, stderr := runGen(map[string]string{"GOPACKAGE": "models"}, "-schema", "testdata/schema.json")
+	if status != 0 {
+		t.Fatalf("webflow-gen is expected to generate the structs! Got %d; %s", status, stderr)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "models.go", stdout, 0); err != nil {
+		t.Errorf("webflow-gen is expected to generate valid Go: %+v", err)
+	}
+
+	for _, want := range []string{
+		"// Code generated by webflow-gen from testdata/schema.json; DO NOT EDIT.",
+		"package models",
+		"type BlogPost struct {",
+		"ID string `json:\"_id,omitempty\"`",
+		"PostBody string `json:\"post-body,omitempty\"`",
+		"Views int64 `json:\"views,omitempty\"`",
+		"Date time.Time `json:\"date\"`",
+		"Photo *webflowAPI.Image `json:\"photo,omitempty\"`",
+		"Tags []string `json:\"tags,omitempty\"`",
+		"CanonicalURL string `json:\"canonical-url,omitempty\"`",
+		"IDField string `json:\"id,omitempty\"`",
+		"// Author ItemRef field \"Author\"; the ID of an item of authors.",
+		"BlogPostStatusInReview = \"o1\"",
+		"const BlogPostCollectionID = \"posts1\"",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("webflow-gen is expected to generate %q! Got:\n%s", want, stdout)
+		}
+	}
+	if strings.Count(stdout, "`json:\"_archived\"`") != 2 {
+		t.Errorf("webflow-gen is expected to declare metadata schema fields once! Got:\n%s", stdout)
+	}
+}
+
+func TestGenerateFromAPI(t *testing.T) {
+	collections, err := schema.Load("testdata/schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixture := &webflowtest.Fixture{Sites: []webflowtest.SiteFixture{{}}}
+	fixture.Sites[0].ID = "mysiteid"
+	for _, collection := range collections {
+		fixture.Sites[0].Collections = append(fixture.Sites[0].Collections, webflowtest.CollectionFixture{Collection: collection})
+	}
+	server := webflowtest.NewServer(fixture)
+	defer server.Close()
+
+	env := map[string]string{"WEBFLOW_TOKEN": "mytoken", "WEBFLOW_SITE_ID": "mysiteid"}
+	status, stdout, stderr := runGen(env, "-base-url", server.URL, "-package", "cms", "-collections", "authors")
+	if status != 0 || !strings.Contains(stdout, "type Author struct") || strings.Contains(stdout, "BlogPost") {
+		t.Errorf("webflow-gen is expected to generate the chosen collections from the API! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	if status, _, _ := runGen(env, "-base-url", server.URL, "-package", "cms", "-collections", "nope"); status != 1 {
+		t.Errorf("webflow-gen is expected to fail for unknown collections! Got %d.", status)
+	}
+	if status, _, _ := runGen(env, "-schema", "testdata/schema.json"); status != 2 {
+		t.Errorf("webflow-gen is expected to require a package! Got %d.", status)
+	}
+}
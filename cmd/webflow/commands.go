@@ -0,0 +1,1337 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/backup"
+	"github.com/redeemed2011/webflowAPI/diff"
+	"github.com/redeemed2011/webflowAPI/expand"
+	"github.com/redeemed2011/webflowAPI/export"
+	"github.com/redeemed2011/webflowAPI/importer"
+	"github.com/redeemed2011/webflowAPI/promote"
+	"github.com/redeemed2011/webflowAPI/reconcile"
+	"github.com/redeemed2011/webflowAPI/schedule"
+	"github.com/redeemed2011/webflowAPI/schema"
+	"github.com/redeemed2011/webflowAPI/watch"
+	"github.com/redeemed2011/webflowAPI/webhook/queue"
+	"github.com/tidwall/gjson"
+)
+
+// stringsFlag Flag that may be repeated.
+type stringsFlag []string
+
+var _ flag.Value = &stringsFlag{}
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(val string) error {
+	*s = append(*s, val)
+	return nil
+}
+
+// sitesList List the sites the token has access to.
+func (c *cli) sitesList(args []string) error {
+	if _, err := parseArgs(c.newFlagSet("sites list"), args); err != nil {
+		return err
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	sites, err := api.GetAllSites()
+	if err != nil {
+		return err
+	}
+
+	records, err := toRecords(sites)
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(records, siteColumns)
+}
+
+// collectionsList List the site's collections.
+func (c *cli) collectionsList(args []string) error {
+	if _, err := parseArgs(c.newFlagSet("collections list"), args); err != nil {
+		return err
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collections, err := api.GetAllCollections()
+	if err != nil {
+		return err
+	}
+
+	records, err := toRecords(collections)
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(records, collectionColumns)
+}
+
+// collectionsShow Show a collection and its fields.
+func (c *cli) collectionsShow(args []string) error {
+	positional, err := parseArgs(c.newFlagSet("collections show"), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: collections show <collection>")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	// The collections list does not include the fields.
+	if collection, err = api.GetCollectionByID(collection.ID); err != nil {
+		return err
+	}
+
+	record, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+
+	fields, err := toRecords(collection.Fields)
+	if err != nil {
+		return err
+	}
+
+	return c.out.one(record, fields, fieldColumns)
+}
+
+// itemsList List all the items in a collection.
+func (c *cli) itemsList(args []string) error {
+	fs := c.newFlagSet("items list")
+	maxPages := fs.Int("max-pages", 10, "Maximum number of additional pages of 100 items to request.")
+	options := webflowAPI.ListOptions{}
+	fs.StringVar(&options.Drafts, "drafts", webflowAPI.IncludeItems, "Drafts to list: exclude or only. Defaults to including them.")
+	fs.StringVar(&options.Archived, "archived", webflowAPI.IncludeItems, "Archived items to list: exclude or only. Defaults to including them.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || !validState(options.Drafts) || !validState(options.Archived) {
+		return errors.New("usage: items list <collection> [--max-pages N] [--drafts exclude|only] [--archived exclude|only]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	items, err := webflowAPI.ListItems(api, collection.ID, *maxPages, options)
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(items, itemColumns)
+}
+
+// validState Whether the choice of items in a state is known.
+func validState(choice string) bool {
+	return choice == webflowAPI.IncludeItems || choice == webflowAPI.ExcludeItems || choice == webflowAPI.OnlyItems
+}
+
+// itemsGet Show a single item found by ID or name.
+func (c *cli) itemsGet(args []string) error {
+	fs := c.newFlagSet("items get")
+	expandFields := &stringsFlag{}
+	fs.Var(expandFields, "expand", "Reference field to replace with the referenced item, e.g. author.company. May be repeated.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return errors.New("usage: items get <collection> <item ID or name> [--expand FIELD]...")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	item, err := api.GetItem("", "", collection.ID, "", positional[1])
+	if err == nil && item == nil {
+		item, err = api.GetItem("", "", collection.ID, positional[1], "")
+	}
+	if err != nil {
+		return err
+	}
+	if item == nil {
+		return fmt.Errorf("item '%s' not found in collection '%s'", positional[1], collection.Slug)
+	}
+
+	if len(*expandFields) > 0 {
+		if item, err = expand.New(api).Expand(item, *expandFields...); err != nil {
+			return err
+		}
+	}
+
+	return c.out.one(item, [][]byte{item}, itemColumns)
+}
+
+// itemsCreate Create an item from JSON fields.
+func (c *cli) itemsCreate(args []string) error {
+	fs := c.newFlagSet("items create")
+	data := fs.String("data", "", "Item fields as a JSON object.")
+	file := fs.String("file", "", "File containing the item fields as a JSON object; - for stdin.")
+	live := fs.Bool("live", false, "Publish the item immediately.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: items create <collection> (--data JSON | --file PATH) [--live]")
+	}
+
+	fields, err := c.readFields(*data, *file)
+	if err != nil {
+		return err
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	item, err := api.CreateItem(collection.ID, fields, *live)
+	if err != nil {
+		return err
+	}
+
+	return c.out.one(item, [][]byte{item}, itemColumns)
+}
+
+// itemsUpsert Update the item with the same key field as the JSON fields, or create it when there is none.
+func (c *cli) itemsUpsert(args []string) error {
+	fs := c.newFlagSet("items upsert")
+	key := fs.String("key", webflowAPI.DefaultUpsertKey, "Unique field matching the existing item, e.g. slug or an external ID.")
+	data := fs.String("data", "", "Item fields as a JSON object.")
+	file := fs.String("file", "", "File containing the item fields as a JSON object; - for stdin.")
+	live := fs.Bool("live", false, "Publish the item immediately.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: items upsert <collection> (--data JSON | --file PATH) [--key FIELD] [--live]")
+	}
+
+	fields, err := c.readFields(*data, *file)
+	if err != nil {
+		return err
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	action, item, err := webflowAPI.UpsertItem(api, collection.ID, *key, fields, *live)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "%s item\n", action)
+
+	return c.out.one(item, [][]byte{item}, itemColumns)
+}
+
+// itemsUpdate Replace, or patch, an item's fields.
+func (c *cli) itemsUpdate(args []string) error {
+	fs := c.newFlagSet("items update")
+	data := fs.String("data", "", "Item fields as a JSON object.")
+	file := fs.String("file", "", "File containing the item fields as a JSON object; - for stdin.")
+	patch := fs.Bool("patch", false, "Only change the given fields rather than replacing them all.")
+	live := fs.Bool("live", false, "Publish the change immediately.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return errors.New("usage: items update <collection> <item ID> (--data JSON | --file PATH) [--patch] [--live]")
+	}
+
+	fields, err := c.readFields(*data, *file)
+	if err != nil {
+		return err
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	var item []byte
+	if *patch {
+		item, err = api.PatchItem(collection.ID, positional[1], fields, *live)
+	} else {
+		item, err = api.UpdateItem(collection.ID, positional[1], fields, *live)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.out.one(item, [][]byte{item}, itemColumns)
+}
+
+// itemsState Archive, unarchive, mark as drafts or ready, or publish items: the subcommand.
+func (c *cli) itemsState(subcommand string, args []string) error {
+	fs := c.newFlagSet("items " + subcommand)
+	ready := fs.Bool("ready", false, "Mark the items as ready to publish rather than as drafts.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 2 {
+		return fmt.Errorf("usage: items %s <collection> <item ID>...", subcommand)
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+	ids := positional[1:]
+
+	if subcommand == "publish" {
+		res, err := api.PublishItems(collection.ID, ids)
+		if err != nil {
+			return err
+		}
+		for _, msg := range res.Errors {
+			fmt.Fprintln(c.stderr, "error:", msg)
+		}
+		fmt.Fprintf(c.stderr, "published %d of %d item(s)\n", len(res.PublishedItemIDs), len(ids))
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("%d item(s) could not be published", len(res.Errors))
+		}
+		return nil
+	}
+
+	items := [][]byte{}
+	for _, id := range ids {
+		var item []byte
+		switch subcommand {
+		case "archive":
+			item, err = webflowAPI.ArchiveItem(api, collection.ID, id)
+		case "unarchive":
+			item, err = webflowAPI.UnarchiveItem(api, collection.ID, id)
+		default:
+			item, err = webflowAPI.SetDraft(api, collection.ID, id, !*ready)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %+v", id, err)
+		}
+		items = append(items, item)
+	}
+
+	return c.out.list(items, itemColumns)
+}
+
+// itemsDelete Remove an item.
+func (c *cli) itemsDelete(args []string) error {
+	positional, err := parseArgs(c.newFlagSet("items delete"), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return errors.New("usage: items delete <collection> <item ID>")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	if err := api.DeleteItem(collection.ID, positional[1]); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "deleted item %s\n", positional[1])
+	return nil
+}
+
+// publish Publish the site.
+func (c *cli) publish(args []string) error {
+	fs := c.newFlagSet("publish")
+	domains := &stringsFlag{}
+	fs.Var(domains, "domain", "Domain to publish to. May be repeated.")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	if err := api.PublishSite(*domains); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(c.stderr, "publish queued")
+	return nil
+}
+
+// export Write all of a collection's items to stdout or a file. With a checkpoint file, an NDJSON export to a file
+// saves its position after every page, and running the same command again after a failure continues from it.
+func (c *cli) export(args []string) error {
+	fs := c.newFlagSet("export")
+	format := fs.String("format", export.FormatNDJSON, "Export format: ndjson, json or csv.")
+	file := fs.String("file", "", "File to write to rather than stdout.")
+	checkpoint := fs.String("checkpoint", "", "File saving the position after every page, to resume an interrupted NDJSON export to --file.")
+	maxPages := fs.Int("max-pages", export.DefaultMaxPages, "Maximum number of additional pages of 100 items to request.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || *checkpoint != "" && (*file == "" || *format != export.FormatNDJSON) {
+		return errors.New("usage: export <collection> [--format ndjson|json|csv] [--file PATH [--checkpoint PATH]]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	exporter := export.New(api)
+	exporter.MaxPages = *maxPages
+
+	position := export.Position{Cursor: webflowAPI.NewCursor(collection.ID)}
+	resume := false
+	if *checkpoint != "" {
+		saved, err := export.LoadPosition(*checkpoint)
+		switch {
+		case err == nil && saved.CollectionID != collection.ID:
+			return fmt.Errorf("the checkpoint %s is of another collection, %s", *checkpoint, saved.CollectionID)
+		case err == nil:
+			position, resume = *saved, true
+			fmt.Fprintf(c.stderr, "resuming from item %d of %d\n", position.Offset, position.Total)
+		case !os.IsNotExist(err):
+			return err
+		}
+		exporter.Checkpoint = func(reached export.Position) error {
+			position = reached
+			return position.Save(*checkpoint)
+		}
+	}
+
+	w := c.stdout
+	if *file != "" {
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if resume {
+			flags = os.O_WRONLY | os.O_APPEND
+		}
+		f, err := os.OpenFile(*file, flags, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		// Drop any page only partly written before the interruption.
+		if resume {
+			if err := f.Truncate(position.Bytes); err != nil {
+				return err
+			}
+		}
+		w = f
+	}
+
+	var n int
+	if *checkpoint != "" {
+		n, err = exporter.Resume(w, position)
+	} else {
+		n, err = exporter.Export(w, collection.ID, *format)
+	}
+	if err != nil {
+		if *checkpoint != "" {
+			return fmt.Errorf("%+v; run the same command again to resume", err)
+		}
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "exported %d item(s)\n", n)
+	if *checkpoint != "" {
+		if !position.Done() {
+			fmt.Fprintf(c.stderr, "stopped at item %d of %d; run the same command again to continue\n", position.Offset, position.Total)
+			return nil
+		}
+		return os.Remove(*checkpoint)
+	}
+
+	return nil
+}
+
+// importItems Create or update items from a CSV or NDJSON file.
+func (c *cli) importItems(args []string) error {
+	fs := c.newFlagSet("import")
+	file := fs.String("file", "", "CSV or NDJSON file to import; - for stdin.")
+	format := fs.String("format", "", "Import format: csv or ndjson. Defaults to the file's extension.")
+	mapping := fs.String("mapping", "", "YAML file mapping columns to field slugs.")
+	dryRun := fs.Bool("dry-run", false, "Report the changes without making them.")
+	live := fs.Bool("live", false, "Publish the items immediately.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || *file == "" {
+		return errors.New("usage: import <collection> --file PATH [--format csv|ndjson] [--mapping PATH] [--dry-run] [--live]")
+	}
+
+	if *format == "" {
+		*format = importer.FormatCSV
+		if strings.HasSuffix(*file, ".ndjson") || strings.HasSuffix(*file, ".jsonl") {
+			*format = importer.FormatNDJSON
+		}
+	}
+
+	r := c.stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	im := importer.New(api)
+	im.DryRun = *dryRun
+	im.Live = *live
+	if *mapping != "" {
+		if im.Mapping, err = importer.LoadMapping(*mapping); err != nil {
+			return err
+		}
+	}
+
+	report, err := im.Import(r, collection.ID, *format)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Ignored) > 0 {
+		fmt.Fprintf(c.stderr, "ignored unmapped columns: %s\n", strings.Join(report.Ignored, ", "))
+	}
+
+	records, err := toRecords(report.Results)
+	if err != nil {
+		return err
+	}
+	if err := c.out.list(records, importColumns); err != nil {
+		return err
+	}
+
+	if failed := report.Count(importer.ActionError); failed > 0 {
+		return fmt.Errorf("%d of %d record(s) failed", failed, len(report.Results))
+	}
+
+	return nil
+}
+
+// backup Snapshot the site's collections & items.
+func (c *cli) backup(args []string) error {
+	positional, err := parseArgs(c.newFlagSet("backup"), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: backup <dir or file.tar.gz>")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := backup.New(api).Backup(c.siteID, positional[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "backed up to %s\n", snapshot)
+	return nil
+}
+
+// restore Recreate the items of a backup in the site.
+func (c *cli) restore(args []string) error {
+	fs := c.newFlagSet("restore")
+	live := fs.Bool("live", false, "Publish the restored items immediately.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: restore <backup> [--live]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	b := backup.New(api)
+	b.Live = *live
+
+	result, err := b.Restore(positional[0])
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range result.Errors {
+		fmt.Fprintln(c.stderr, "error:", msg)
+	}
+	fmt.Fprintf(c.stderr, "created %d & updated %d item(s)\n", result.Created, result.Updated)
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d item(s) could not be restored", len(result.Errors))
+	}
+
+	return nil
+}
+
+// diff Compare a collection's items to those of a backup or export file.
+func (c *cli) diff(args []string) error {
+	fs := c.newFlagSet("diff")
+	against := fs.String("against", "", "Backup directory or archive, or NDJSON or JSON export, to compare to.")
+	key := fs.String("key", diff.DefaultKey, "Field matching the items, e.g. slug or _id.")
+	format := fs.String("format", diff.FormatText, "Diff format: text, json or unified.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || *against == "" {
+		return errors.New("usage: diff <collection> --against PATH [--key FIELD] [--format text|json|unified]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	// Exports are compared by item ID, as they hold no referenced items; backups, possibly of another site, by the
+	// referenced items' keys.
+	var before [][]byte
+	var refs *diff.References
+	switch filepath.Ext(*against) {
+	case ".ndjson", ".jsonl", ".json":
+		f, err := os.Open(*against)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		before, err = diff.ReadItems(f)
+	default:
+		if before, err = backup.ReadItems(*against, collection.Slug); err == nil {
+			refs, err = diffReferences(api, collection, *against, *key)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	after, err := api.GetAllItemsInCollectionByID(collection.ID, backup.DefaultMaxPages)
+	if err != nil {
+		return err
+	}
+
+	result, err := diff.DiffReferences(before, after, *key, refs)
+	if err != nil {
+		return err
+	}
+
+	return result.Render(c.stdout, *format)
+}
+
+// diffReferences The collection's reference fields & the keys, falling back to slugs, of the items they may reference
+// in the backup & the live site.
+func diffReferences(api webflowAPI.Interface, collection *webflowAPI.Collection, against, key string) (*diff.References, error) {
+	refs := &diff.References{Fields: map[string]bool{}, Before: map[string]string{}, After: map[string]string{}}
+	read := map[string]bool{}
+
+	// The collections list does not include the fields.
+	collection, err := api.GetCollectionByID(collection.ID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, errors.New("collection not found")
+	}
+
+	for _, field := range collection.Fields {
+		if field.Type != webflowAPI.FieldTypeItemRef && field.Type != webflowAPI.FieldTypeItemRefSet {
+			continue
+		}
+		if field.Validations == nil || field.Validations.CollectionID == "" {
+			continue
+		}
+		refs.Fields[field.Slug] = true
+
+		referenced := field.Validations.CollectionID
+		if read[referenced] {
+			continue
+		}
+		read[referenced] = true
+
+		live, err := api.GetAllItemsInCollectionByID(referenced, backup.DefaultMaxPages)
+		if err != nil {
+			return nil, err
+		}
+		keyItems(refs.After, live, key)
+
+		info, err := api.GetCollectionByID(referenced)
+		if err != nil {
+			return nil, err
+		}
+		// Collections missing from the backup leave their references compared by ID.
+		if info != nil {
+			if backedUp, err := backup.ReadItems(against, info.Slug); err == nil {
+				keyItems(refs.Before, backedUp, key)
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+// keyItems Record the items' keys, falling back to slugs, by item ID.
+func keyItems(keys map[string]string, items [][]byte, key string) {
+	for _, item := range items {
+		k := gjson.GetBytes(item, key).String()
+		if k == "" {
+			k = gjson.GetBytes(item, "slug").String()
+		}
+		keys[gjson.GetBytes(item, "_id").String()] = k
+	}
+}
+
+// watch Poll a collection, printing each change as a line of JSON, until interrupted.
+func (c *cli) watch(args []string) error {
+	fs := c.newFlagSet("watch")
+	interval := fs.Duration("interval", time.Minute, "Time between polls.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: watch <collection> [--interval DURATION]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collection, err := resolveCollection(api, positional[0])
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	enc := json.NewEncoder(c.stdout)
+	for event := range watch.New(api).Watch(ctx, collection.ID, *interval) {
+		if event.Type == watch.Error {
+			fmt.Fprintln(c.stderr, "error:", event.Err)
+			continue
+		}
+		if err := enc.Encode(event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// schedule Publish & archive items when their date fields say, once or until interrupted, printing each action taken.
+func (c *cli) schedule(args []string) error {
+	fs := c.newFlagSet("schedule")
+	record := fs.String("record", "webflow-schedule.json", "File recording the actions taken, so restarts do not repeat them.")
+	publishField := fs.String("publish-field", schedule.DefaultPublishField, "Date field holding when to publish an item.")
+	expireField := fs.String("expire-field", schedule.DefaultExpireField, "Date field holding when to archive an item.")
+	interval := fs.Duration("interval", schedule.DefaultInterval, "Time between passes.")
+	once := fs.Bool("once", false, "Make a single pass, e.g. from cron, rather than running until interrupted.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("usage: schedule <collection>... [--record PATH] [--publish-field FIELD] [--expire-field FIELD] [--interval DURATION] [--once]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	ids := []string{}
+	for _, ref := range positional {
+		collection, err := resolveCollection(api, ref)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, collection.ID)
+	}
+
+	s, err := schedule.New(api, *record)
+	if err != nil {
+		return err
+	}
+	s.PublishField = *publishField
+	s.ExpireField = *expireField
+	s.Interval = *interval
+
+	enc := json.NewEncoder(c.stdout)
+	failed := 0
+	report := func(r *schedule.Report) {
+		for _, entry := range r.Done {
+			enc.Encode(entry)
+		}
+		for _, failure := range r.Failed {
+			fmt.Fprintf(c.stderr, "error: %s %s: %s\n", failure.Entry.Action, failure.Entry.ItemID, failure.Error)
+		}
+		failed = len(r.Failed)
+	}
+
+	if *once {
+		r, err := s.RunOnce(ids)
+		if err != nil {
+			return err
+		}
+		report(r)
+		if failed > 0 {
+			return fmt.Errorf("%d action(s) failed", failed)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err := s.Run(ctx, ids, report); err != nil && err != context.Canceled {
+		return err
+	}
+
+	return nil
+}
+
+// syncPlan Print the plan bringing collections to their desired states, applying it when asked.
+func (c *cli) syncPlan(args []string, apply bool) error {
+	fs := c.newFlagSet("sync")
+	live := fs.Bool("live", false, "Publish created & updated items immediately.")
+	rate := fs.Int("rate", reconcile.DefaultRequestsPerMinute, "Maximum writes per minute; 0 for no limit.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("usage: sync plan|apply <desired file>... [--live] [--rate N]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	r := reconcile.New(api)
+	r.Live = *live
+	r.RequestsPerMinute = *rate
+
+	failed := 0
+	for _, path := range positional {
+		desired, err := reconcile.LoadDesired(path)
+		if err != nil {
+			return err
+		}
+
+		collection, err := resolveCollection(api, desired.Collection)
+		if err != nil {
+			return fmt.Errorf("%s: %+v", path, err)
+		}
+
+		plan, err := r.Plan(collection.ID, desired)
+		if err != nil {
+			return fmt.Errorf("%s: %+v", path, err)
+		}
+
+		fmt.Fprintf(c.stdout, "# %s (%s)\n", path, collection.Slug)
+		if err := plan.Render(c.stdout); err != nil {
+			return err
+		}
+
+		if !apply || plan.Empty() {
+			continue
+		}
+
+		report := r.Apply(plan)
+		for _, failure := range report.Failed {
+			fmt.Fprintf(c.stderr, "error: %s %s: %s\n", failure.Action.Kind, failure.Action.Key, failure.Error)
+		}
+		fmt.Fprintf(c.stderr, "applied %d of %d action(s) to %s\n", len(report.Applied), len(plan.Actions), collection.Slug)
+		failed += len(report.Failed)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d action(s) failed", failed)
+	}
+
+	return nil
+}
+
+// lint Validate the items of desired state files against their collections' schemas without writing them.
+func (c *cli) lint(args []string) error {
+	fs := c.newFlagSet("lint")
+	partial := fs.Bool("partial", false, "Items only give the fields they manage, so required fields may be left out.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return errors.New("usage: lint <desired file>... [--partial]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	v := schema.NewValidator(api)
+
+	problems := 0
+	for _, path := range positional {
+		desired, err := reconcile.LoadDesired(path)
+		if err != nil {
+			return err
+		}
+
+		collection, err := resolveCollection(api, desired.Collection)
+		if err != nil {
+			return fmt.Errorf("%s: %+v", path, err)
+		}
+
+		for i, item := range desired.Items {
+			errs, err := v.Validate(collection.ID, item, *partial)
+			if err != nil {
+				return fmt.Errorf("%s: %+v", path, err)
+			}
+			for _, fieldErr := range errs {
+				fmt.Fprintf(c.stdout, "%s: item %d (%v): %s\n", path, i+1, item["slug"], fieldErr)
+			}
+			problems += len(errs)
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("%d problem(s) found", problems)
+	}
+
+	return nil
+}
+
+// schemaDiff Compare the site's schema to a saved schema file or another profile's site, failing when they differ so
+// drift can be caught in CI.
+func (c *cli) schemaDiff(args []string) error {
+	fs := c.newFlagSet("schema diff")
+	against := fs.String("against", "", "Saved schema file to compare to, e.g. written by schema dump.")
+	againstProfile := fs.String("against-profile", "", "Profile of the site to compare to.")
+	format := fs.String("format", schema.FormatText, "Diff format: text or json.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || (*against == "") == (*againstProfile == "") {
+		return errors.New("usage: schema diff (--against PATH | --against-profile NAME) [--format text|json]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	var before []webflowAPI.Collection
+	if *against != "" {
+		before, err = schema.Load(*against)
+	} else {
+		var other webflowAPI.Interface
+		if other, err = c.profileAPI(*againstProfile); err == nil {
+			before, err = schema.Fetch(other)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	after, err := schema.Fetch(api)
+	if err != nil {
+		return err
+	}
+
+	cmp := schema.Compare(before, after)
+	if err := cmp.Render(c.stdout, *format); err != nil {
+		return err
+	}
+
+	if !cmp.Empty() {
+		return fmt.Errorf("the schemas differ by %d change(s)", len(cmp.Changes))
+	}
+
+	return nil
+}
+
+// schemaDump Write the schemas of all the collections, as saved by the API or as JSON Schema.
+func (c *cli) schemaDump(args []string) error {
+	fs := c.newFlagSet("schema dump")
+	format := fs.String("format", "json", "Schema format: json, readable by webflow-gen & schema diff, or jsonschema.")
+	file := fs.String("file", "", "File to write to rather than stdout.")
+	dir := fs.String("dir", "", "Directory to write a <slug>.json file per collection to rather than one document.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || (*format != "json" && *format != "jsonschema") {
+		return errors.New("usage: schema dump [--format json|jsonschema] [--file PATH | --dir DIR]")
+	}
+
+	api, err := c.api()
+	if err != nil {
+		return err
+	}
+
+	collections, err := schema.Fetch(api)
+	if err != nil {
+		return err
+	}
+
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0755); err != nil {
+			return err
+		}
+		for i := range collections {
+			var doc interface{} = collections[i]
+			if *format == "jsonschema" {
+				doc = schema.JSONSchema(&collections[i])
+			}
+			data, err := json.MarshalIndent(doc, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(filepath.Join(*dir, collections[i].Slug+".json"), append(data, '\n'), 0644); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(c.stderr, "wrote %d schema(s) to %s\n", len(collections), *dir)
+		return nil
+	}
+
+	w := c.stdout
+	if *file != "" {
+		f, err := os.Create(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "json" {
+		return schema.Write(w, collections)
+	}
+
+	data, err := json.MarshalIndent(schema.JSONSchemas(collections), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// promote Print the plan copying items from the site to another profile's site, e.g. staging to production, applying
+// it when asked.
+func (c *cli) promote(args []string, apply bool) error {
+	fs := c.newFlagSet("promote")
+	to := fs.String("to-profile", "", "Profile of the site to promote the items to.")
+	live := fs.Bool("live", false, "Publish created & updated items immediately.")
+	published := fs.Bool("published", false, "Only promote items that are neither drafts nor archived.")
+	since := fs.String("since", "", "Only promote items created or updated after this RFC 3339 time.")
+	slugs := stringsFlag{}
+	fs.Var(&slugs, "slug", "Only promote the item with this slug. May be repeated.")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if *to == "" {
+		return errors.New("usage: promote plan|apply --to-profile NAME [collection]... [--slug SLUG]... [--since TIME] [--published] [--live]")
+	}
+
+	filters := []promote.Filter{}
+	if *published {
+		filters = append(filters, promote.Published)
+	}
+	if *since != "" {
+		t, err := time.Parse(time.RFC3339, *since)
+		if err != nil {
+			return fmt.Errorf("invalid --since time; error: %+v", err)
+		}
+		filters = append(filters, promote.ChangedSince(t))
+	}
+	if len(slugs) > 0 {
+		filters = append(filters, promote.Slugs(slugs...))
+	}
+
+	src, err := c.api()
+	if err != nil {
+		return err
+	}
+	dst, err := c.profileAPI(*to)
+	if err != nil {
+		return err
+	}
+
+	p := promote.New(src, dst)
+	p.Live = *live
+
+	plan, err := p.Plan(positional, func(collection string, item map[string]interface{}) bool {
+		for _, filter := range filters {
+			if !filter(collection, item) {
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := plan.Render(c.stdout); err != nil {
+		return err
+	}
+	if !apply || plan.Empty() {
+		return nil
+	}
+
+	report := p.Apply(plan)
+	for _, failure := range report.Failed {
+		fmt.Fprintf(c.stderr, "error: %s %s/%s: %s\n", failure.Action.Kind, failure.Action.Collection, failure.Action.Key, failure.Error)
+	}
+	fmt.Fprintf(c.stderr, "applied %d of %d action(s) to %s\n", len(report.Applied), len(plan.Actions), *to)
+
+	if len(report.Failed) > 0 {
+		return fmt.Errorf("%d action(s) failed", len(report.Failed))
+	}
+
+	return nil
+}
+
+// queueDead List the events in a webhook queue's dead-letter storage.
+func (c *cli) queueDead(args []string) error {
+	positional, err := parseArgs(c.newFlagSet("queue dead"), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: queue dead <dir>")
+	}
+
+	q, err := queue.New(positional[0])
+	if err != nil {
+		return err
+	}
+
+	dead, err := q.Dead()
+	if err != nil {
+		return err
+	}
+
+	records, err := toRecords(dead)
+	if err != nil {
+		return err
+	}
+
+	return c.out.list(records, []column{
+		{"ID", "id"},
+		{"TRIGGER", "event.triggerType"},
+		{"ATTEMPTS", "attempts"},
+		{"LAST ERROR", "lastError"},
+	})
+}
+
+// queueReplay Move dead-lettered webhook events back to the pending queue.
+func (c *cli) queueReplay(args []string) error {
+	positional, err := parseArgs(c.newFlagSet("queue replay"), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 1 {
+		return errors.New("usage: queue replay <dir> [event ID]...")
+	}
+
+	q, err := queue.New(positional[0])
+	if err != nil {
+		return err
+	}
+
+	replayed, err := q.Replay(positional[1:]...)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "replayed %d event(s)\n", replayed)
+	return nil
+}
+
+// readFields Read the item fields from --data or --file.
+func (c *cli) readFields(data, file string) (json.RawMessage, error) {
+	raw := []byte(data)
+
+	if file != "" {
+		var err error
+		if file == "-" {
+			raw, err = ioutil.ReadAll(c.stdin)
+		} else {
+			raw, err = ioutil.ReadFile(file)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("item fields must be a JSON object given by --data or --file; error: %+v", err)
+	}
+
+	return raw, nil
+}
+
+// resolveCollection Find a collection by slug, name or ID.
+func resolveCollection(api webflowAPI.Interface, ref string) (*webflowAPI.Collection, error) {
+	collection, err := api.GetCollectionBySlug(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if collection == nil {
+		if collection, err = api.GetCollectionByName(ref); err != nil {
+			return nil, err
+		}
+	}
+
+	if collection == nil {
+		collections, err := api.GetAllCollections()
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range *collections {
+			if c.ID == ref {
+				return &c, nil
+			}
+		}
+		return nil, fmt.Errorf("collection '%s' not found", ref)
+	}
+
+	return collection, nil
+}
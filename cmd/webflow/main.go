@@ -0,0 +1,294 @@
+// Command webflow Everyday Webflow CMS operations from the command line.
+//
+//	webflow [global flags] <command> <subcommand> [flags] [args]
+//
+//	webflow sites list
+//	webflow collections list
+//	webflow collections show <collection>
+//	webflow items list <collection> [--drafts exclude|only] [--archived exclude|only]
+//	webflow items get <collection> <item ID or name> [--expand author]...
+//	webflow items create <collection> --data '{"name":"Hi","slug":"hi"}' [--live]
+//	webflow items update <collection> <item ID> --file item.json [--patch] [--live]
+//	webflow items upsert <collection> --data '{"name":"Hi","slug":"hi"}' [--key slug] [--live]
+//	webflow items delete <collection> <item ID>
+//	webflow items archive|unarchive <collection> <item ID>...
+//	webflow items draft <collection> <item ID>... [--ready]
+//	webflow items publish <collection> <item ID>...
+//	webflow publish [--domain example.com]...
+//	webflow export <collection> [--format csv] [--file posts.csv]
+//	webflow export <collection> --file posts.ndjson --checkpoint posts.cursor
+//	webflow import <collection> --file posts.csv [--mapping mapping.yaml] [--dry-run] [--live]
+//	webflow backup <dir or file.tar.gz>
+//	webflow restore <backup> [--live]
+//	webflow diff <collection> --against <backup or export file> [--key slug] [--format text|json|unified]
+//	webflow watch <collection> [--interval 1m]
+//	webflow schedule <collection>... [--publish-field publish-at] [--expire-field expire-at] [--once]
+//	webflow sync plan <desired.yaml>...
+//	webflow sync apply <desired.yaml>... [--live] [--rate 60]
+//	webflow lint <desired.yaml>... [--partial]
+//	webflow schema dump [--format json|jsonschema] [--file schema.json | --dir schemas]
+//	webflow schema diff (--against schema.json | --against-profile production) [--format text|json]
+//	webflow promote plan --to-profile production [collection]... [--slug hello]... [--since 2020-01-02T00:00:00Z]
+//	webflow promote apply --to-profile production [collection]... [--published] [--live]
+//	webflow queue dead <dir>
+//	webflow queue replay <dir> [event ID]...
+//
+// Collections may be given by slug, name or ID. The token & site ID are read from the flags, the WEBFLOW_TOKEN &
+// WEBFLOW_SITE_ID environment variables or the profile, in that order. Profiles are defined in
+// ~/.config/webflow/config.yaml; see webflowAPI.Config.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+const (
+	usage = `usage: webflow [global flags] <command> <subcommand> [flags] [args]
+
+commands:
+  sites list
+  collections list
+  collections show <collection>
+  items list <collection> [--max-pages N] [--drafts exclude|only] [--archived exclude|only]
+  items get <collection> <item ID or name> [--expand FIELD]...
+  items create <collection> (--data JSON | --file PATH) [--live]
+  items update <collection> <item ID> (--data JSON | --file PATH) [--patch] [--live]
+  items upsert <collection> (--data JSON | --file PATH) [--key FIELD] [--live]
+  items delete <collection> <item ID>
+  items archive|unarchive <collection> <item ID>...
+  items draft <collection> <item ID>... [--ready]
+  items publish <collection> <item ID>...
+  publish [--domain DOMAIN]...
+  export <collection> [--format ndjson|json|csv] [--file PATH [--checkpoint PATH]]
+  import <collection> --file PATH [--format csv|ndjson] [--mapping PATH] [--dry-run] [--live]
+  backup <dir or file.tar.gz>
+  restore <backup> [--live]
+  diff <collection> --against PATH [--key FIELD] [--format text|json|unified]
+  watch <collection> [--interval DURATION]
+  schedule <collection>... [--record PATH] [--publish-field FIELD] [--expire-field FIELD] [--interval DURATION] [--once]
+  sync plan <desired file>...
+  sync apply <desired file>... [--live] [--rate N]
+  lint <desired file>... [--partial]
+  schema dump [--format json|jsonschema] [--file PATH | --dir DIR]
+  schema diff (--against PATH | --against-profile NAME) [--format text|json]
+  promote plan|apply --to-profile NAME [collection]... [--slug SLUG]... [--since TIME] [--published] [--live]
+  queue dead <dir>
+  queue replay <dir> [event ID]...
+
+global flags:
+`
+)
+
+// plainCommands Commands without a subcommand; their first arg is their own.
+var plainCommands = map[string]bool{
+	"publish": true, "export": true, "import": true, "backup": true, "restore": true, "diff": true, "watch": true,
+	"schedule": true, "lint": true,
+}
+
+// cli State shared by the commands.
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	token, siteID, baseURL, profile, configFile string
+	out                                         *printer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv, os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run Execute the command line, returning the exit status.
+func run(args []string, getenv func(string) string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &cli{stdin: stdin, stdout: stdout, stderr: stderr, getenv: getenv}
+
+	fs := flag.NewFlagSet("webflow", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&c.token, "token", "", "API token. Defaults to $WEBFLOW_TOKEN.")
+	fs.StringVar(&c.siteID, "site", "", "Site ID. Defaults to $WEBFLOW_SITE_ID.")
+	fs.StringVar(&c.baseURL, "base-url", "", "API base URL.")
+	fs.StringVar(&c.profile, "profile", "", "Profile of the config file to use. Defaults to $WEBFLOW_PROFILE.")
+	fs.StringVar(&c.configFile, "config", webflowAPI.DefaultConfigPath(), "YAML file defining the profiles.")
+	output := fs.String("output", outputTable, "Output format: table, json, ndjson or yaml.")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	var err error
+	if c.out, err = newPrinter(stdout, *output); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	command, rest := fs.Arg(0), fs.Args()[1:]
+	if len(rest) > 0 && !plainCommands[command] {
+		command, rest = command+" "+rest[0], rest[1:]
+	}
+
+	switch command {
+	case "sites list":
+		err = c.sitesList(rest)
+	case "collections list":
+		err = c.collectionsList(rest)
+	case "collections show":
+		err = c.collectionsShow(rest)
+	case "items list":
+		err = c.itemsList(rest)
+	case "items get":
+		err = c.itemsGet(rest)
+	case "items create":
+		err = c.itemsCreate(rest)
+	case "items update":
+		err = c.itemsUpdate(rest)
+	case "items upsert":
+		err = c.itemsUpsert(rest)
+	case "items delete":
+		err = c.itemsDelete(rest)
+	case "items archive", "items unarchive", "items draft", "items publish":
+		err = c.itemsState(strings.TrimPrefix(command, "items "), rest)
+	case "sync plan":
+		err = c.syncPlan(rest, false)
+	case "sync apply":
+		err = c.syncPlan(rest, true)
+	case "schema dump":
+		err = c.schemaDump(rest)
+	case "schema diff":
+		err = c.schemaDiff(rest)
+	case "promote plan":
+		err = c.promote(rest, false)
+	case "promote apply":
+		err = c.promote(rest, true)
+	case "queue dead":
+		err = c.queueDead(rest)
+	case "queue replay":
+		err = c.queueReplay(rest)
+	case "publish":
+		err = c.publish(rest)
+	case "export":
+		err = c.export(rest)
+	case "import":
+		err = c.importItems(rest)
+	case "backup":
+		err = c.backup(rest)
+	case "restore":
+		err = c.restore(rest)
+	case "diff":
+		err = c.diff(rest)
+	case "watch":
+		err = c.watch(rest)
+	case "schedule":
+		err = c.schedule(rest)
+	case "lint":
+		err = c.lint(rest)
+	default:
+		fmt.Fprintf(stderr, "unknown command '%s'\n", command)
+		fs.Usage()
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, "error:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// api Create the API client from the flags, the environment & the profile.
+func (c *cli) api() (webflowAPI.Interface, error) {
+	name := firstOf(c.profile, c.getenv(webflowAPI.ProfileEnv))
+
+	// The config file & profile are optional unless one was named.
+	profile := &webflowAPI.Profile{}
+	config, err := webflowAPI.LoadConfig(c.configFile)
+	if err == nil {
+		if profile, err = config.Profile(name); err != nil && name == "" {
+			profile, err = &webflowAPI.Profile{}, nil
+		}
+	} else if os.IsNotExist(err) && name == "" {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if token := firstOf(c.token, c.getenv("WEBFLOW_TOKEN")); token != "" {
+		profile.Token = token
+	} else if profile.Token == "" && profile.TokenEnv == "" && profile.TokenFile == "" {
+		return nil, errors.New("no API token; use --token, $WEBFLOW_TOKEN or a profile")
+	}
+	profile.SiteID = firstOf(c.siteID, c.getenv("WEBFLOW_SITE_ID"), profile.SiteID)
+	c.siteID = profile.SiteID
+	profile.BaseURL = firstOf(c.baseURL, profile.BaseURL)
+
+	return profile.New(nil)
+}
+
+// profileAPI Create an API client for another site from its profile alone, e.g. production when comparing it to the
+// staging site of the flags.
+func (c *cli) profileAPI(name string) (webflowAPI.Interface, error) {
+	config, err := webflowAPI.LoadConfig(c.configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := config.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile.New(nil)
+}
+
+// firstOf The first non-empty value.
+func firstOf(vals ...string) string {
+	for _, val := range vals {
+		if val != "" {
+			return val
+		}
+	}
+
+	return ""
+}
+
+// parseArgs Parse flags that may be interleaved with positional args, returning the positional args.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// newFlagSet Create a flag set for a subcommand that reports errors rather than exiting.
+func (c *cli) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
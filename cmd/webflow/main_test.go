@@ -0,0 +1,499 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/export"
+	"github.com/redeemed2011/webflowAPI/webflowtest"
+	"github.com/redeemed2011/webflowAPI/webhook"
+	"github.com/redeemed2011/webflowAPI/webhook/queue"
+)
+
+const (
+	siteID = "mysiteid"
+)
+
+var (
+	exampleFixture = &webflowtest.Fixture{Sites: []webflowtest.SiteFixture{{
+		Site: webflowAPI.Site{ID: siteID, Name: "My Site"},
+		Collections: []webflowtest.CollectionFixture{{
+			Collection: webflowAPI.Collection{
+				ID:   "1",
+				Name: "Dogs",
+				Slug: "dogs",
+				Fields: []webflowAPI.CollectionField{
+					{ID: "f1", Name: "Name", Slug: "name", Type: webflowAPI.FieldTypePlainText, Required: true},
+					{ID: "f2", Name: "Slug", Slug: "slug", Type: webflowAPI.FieldTypePlainText, Required: true},
+				},
+			},
+			Items: []json.RawMessage{
+				json.RawMessage(`{"_id":"d1","name":"blue","slug":"blue"}`),
+				json.RawMessage(`{"_id":"d2","name":"green","slug":"green"}`),
+			},
+		}},
+	}}}
+)
+
+// runCLI Run the command line against the server, returning the exit status, stdout & stderr.
+func runCLI(server *webflowtest.Server, args ...string) (int, string, string) {
+	env := map[string]string{
+		"WEBFLOW_TOKEN":   "mytoken",
+		"WEBFLOW_SITE_ID": siteID,
+		"HOME":            "/nonexistent",
+	}
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	args = append([]string{"--base-url", server.URL, "--config", "/nonexistent/config.yaml"}, args...)
+	status := run(args, func(key string) string { return env[key] }, strings.NewReader(""), stdout, stderr)
+
+	return status, stdout.String(), stderr.String()
+}
+
+func TestRead(t *testing.T) {
+	server := webflowtest.NewServer(exampleFixture)
+	defer server.Close()
+
+	{
+		status, stdout, stderr := runCLI(server, "collections", "list")
+		if status != 0 || !strings.Contains(stdout, "SLUG") || !strings.Contains(stdout, "dogs") {
+			t.Errorf("collections list is expected to print a table of collections! Got %d; %s%s", status, stdout, stderr)
+		}
+	}
+	{
+		status, stdout, stderr := runCLI(server, "--output", "json", "collections", "show", "Dogs")
+		collection := &webflowAPI.Collection{}
+		if err := json.Unmarshal([]byte(stdout), collection); status != 0 || err != nil || len(collection.Fields) != 2 {
+			t.Errorf("collections show is expected to print the collection with its fields! Got %d; %s%s", status, stdout, stderr)
+		}
+	}
+	{
+		status, stdout, stderr := runCLI(server, "--output", "ndjson", "items", "list", "dogs")
+		if lines := strings.Split(strings.TrimSpace(stdout), "\n"); status != 0 || len(lines) != 2 {
+			t.Errorf("items list is expected to print one item per line! Got %d; %s%s", status, stdout, stderr)
+		}
+	}
+	{
+		status, stdout, stderr := runCLI(server, "--output", "yaml", "items", "get", "dogs", "green")
+		if status != 0 || !strings.Contains(stdout, "_id: d2") {
+			t.Errorf("items get is expected to find the item by name and print YAML! Got %d; %s%s", status, stdout, stderr)
+		}
+	}
+	{
+		status, _, stderr := runCLI(server, "items", "list", "birds")
+		if status != 1 || !strings.Contains(stderr, "not found") {
+			t.Errorf("items list is expected to fail for an unknown collection! Got %d; %s", status, stderr)
+		}
+	}
+	{
+		status, _, _ := runCLI(server, "--output", "xml", "sites", "list")
+		if status != 2 {
+			t.Errorf("An unknown output format is expected to be a usage error! Got %d.", status)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	server := webflowtest.NewServer(exampleFixture)
+	defer server.Close()
+
+	status, stdout, stderr := runCLI(server, "--output", "json", "items", "create", "dogs", "--data", `{"name":"red","slug":"red"}`, "--live")
+	item := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(stdout), &item); status != 0 || err != nil || item["_id"] == nil {
+		t.Fatalf("items create is expected to print the created item! Got %d; %s%s", status, stdout, stderr)
+	}
+	id := item["_id"].(string)
+
+	status, stdout, stderr = runCLI(server, "--output", "json", "items", "update", "dogs", id, "--patch", "--data", `{"name":"maroon"}`)
+	if status != 0 || !strings.Contains(stdout, "maroon") {
+		t.Errorf("items update --patch is expected to print the updated item! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	status, stdout, stderr = runCLI(server, "--output", "json", "items", "upsert", "dogs", "--data", `{"name":"red","slug":"red"}`)
+	if status != 0 || !strings.Contains(stderr, "updated item") || !strings.Contains(stdout, id) {
+		t.Errorf("items upsert is expected to update the item with the slug! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	status, _, stderr = runCLI(server, "items", "delete", "dogs", id)
+	if status != 0 || len(server.Items("1")) != 2 {
+		t.Errorf("items delete is expected to remove the item! Got %d; %s", status, stderr)
+	}
+
+	status, _, stderr = runCLI(server, "items", "upsert", "dogs", "--key", "name", "--data", `{"name":"blue","slug":"navy"}`)
+	if status != 0 || !strings.Contains(stderr, "updated item") || len(server.Items("1")) != 2 {
+		t.Errorf("items upsert --key is expected to match the item by the key! Got %d; %s", status, stderr)
+	}
+	runCLI(server, "items", "upsert", "dogs", "--key", "name", "--data", `{"name":"blue","slug":"blue"}`)
+
+	status, stdout, stderr = runCLI(server, "export", "dogs", "--format", "csv")
+	if status != 0 || !strings.HasPrefix(stdout, "_id,name,slug") || strings.Count(stdout, "\n") != 3 {
+		t.Errorf("export is expected to write the items as CSV! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	status, stdout, stderr = runCLI(server, "import", "dogs", "--file", "testdata/dogs.csv", "--dry-run")
+	if status != 0 || strings.Count(stdout, "create") != 1 || strings.Count(stdout, "update") != 1 || len(server.Items("1")) != 2 {
+		t.Errorf("import --dry-run is expected to report the changes without making them! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	status, _, stderr = runCLI(server, "import", "dogs", "--file", "testdata/dogs.csv")
+	if status != 0 || len(server.Items("1")) != 3 {
+		t.Errorf("import is expected to create the new item! Got %d; %s", status, stderr)
+	}
+
+	if status, _, stderr := runCLI(server, "items", "archive", "dogs", "d1"); status != 0 {
+		t.Errorf("items archive is expected to archive the item! Got %d; %s", status, stderr)
+	}
+	runCLI(server, "items", "draft", "dogs", "d2")
+	status, stdout, stderr = runCLI(server, "--output", "ndjson", "items", "list", "dogs", "--drafts", "exclude", "--archived", "exclude")
+	if status != 0 || strings.Count(stdout, "\n") != 1 || strings.Contains(stdout, `"d1"`) || strings.Contains(stdout, `"d2"`) {
+		t.Errorf("items list is expected to leave out drafts & archived items! Got %d; %s%s", status, stdout, stderr)
+	}
+	if status, _, stderr := runCLI(server, "items", "publish", "dogs", "d1", "d2"); status != 1 || !strings.Contains(stderr, "item d2 is a draft") {
+		t.Errorf("items publish is expected to fail for drafts! Got %d; %s", status, stderr)
+	}
+	runCLI(server, "items", "unarchive", "dogs", "d1")
+	runCLI(server, "items", "draft", "dogs", "d2", "--ready")
+	if status, stdout, _ := runCLI(server, "--output", "ndjson", "items", "list", "dogs", "--drafts", "only"); status != 0 || stdout != "" {
+		t.Errorf("items draft --ready is expected to clear the draft flag! Got %d; %s", status, stdout)
+	}
+
+	status, _, stderr = runCLI(server, "publish", "--domain", "example.com")
+	if status != 0 || server.Published(siteID) != 1 {
+		t.Errorf("publish is expected to publish the site! Got %d; %s", status, stderr)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	server := webflowtest.NewServer(exampleFixture)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "webflow-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := filepath.Join(dir, "config.yaml")
+	ioutil.WriteFile(config, []byte(fmt.Sprintf("profiles:\n  staging:\n    token: mytoken\n    siteId: %s\n    baseUrl: %s\n", siteID, server.URL)), 0600)
+
+	runProfile := func(env map[string]string, args ...string) (int, string) {
+		stderr := &bytes.Buffer{}
+		args = append([]string{"--config", config}, args...)
+		status := run(args, func(key string) string { return env[key] }, strings.NewReader(""), ioutil.Discard, stderr)
+		return status, stderr.String()
+	}
+
+	if status, stderr := runProfile(nil, "--profile", "staging", "collections", "list"); status != 0 {
+		t.Errorf("--profile is expected to supply the token, site & base URL! Got %d; %s", status, stderr)
+	}
+	if status, stderr := runProfile(map[string]string{"WEBFLOW_PROFILE": "staging"}, "collections", "list"); status != 0 {
+		t.Errorf("$WEBFLOW_PROFILE is expected to choose the profile! Got %d; %s", status, stderr)
+	}
+	if status, stderr := runProfile(nil, "--profile", "production", "collections", "list"); status != 1 || !strings.Contains(stderr, "production") {
+		t.Errorf("An undefined profile is expected to be an error! Got %d; %s", status, stderr)
+	}
+}
+
+func TestBackup(t *testing.T) {
+	server := webflowtest.NewServer(exampleFixture)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "webflow-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive := filepath.Join(dir, "site.tar.gz")
+	if status, _, stderr := runCLI(server, "backup", archive); status != 0 {
+		t.Fatalf("backup is expected to write the archive! Got %d; %s", status, stderr)
+	}
+
+	runCLI(server, "items", "create", "dogs", "--data", `{"name":"red","slug":"red"}`)
+	status, stdout, stderr := runCLI(server, "diff", "dogs", "--against", archive)
+	if status != 0 || !strings.Contains(stdout, "+ red") || !strings.Contains(stdout, "1 added, 0 removed, 0 changed") {
+		t.Errorf("diff is expected to report the item added since the backup! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	if status, _, stderr := runCLI(server, "restore", archive); status != 0 || !strings.Contains(stderr, "updated 2") {
+		t.Errorf("restore is expected to update the backed up items! Got %d; %s", status, stderr)
+	}
+}
+
+func TestSync(t *testing.T) {
+	server := webflowtest.NewServer(exampleFixture)
+	defer server.Close()
+
+	status, stdout, stderr := runCLI(server, "sync", "plan", "testdata/dogs.yaml")
+	if status != 0 || !strings.Contains(stdout, "Plan: 1 to create, 1 to update, 1 to delete.") || len(server.Items("1")) != 2 {
+		t.Errorf("sync plan is expected to print the plan without applying it! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	status, _, stderr = runCLI(server, "sync", "apply", "--rate", "0", "testdata/dogs.yaml")
+	if status != 0 || len(server.Items("1")) != 2 {
+		t.Errorf("sync apply is expected to apply the plan! Got %d; %s", status, stderr)
+	}
+
+	status, stdout, _ = runCLI(server, "sync", "plan", "testdata/dogs.yaml")
+	if status != 0 || !strings.Contains(stdout, "Plan: 0 to create, 0 to update, 0 to delete.") {
+		t.Errorf("sync plan is expected to be empty once applied! Got %d; %s", status, stdout)
+	}
+
+	status, _, stderr = runCLI(server, "lint", "testdata/dogs.yaml")
+	if status != 0 {
+		t.Errorf("lint is expected to pass valid content! Got %d; %s", status, stderr)
+	}
+
+	status, stdout, _ = runCLI(server, "lint", "testdata/bad-dogs.yaml")
+	if status != 1 || !strings.Contains(stdout, "item 1 (Red Dog): slug:") || !strings.Contains(stdout, "item 2 (spot): colour:") || !strings.Contains(stdout, "item 2 (spot): name:") {
+		t.Errorf("lint is expected to report the invalid fields! Got %d; %s", status, stdout)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	server := webflowtest.NewServer(exampleFixture)
+	defer server.Close()
+
+	status, stdout, stderr := runCLI(server, "schema", "dump", "--format", "jsonschema")
+	if status != 0 || !strings.Contains(stdout, `"$schema": "https://json-schema.org/draft/2020-12/schema"`) || !strings.Contains(stdout, `"$id": "dogs.json"`) {
+		t.Errorf("schema dump is expected to print the JSON Schemas! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	dir, err := ioutil.TempDir("", "webflow-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if status, _, stderr := runCLI(server, "schema", "dump", "--dir", dir); status != 0 {
+		t.Fatalf("schema dump is expected to write a file per collection! Got %d; %s", status, stderr)
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "dogs.json")); !strings.Contains(string(data), `"slug": "dogs"`) {
+		t.Errorf("schema dump is expected to write the collection's schema! Got %s.", data)
+	}
+
+	saved := filepath.Join(dir, "schema.json")
+	if status, _, stderr := runCLI(server, "schema", "dump", "--file", saved); status != 0 {
+		t.Fatalf("schema dump is expected to save the schema! Got %d; %s", status, stderr)
+	}
+	if status, stdout, stderr := runCLI(server, "schema", "diff", "--against", saved); status != 0 || !strings.Contains(stdout, "Schema: 0 added, 0 removed, 0 changed.") {
+		t.Errorf("schema diff is expected to pass when the schema is unchanged! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	data, _ := ioutil.ReadFile(saved)
+	ioutil.WriteFile(saved, bytes.Replace(data, []byte(`"required": true`), []byte(`"required": false`), 1), 0644)
+	if status, stdout, _ := runCLI(server, "schema", "diff", "--against", saved); status != 1 || !strings.Contains(stdout, "~ field dogs.name: required false -> true") {
+		t.Errorf("schema diff is expected to fail when the schema drifted! Got %d; %s", status, stdout)
+	}
+
+	config := filepath.Join(dir, "config.yaml")
+	ioutil.WriteFile(config, []byte(fmt.Sprintf("profiles:\n  production:\n    token: mytoken\n    siteId: %s\n    baseUrl: %s\n", siteID, server.URL)), 0600)
+	if status, _, stderr := runCLI(server, "--config", config, "schema", "diff", "--against-profile", "production"); status != 0 {
+		t.Errorf("schema diff is expected to compare to another profile's site! Got %d; %s", status, stderr)
+	}
+}
+
+func TestPromote(t *testing.T) {
+	server := webflowtest.NewServer(exampleFixture)
+	defer server.Close()
+
+	production := webflowtest.NewServer(&webflowtest.Fixture{Sites: []webflowtest.SiteFixture{{
+		Site: webflowAPI.Site{ID: "productionsiteid", Name: "Production"},
+		Collections: []webflowtest.CollectionFixture{{
+			Collection: webflowAPI.Collection{
+				ID:     "2",
+				Name:   "Dogs",
+				Slug:   "dogs",
+				Fields: exampleFixture.Sites[0].Collections[0].Fields,
+			},
+			Items: []json.RawMessage{json.RawMessage(`{"_id":"p1","name":"navy","slug":"blue"}`)},
+		}},
+	}}})
+	defer production.Close()
+
+	dir, err := ioutil.TempDir("", "webflow-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := filepath.Join(dir, "config.yaml")
+	ioutil.WriteFile(config, []byte(fmt.Sprintf("profiles:\n  production:\n    token: mytoken\n    siteId: productionsiteid\n    baseUrl: %s\n", production.URL)), 0600)
+
+	status, stdout, stderr := runCLI(server, "--config", config, "promote", "plan", "--to-profile", "production", "dogs")
+	if status != 0 || !strings.Contains(stdout, "~ update blue\n    name: \"navy\" -> \"blue\"\n+ create green\n") {
+		t.Errorf("promote plan is expected to print the plan! Got %d; %s%s", status, stdout, stderr)
+	}
+	if len(production.Items("2")) != 1 {
+		t.Error("promote plan is expected not to write anything!")
+	}
+
+	if status, _, stderr := runCLI(server, "--config", config, "promote", "apply", "--to-profile", "production", "--slug", "green"); status != 0 {
+		t.Fatalf("promote apply is expected to succeed! Got %d; %s", status, stderr)
+	}
+	if items := production.Items("2"); len(items) != 2 || !strings.Contains(string(items[0]), `"navy"`) {
+		t.Errorf("promote apply is expected to create only green! Got %s", items)
+	}
+
+	if status, _, _ := runCLI(server, "promote", "plan"); status != 1 {
+		t.Errorf("promote is expected to require --to-profile! Got %d", status)
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	server := webflowtest.NewServer(&webflowtest.Fixture{Sites: []webflowtest.SiteFixture{{
+		Site: exampleFixture.Sites[0].Site,
+		Collections: []webflowtest.CollectionFixture{{
+			Collection: exampleFixture.Sites[0].Collections[0].Collection,
+			Items: []json.RawMessage{
+				json.RawMessage(`{"_id":"d1","_draft":true,"name":"blue","slug":"blue","publish-at":"2020-01-01T00:00:00.000Z"}`),
+				json.RawMessage(`{"_id":"d2","name":"green","slug":"green","expire-at":"2020-01-01T00:00:00.000Z"}`),
+				json.RawMessage(`{"_id":"d3","name":"red","slug":"red","expire-at":"2999-01-01T00:00:00.000Z"}`),
+			},
+		}},
+	}}})
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "webflow-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	record := filepath.Join(dir, "schedule.json")
+
+	status, stdout, stderr := runCLI(server, "schedule", "dogs", "--once", "--record", record)
+	if status != 0 || strings.Count(stdout, "\n") != 2 || !strings.Contains(stdout, `"action":"publish","collectionId":"1","itemId":"d1"`) ||
+		!strings.Contains(stdout, `"action":"expire","collectionId":"1","itemId":"d2"`) {
+		t.Errorf("schedule --once is expected to publish d1 & archive d2! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	if status, stdout, _ := runCLI(server, "schedule", "dogs", "--once", "--record", record); status != 0 || stdout != "" {
+		t.Errorf("schedule is expected not to repeat actions! Got %d; %s", status, stdout)
+	}
+	if status, _, _ := runCLI(server, "schedule", "--once"); status != 1 {
+		t.Errorf("schedule is expected to require a collection! Got %d", status)
+	}
+}
+
+func TestExportCheckpoint(t *testing.T) {
+	server := webflowtest.NewServer(exampleFixture)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "webflow-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out, checkpoint := filepath.Join(dir, "dogs.ndjson"), filepath.Join(dir, "dogs.cursor")
+
+	// An export interrupted after the first item, part way through writing the second.
+	first := `{"_id":"d1","name":"blue","slug":"blue"}` + "\n"
+	ioutil.WriteFile(out, []byte(first+`{"_id":"d2","na`), 0644)
+	export.Position{Cursor: webflowAPI.Cursor{CollectionID: "1", Offset: 1, Total: 2}, Bytes: int64(len(first))}.Save(checkpoint)
+
+	status, _, stderr := runCLI(server, "export", "dogs", "--file", out, "--checkpoint", checkpoint)
+	data, _ := ioutil.ReadFile(out)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if status != 0 || !strings.Contains(stderr, "resuming from item 1 of 2") || len(lines) != 2 || !strings.HasPrefix(lines[1], `{"_id":"d2","name":"green"`) {
+		t.Errorf("export --checkpoint is expected to drop the partly written item & append the remaining items! Got %d; %s%s", status, data, stderr)
+	}
+	if _, err := os.Stat(checkpoint); !os.IsNotExist(err) {
+		t.Errorf("export --checkpoint is expected to remove the checkpoint once done! Got %+v", err)
+	}
+
+	status, _, stderr = runCLI(server, "export", "dogs", "--file", out, "--checkpoint", checkpoint)
+	if data, _ := ioutil.ReadFile(out); status != 0 || strings.Count(string(data), "\n") != 2 {
+		t.Errorf("export --checkpoint is expected to start over without a checkpoint! Got %d; %s%s", status, data, stderr)
+	}
+
+	if status, _, _ := runCLI(server, "export", "dogs", "--checkpoint", checkpoint); status != 1 {
+		t.Errorf("export --checkpoint is expected to require --file! Got %d", status)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	server := webflowtest.NewServer(exampleFixture)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "webflow-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// An event that failed its only attempt.
+	q, err := queue.New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.MaxAttempts = 1
+	q.Enqueue(webhook.Event{TriggerType: webhook.TriggerItemChanged, Payload: []byte(`{"_id":"d1"}`)})
+	q.Process(func(webhook.Event) error { return errors.New("handler down") })
+
+	dead, _ := q.Dead()
+	if len(dead) != 1 {
+		t.Fatalf("Process() is expected to dead-letter the event! Got %+v", dead)
+	}
+
+	status, stdout, stderr := runCLI(server, "queue", "dead", dir)
+	if status != 0 || !strings.Contains(stdout, dead[0].ID) || !strings.Contains(stdout, "handler down") {
+		t.Errorf("queue dead is expected to list the dead-lettered events! Got %d; %s%s", status, stdout, stderr)
+	}
+
+	status, _, stderr = runCLI(server, "queue", "replay", dir, dead[0].ID)
+	if pending, _ := q.Pending(); status != 0 || !strings.Contains(stderr, "replayed 1 event(s)") || len(pending) != 1 {
+		t.Errorf("queue replay is expected to move the event back to the pending queue! Got %d; %s", status, stderr)
+	}
+
+	if status, _, _ := runCLI(server, "queue", "replay"); status != 1 {
+		t.Errorf("queue replay is expected to require the queue directory! Got %d", status)
+	}
+}
+
+func TestDiffReferences(t *testing.T) {
+	// The same content in two sites, under different IDs.
+	site := func(prefix string) *webflowtest.Fixture {
+		return &webflowtest.Fixture{Sites: []webflowtest.SiteFixture{{
+			Site: webflowAPI.Site{ID: siteID, Name: "My Site"},
+			Collections: []webflowtest.CollectionFixture{
+				{
+					Collection: webflowAPI.Collection{ID: prefix + "authors", Name: "Authors", Slug: "authors", Fields: exampleFixture.Sites[0].Collections[0].Fields},
+					Items:      []json.RawMessage{json.RawMessage(fmt.Sprintf(`{"_id":"%sa1","name":"Ada","slug":"ada"}`, prefix))},
+				},
+				{
+					Collection: webflowAPI.Collection{ID: prefix + "posts", Name: "Posts", Slug: "posts", Fields: append([]webflowAPI.CollectionField{
+						{ID: "f3", Name: "Author", Slug: "author", Type: webflowAPI.FieldTypeItemRef, Validations: &webflowAPI.FieldValidations{CollectionID: prefix + "authors"}},
+					}, exampleFixture.Sites[0].Collections[0].Fields...)},
+					Items: []json.RawMessage{json.RawMessage(fmt.Sprintf(`{"_id":"%sp1","name":"Hello","slug":"hello","author":"%sa1"}`, prefix, prefix))},
+				},
+			},
+		}}}
+	}
+	staging, production := webflowtest.NewServer(site("s")), webflowtest.NewServer(site("p"))
+	defer staging.Close()
+	defer production.Close()
+
+	dir, err := ioutil.TempDir("", "webflow-cli")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	archive := filepath.Join(dir, "production.tar.gz")
+	if status, _, stderr := runCLI(production, "backup", archive); status != 0 {
+		t.Fatalf("backup is expected to write the archive! Got %d; %s", status, stderr)
+	}
+
+	status, stdout, stderr := runCLI(staging, "diff", "posts", "--against", archive)
+	if status != 0 || !strings.Contains(stdout, "0 added, 0 removed, 0 changed") {
+		t.Errorf("diff is expected to compare references by the referenced items' slugs! Got %d; %s%s", status, stdout, stderr)
+	}
+}
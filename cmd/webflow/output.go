@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/tidwall/gjson"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Output formats accepted by --output.
+const (
+	outputTable  = "table"
+	outputJSON   = "json"
+	outputNDJSON = "ndjson"
+	outputYAML   = "yaml"
+)
+
+// column A table column: its header and the gjson path of its value in each record.
+type column struct {
+	header string
+	path   string
+}
+
+var (
+	siteColumns = []column{
+		{"ID", "_id"},
+		{"NAME", "name"},
+		{"SHORT NAME", "shortName"},
+		{"LAST PUBLISHED", "lastPublished"},
+	}
+	collectionColumns = []column{
+		{"ID", "_id"},
+		{"NAME", "name"},
+		{"SLUG", "slug"},
+		{"SINGULAR NAME", "singularName"},
+	}
+	fieldColumns = []column{
+		{"SLUG", "slug"},
+		{"NAME", "name"},
+		{"TYPE", "type"},
+		{"REQUIRED", "required"},
+	}
+	itemColumns = []column{
+		{"ID", "_id"},
+		{"NAME", "name"},
+		{"SLUG", "slug"},
+		{"DRAFT", "_draft"},
+		{"ARCHIVED", "_archived"},
+		{"UPDATED", "updated-on"},
+	}
+	importColumns = []column{
+		{"ROW", "row"},
+		{"ACTION", "action"},
+		{"ID", "itemId"},
+		{"KEY", "key"},
+		{"ERROR", "error"},
+	}
+)
+
+// printer Render records as a table, JSON, NDJSON or YAML.
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+// newPrinter Create a printer, checking the format is supported.
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case outputTable, outputJSON, outputNDJSON, outputYAML:
+		return &printer{w: w, format: format}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output format '%s'; expected table, json, ndjson or yaml", format)
+}
+
+// list Print a list of records.
+func (p *printer) list(records [][]byte, columns []column) error {
+	switch p.format {
+	case outputTable:
+		return p.table(records, columns)
+	case outputNDJSON:
+		for _, record := range records {
+			if err := p.ndjson(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	raw := make([]json.RawMessage, len(records))
+	for i, record := range records {
+		raw[i] = record
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return p.document(data)
+}
+
+// one Print a single record. Tables show the rows, with the columns given, rather than the record itself.
+func (p *printer) one(record []byte, rows [][]byte, columns []column) error {
+	switch p.format {
+	case outputTable:
+		return p.table(rows, columns)
+	case outputNDJSON:
+		return p.ndjson(record)
+	}
+
+	return p.document(record)
+}
+
+// document Print JSON as indented JSON or as YAML.
+func (p *printer) document(data []byte) error {
+	if p.format == outputYAML {
+		var val interface{}
+		if err := yaml.Unmarshal(data, &val); err != nil {
+			return err
+		}
+		out, err := yaml.Marshal(val)
+		if err != nil {
+			return err
+		}
+		_, err = p.w.Write(out)
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.Indent(buf, data, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+	_, err := buf.WriteTo(p.w)
+	return err
+}
+
+// ndjson Print a record compacted onto a single line.
+func (p *printer) ndjson(record []byte) error {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, record); err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+	_, err := buf.WriteTo(p.w)
+	return err
+}
+
+// table Print the columns of each record aligned in a table.
+func (p *printer) table(records [][]byte, columns []column) error {
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+
+	headers := []string{}
+	for _, col := range columns {
+		headers = append(headers, col.header)
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	for _, record := range records {
+		cells := []string{}
+		for _, col := range columns {
+			cells = append(cells, strings.Replace(gjson.GetBytes(record, col.path).String(), "\n", " ", -1))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// toRecords Encode each value as a JSON record.
+func toRecords(values interface{}) ([][]byte, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := []json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	records := make([][]byte, len(raw))
+	for i, record := range raw {
+		records[i] = record
+	}
+
+	return records, nil
+}
@@ -14,14 +14,118 @@ type GeneralError struct {
 	Err  string `json:"err"`
 }
 
-// Collection API contract for CMS collection.
+// Field types reported in CollectionField.Type.
+const (
+	FieldTypeBool       = "Bool"
+	FieldTypeColor      = "Color"
+	FieldTypeDate       = "Date"
+	FieldTypeEmail      = "Email"
+	FieldTypeExtFileRef = "ExtFileRef"
+	FieldTypeImageRef   = "ImageRef"
+	FieldTypeItemRef    = "ItemRef"
+	FieldTypeItemRefSet = "ItemRefSet"
+	FieldTypeLink       = "Link"
+	FieldTypeNumber     = "Number"
+	FieldTypeOption     = "Option"
+	FieldTypePhone      = "Phone"
+	FieldTypePlainText  = "PlainText"
+	FieldTypeRichText   = "RichText"
+	FieldTypeSet        = "Set"
+	FieldTypeUser       = "User"
+	FieldTypeVideo      = "Video"
+)
+
+// Site API contract for a site.
+type Site struct {
+	ID            string    `json:"_id"`
+	CreatedOn     time.Time `json:"createdOn"`
+	Name          string    `json:"name"`
+	ShortName     string    `json:"shortName"`
+	LastPublished time.Time `json:"lastPublished"`
+	PreviewURL    string    `json:"previewUrl"`
+	Timezone      string    `json:"timezone"`
+}
+
+// Sites List of Site.
+type Sites []Site
+
+// Collection API contract for CMS collection. Fields are only reported when requesting a single collection.
 type Collection struct {
-	ID           string    `json:"_id"`
-	LastUpdated  time.Time `json:"lastUpdated"`
-	CreatedOn    time.Time `json:"createdOn"`
-	Name         string    `json:"name"`
-	Slug         string    `json:"slug"`
-	SingularName string    `json:"singularName"`
+	ID           string            `json:"_id"`
+	LastUpdated  time.Time         `json:"lastUpdated"`
+	CreatedOn    time.Time         `json:"createdOn"`
+	Name         string            `json:"name"`
+	Slug         string            `json:"slug"`
+	SingularName string            `json:"singularName"`
+	Fields       []CollectionField `json:"fields,omitempty"`
+}
+
+// CollectionField API contract for a field in a collection's schema.
+type CollectionField struct {
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Slug        string            `json:"slug"`
+	Type        string            `json:"type"`
+	Required    bool              `json:"required"`
+	Editable    bool              `json:"editable"`
+	HelpText    string            `json:"helpText,omitempty"`
+	Validations *FieldValidations `json:"validations,omitempty"`
+}
+
+// CollectionDefinition API contract for creating a collection. Webflow adds the name & slug fields itself.
+type CollectionDefinition struct {
+	Name         string `json:"name"`
+	SingularName string `json:"singularName"`
+	// Slug Defaults to one made from the name.
+	Slug   string            `json:"slug,omitempty"`
+	Fields []FieldDefinition `json:"fields,omitempty"`
+}
+
+// FieldDefinition API contract for creating or updating a field of a collection. A field's type cannot be changed.
+type FieldDefinition struct {
+	Name string `json:"name"`
+	// Slug Defaults to one made from the name.
+	Slug        string            `json:"slug,omitempty"`
+	Type        string            `json:"type"`
+	Required    bool              `json:"required"`
+	HelpText    string            `json:"helpText,omitempty"`
+	Validations *FieldValidations `json:"validations,omitempty"`
+}
+
+// FieldValidations API contract for the validation rules of a field. Which rules are present depends on the field type.
+type FieldValidations struct {
+	// PlainText & RichText.
+	MinLength  *int  `json:"minLength,omitempty"`
+	MaxLength  *int  `json:"maxLength,omitempty"`
+	SingleLine *bool `json:"singleLine,omitempty"`
+	// Number.
+	Min           *float64 `json:"min,omitempty"`
+	Max           *float64 `json:"max,omitempty"`
+	DecimalPlaces *int     `json:"decimalPlaces,omitempty"`
+	AllowNegative *bool    `json:"allowNegative,omitempty"`
+	// Option.
+	Options []FieldOption `json:"options,omitempty"`
+	// ItemRef & ItemRefSet.
+	CollectionID string `json:"collectionId,omitempty"`
+}
+
+// FieldOption API contract for a choice of an Option field.
+type FieldOption struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Image API contract for the value of an ImageRef field, or an image of a Set field.
+type Image struct {
+	FileID string `json:"fileId,omitempty"`
+	URL    string `json:"url"`
+	Alt    string `json:"alt,omitempty"`
+}
+
+// File API contract for the value of an ExtFileRef field.
+type File struct {
+	FileID string `json:"fileId,omitempty"`
+	URL    string `json:"url"`
 }
 
 // Collections List of Collection.
@@ -40,6 +144,12 @@ type CollectionItem struct {
 	ID          string `json:"_id"`
 }
 
+// PublishedItems API contract for the result of publishing items.
+type PublishedItems struct {
+	PublishedItemIDs []string `json:"publishedItemIds"`
+	Errors           []string `json:"errors"`
+}
+
 // CollectionItems API contract for retrieving collection items.
 type CollectionItems struct {
 	// Delay parsing until we know the type.
@@ -49,3 +159,16 @@ type CollectionItems struct {
 	Offset int             `json:"offset"`
 	Total  int             `json:"total"`
 }
+
+// Webhook API contract for a webhook registered on a site.
+type Webhook struct {
+	ID          string    `json:"_id"`
+	TriggerType string    `json:"triggerType"`
+	TriggerID   string    `json:"triggerId"`
+	Site        string    `json:"site"`
+	URL         string    `json:"url"`
+	CreatedOn   time.Time `json:"createdOn"`
+}
+
+// Webhooks List of Webhook.
+type Webhooks []Webhook
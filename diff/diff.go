@@ -0,0 +1,393 @@
+// Package diff Compare two sets of collection items, e.g. a live collection & a backup or staging & production, and
+// report the added, removed & changed items with the before & after values of each changed field.
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Formats accepted by Result.Render.
+const (
+	FormatText    = "text"
+	FormatJSON    = "json"
+	FormatUnified = "unified"
+
+	// Kinds of ItemDiff.
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+
+	// DefaultKey Field matching the items of both sets.
+	DefaultKey = "slug"
+)
+
+// Metadata maintained by Webflow, which differs between sites & copies, so is not compared unless it is the key.
+var metadata = map[string]bool{
+	"_id": true, "_cid": true,
+	"created-on": true, "created-by": true,
+	"updated-on": true, "updated-by": true,
+	"published-on": true, "published-by": true,
+}
+
+// FieldChange A field whose value differs. Before or After is omitted when the field is missing from that side.
+type FieldChange struct {
+	Field  string          `json:"field"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// ItemDiff An item that was added, removed or changed.
+type ItemDiff struct {
+	Key  string `json:"key"`
+	Kind string `json:"kind"`
+	// Before The item in the first set; omitted when added.
+	Before json.RawMessage `json:"before,omitempty"`
+	// After The item in the second set; omitted when removed.
+	After json.RawMessage `json:"after,omitempty"`
+	// Changes The differing fields of a changed item, sorted by field.
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// Result The differences between two item sets, sorted by key.
+type Result struct {
+	Key   string     `json:"key"`
+	Items []ItemDiff `json:"items"`
+}
+
+// Empty Whether the sets hold the same items.
+func (result *Result) Empty() bool {
+	return len(result.Items) == 0
+}
+
+// Count The number of items of the given kind.
+func (result *Result) Count(kind string) int {
+	n := 0
+	for _, item := range result.Items {
+		if item.Kind == kind {
+			n++
+		}
+	}
+
+	return n
+}
+
+// References The reference fields of the compared items and, for each set, the keys of the items they may reference by
+// item ID. See DiffReferences.
+type References struct {
+	// Fields The slugs of the reference & multi-reference fields.
+	Fields map[string]bool
+	// Before The keys, e.g. slugs, of the items the first set may reference, by item ID.
+	Before map[string]string
+	// After The keys of the items the second set may reference, by item ID.
+	After map[string]string
+}
+
+// Diff Compare the items of a to those of b, matched by the given key field. An empty key uses DefaultKey. Metadata
+// such as IDs & timestamps is ignored unless it is the key.
+func Diff(a, b [][]byte, key string) (*Result, error) {
+	return DiffReferences(a, b, key, nil)
+}
+
+// DiffReferences Diff, comparing reference fields by the keys of the referenced items rather than their IDs, so the
+// items of two sites, whose IDs differ, may be compared. IDs missing from the references are compared as they are.
+func DiffReferences(a, b [][]byte, key string, refs *References) (*Result, error) {
+	if key == "" {
+		key = DefaultKey
+	}
+
+	var fields map[string]bool
+	var beforeRefs, afterRefs map[string]string
+	if refs != nil {
+		fields, beforeRefs, afterRefs = refs.Fields, refs.Before, refs.After
+	}
+
+	before, beforeKeys, err := index(a, key, fields, beforeRefs)
+	if err != nil {
+		return nil, fmt.Errorf("first item set: %+v", err)
+	}
+	after, afterKeys, err := index(b, key, fields, afterRefs)
+	if err != nil {
+		return nil, fmt.Errorf("second item set: %+v", err)
+	}
+
+	result := &Result{Key: key, Items: []ItemDiff{}}
+
+	for _, k := range beforeKeys {
+		if _, ok := after[k]; !ok {
+			result.Items = append(result.Items, ItemDiff{Key: k, Kind: Removed, Before: before[k].raw})
+		}
+	}
+
+	for _, k := range afterKeys {
+		old, ok := before[k]
+		if !ok {
+			result.Items = append(result.Items, ItemDiff{Key: k, Kind: Added, After: after[k].raw})
+			continue
+		}
+
+		if changes := compare(old.fields, after[k].fields, key); len(changes) > 0 {
+			result.Items = append(result.Items, ItemDiff{Key: k, Kind: Changed, Before: old.raw, After: after[k].raw, Changes: changes})
+		}
+	}
+
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		return result.Items[i].Key < result.Items[j].Key
+	})
+
+	return result, nil
+}
+
+// ReadItems Read an item set from NDJSON or a JSON array, such as written by the export pkg.
+func ReadItems(r io.Reader) ([][]byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		raw := []json.RawMessage{}
+		if err := json.Unmarshal(trimmed, &raw); err != nil {
+			return nil, fmt.Errorf("unable to decode the JSON array; error: %+v", err)
+		}
+		items := make([][]byte, len(raw))
+		for i, item := range raw {
+			items[i] = item
+		}
+		return items, nil
+	}
+
+	items := [][]byte{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		item := json.RawMessage{}
+		if err := decoder.Decode(&item); err == io.EOF {
+			return items, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to decode item %d; error: %+v", len(items)+1, err)
+		}
+		items = append(items, item)
+	}
+}
+
+// Render Write the result as text, JSON or a unified diff.
+func (result *Result) Render(w io.Writer, format string) error {
+	switch format {
+	case FormatText:
+		return result.text(w)
+	case FormatJSON:
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case FormatUnified:
+		return result.unified(w)
+	}
+
+	return fmt.Errorf("unknown diff format '%s'; expected text, json or unified", format)
+}
+
+// text One line per item, +, - or ~, followed by a line per changed field.
+func (result *Result) text(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	for _, item := range result.Items {
+		switch item.Kind {
+		case Added:
+			fmt.Fprintf(bw, "+ %s\n", item.Key)
+		case Removed:
+			fmt.Fprintf(bw, "- %s\n", item.Key)
+		case Changed:
+			fmt.Fprintf(bw, "~ %s\n", item.Key)
+			for _, change := range item.Changes {
+				fmt.Fprintf(bw, "    %s: %s -> %s\n", change.Field, display(change.Before), display(change.After))
+			}
+		}
+	}
+
+	fmt.Fprintf(bw, "%d added, %d removed, %d changed\n", result.Count(Added), result.Count(Removed), result.Count(Changed))
+
+	return bw.Flush()
+}
+
+// unified A hunk per item with a line per field, in the style of diff -u.
+func (result *Result) unified(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "--- a")
+	fmt.Fprintln(bw, "+++ b")
+
+	for _, item := range result.Items {
+		fmt.Fprintf(bw, "@@ %s=%s (%s) @@\n", result.Key, item.Key, item.Kind)
+
+		switch item.Kind {
+		case Added:
+			writeFields(bw, "+", item.After)
+		case Removed:
+			writeFields(bw, "-", item.Before)
+		case Changed:
+			for _, change := range item.Changes {
+				if change.Before != nil {
+					fmt.Fprintf(bw, "-%s: %s\n", change.Field, change.Before)
+				}
+				if change.After != nil {
+					fmt.Fprintf(bw, "+%s: %s\n", change.Field, change.After)
+				}
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// entry An item's raw JSON & decoded fields.
+type entry struct {
+	raw    json.RawMessage
+	fields map[string]json.RawMessage
+}
+
+// index Decode the items by key, returning the keys in their original order. The IDs in the given reference fields are
+// replaced by the referenced items' keys.
+func index(items [][]byte, key string, refFields map[string]bool, refs map[string]string) (map[string]entry, []string, error) {
+	entries := map[string]entry{}
+	keys := []string{}
+
+	for i, raw := range items {
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, nil, fmt.Errorf("unable to decode item %d; error: %+v", i+1, err)
+		}
+
+		k := ""
+		if err := json.Unmarshal(fields[key], &k); err != nil || k == "" {
+			return nil, nil, fmt.Errorf("item %d has no '%s'", i+1, key)
+		}
+		if _, ok := entries[k]; ok {
+			return nil, nil, fmt.Errorf("more than one item has the %s '%s'", key, k)
+		}
+
+		for name := range refFields {
+			if value, ok := fields[name]; ok {
+				fields[name] = resolve(value, refs)
+			}
+		}
+
+		entries[k] = entry{raw: raw, fields: fields}
+		keys = append(keys, k)
+	}
+
+	return entries, keys, nil
+}
+
+// resolve Replace the item ID, or IDs, of a reference field's value by the referenced items' keys.
+func resolve(value json.RawMessage, refs map[string]string) json.RawMessage {
+	var ref interface{}
+	if err := json.Unmarshal(value, &ref); err != nil {
+		return value
+	}
+
+	switch ref := ref.(type) {
+	case string:
+		if k, ok := refs[ref]; ok {
+			value, _ = json.Marshal(k)
+		}
+	case []interface{}:
+		for i, id := range ref {
+			if k, ok := refs[fmt.Sprint(id)]; ok {
+				ref[i] = k
+			}
+		}
+		value, _ = json.Marshal(ref)
+	}
+
+	return value
+}
+
+// compare The fields, other than metadata & the key, whose values differ.
+func compare(before, after map[string]json.RawMessage, key string) []FieldChange {
+	names := map[string]bool{}
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+
+	changes := []FieldChange{}
+	for name := range names {
+		if name == key || metadata[name] {
+			continue
+		}
+		if !equal(before[name], after[name]) {
+			changes = append(changes, FieldChange{Field: name, Before: compact(before[name]), After: compact(after[name])})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+
+	return changes
+}
+
+// equal Whether two JSON values are the same, ignoring formatting & key order. A missing value equals null.
+func equal(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if len(a) > 0 {
+		json.Unmarshal(a, &va)
+	}
+	if len(b) > 0 {
+		json.Unmarshal(b, &vb)
+	}
+
+	return reflect.DeepEqual(va, vb)
+}
+
+// compact JSON without insignificant whitespace; nil when missing.
+func compact(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, raw); err != nil {
+		return raw
+	}
+
+	return buf.Bytes()
+}
+
+// display A value for the text format; missing values are shown as (none).
+func display(raw json.RawMessage) string {
+	if raw == nil {
+		return "(none)"
+	}
+
+	return string(raw)
+}
+
+// writeFields Write each field of an item, sorted, with the given prefix.
+func writeFields(w io.Writer, prefix string, raw json.RawMessage) {
+	fields := map[string]json.RawMessage{}
+	json.Unmarshal(raw, &fields)
+
+	names := []string{}
+	for name := range fields {
+		if !metadata[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(w, "%s%s: %s\n", prefix, name, strings.TrimSpace(string(compact(fields[name]))))
+	}
+}
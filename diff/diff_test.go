@@ -0,0 +1,116 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var (
+	live = [][]byte{
+		[]byte(`{"_id":"1","slug":"blue","name":"Blue","color":"#00f","updated-on":"2020-01-01T00:00:00.000Z"}`),
+		[]byte(`{"_id":"2","slug":"green","name":"Green","tags":["a","b"]}`),
+		[]byte(`{"_id":"3","slug":"red","name":"Red"}`),
+	}
+	snapshot = [][]byte{
+		[]byte(`{"_id":"9","slug":"blue","name":"Navy","updated-on":"2020-02-01T00:00:00.000Z"}`),
+		[]byte(`{"_id":"8","slug":"green","tags":["a", "b"],"name":"Green"}`),
+		[]byte(`{"_id":"7","slug":"yellow","name":"Yellow"}`),
+	}
+)
+
+func TestDiff(t *testing.T) {
+	result, err := Diff(live, snapshot, "")
+	if err != nil {
+		t.Fatalf("Diff() is expected to compare the item sets: %+v", err)
+	}
+
+	if len(result.Items) != 3 || result.Count(Added) != 1 || result.Count(Removed) != 1 || result.Count(Changed) != 1 {
+		t.Fatalf("Diff() is expected to find 1 added, 1 removed & 1 changed item! Got %+v.", result.Items)
+	}
+
+	changed := result.Items[0]
+	if changed.Key != "blue" || len(changed.Changes) != 2 {
+		t.Fatalf("Diff() is expected to ignore metadata & report the changed fields! Got %+v.", changed)
+	}
+	if c := changed.Changes[0]; c.Field != "color" || string(c.Before) != `"#00f"` || c.After != nil {
+		t.Errorf("Diff() is expected to report the removed field! Got %+v.", c)
+	}
+	if c := changed.Changes[1]; c.Field != "name" || string(c.Before) != `"Blue"` || string(c.After) != `"Navy"` {
+		t.Errorf("Diff() is expected to report the before & after values! Got %+v.", c)
+	}
+
+	if _, err := Diff(live, append(snapshot, []byte(`{"slug":"yellow"}`)), "slug"); err == nil {
+		t.Error("Diff() is expected to error for duplicate keys.")
+	}
+
+	byID, _ := Diff(live, snapshot, "_id")
+	if byID.Count(Added) != 3 || byID.Count(Removed) != 3 {
+		t.Errorf("Diff() is expected to match items by the given key! Got %+v.", byID.Items)
+	}
+}
+
+func TestDiffReferences(t *testing.T) {
+	staging := [][]byte{
+		[]byte(`{"_id":"s1","slug":"hello","author":"sa1","editors":["sa1","sa2"]}`),
+		[]byte(`{"_id":"s2","slug":"bye","author":"sa2"}`),
+	}
+	production := [][]byte{
+		[]byte(`{"_id":"p1","slug":"hello","author":"pa1","editors":["pa1","pa2"]}`),
+		[]byte(`{"_id":"p2","slug":"bye","author":"pa1"}`),
+	}
+	refs := &References{
+		Fields: map[string]bool{"author": true, "editors": true},
+		Before: map[string]string{"sa1": "ada", "sa2": "grace"},
+		After:  map[string]string{"pa1": "ada", "pa2": "grace"},
+	}
+
+	if result, _ := Diff(staging, production, ""); result.Count(Changed) != 2 {
+		t.Fatalf("Diff() is expected to compare references by ID! Got %+v.", result.Items)
+	}
+
+	result, err := DiffReferences(staging, production, "", refs)
+	if err != nil || len(result.Items) != 1 || result.Items[0].Key != "bye" {
+		t.Fatalf("DiffReferences() is expected to compare references by the referenced items' keys! Got %+v; error %+v.", result, err)
+	}
+	if c := result.Items[0].Changes; len(c) != 1 || string(c[0].Before) != `"grace"` || string(c[0].After) != `"ada"` {
+		t.Errorf("DiffReferences() is expected to report the referenced items' keys! Got %+v.", c)
+	}
+}
+
+func TestRender(t *testing.T) {
+	result, _ := Diff(live, snapshot, "slug")
+
+	buf := &bytes.Buffer{}
+	result.Render(buf, FormatText)
+	if !strings.Contains(buf.String(), `    name: "Blue" -> "Navy"`) || !strings.Contains(buf.String(), "+ yellow") || !strings.Contains(buf.String(), "- red") {
+		t.Errorf("Render() is expected to write a line per item & changed field! Got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	result.Render(buf, FormatUnified)
+	if !strings.Contains(buf.String(), "@@ slug=blue (changed) @@\n-color: \"#00f\"\n-name: \"Blue\"\n+name: \"Navy\"\n") {
+		t.Errorf("Render() is expected to write unified diff hunks! Got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	result.Render(buf, FormatJSON)
+	decoded := &Result{}
+	if err := json.Unmarshal(buf.Bytes(), decoded); err != nil || len(decoded.Items) != 3 {
+		t.Errorf("Render() is expected to write the result as JSON! Got %s; error %+v.", buf.String(), err)
+	}
+
+	if err := result.Render(buf, "html"); err == nil {
+		t.Error("Render() is expected to error for an unknown format.")
+	}
+}
+
+func TestReadItems(t *testing.T) {
+	for _, input := range []string{"[\n{\"slug\":\"a\"},\n{\"slug\":\"b\"}\n]\n", "{\"slug\":\"a\"}\n{\"slug\":\"b\"}\n"} {
+		items, err := ReadItems(strings.NewReader(input))
+		if err != nil || len(items) != 2 {
+			t.Errorf("ReadItems() is expected to read JSON arrays & NDJSON! Got %d items; error %+v.", len(items), err)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package expand Replace the item IDs of ItemRef & ItemRefSet fields with the referenced items, so e.g. a blog post
+// can be rendered with its author & categories without looking each up. Collections are requested once per Expander
+// and referenced items may be expanded in turn, to a limited depth.
+package expand
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/internal/object"
+)
+
+const (
+	// DefaultDepth Levels of references expanded when no fields are given.
+	DefaultDepth = 1
+	// DefaultMaxPages Number of additional pages of items requested per collection.
+	DefaultMaxPages = 100
+)
+
+// Expander Expands references, remembering the collections it has requested. Safe for concurrent use.
+type Expander struct {
+	// Depth Levels of references expanded when no fields are given, e.g. 2 expands a post's author & the author's
+	// company. Explicit field paths are expanded to their own depth.
+	Depth int
+	// MaxPages Number of additional pages of items to request. See webflowAPI.Interface.GetAllItemsInCollectionByID.
+	MaxPages int
+
+	api webflowAPI.Interface
+	mu  sync.Mutex
+	// schemas Collections with their fields, by ID.
+	schemas map[string]*webflowAPI.Collection
+	// items Raw items by ID, by collection ID.
+	items map[string]map[string]json.RawMessage
+}
+
+// New Create an expander reading collections through the given API.
+func New(api webflowAPI.Interface) *Expander {
+	return &Expander{
+		Depth:    DefaultDepth,
+		MaxPages: DefaultMaxPages,
+		api:      api,
+		schemas:  map[string]*webflowAPI.Collection{},
+		items:    map[string]map[string]json.RawMessage{},
+	}
+}
+
+// Expand Return the item with its references replaced by the referenced items. Fields are reference field slugs, with
+// nested references given as paths such as "author.company"; with none, every reference is expanded to Depth levels.
+// The item's collection is found from its _cid. References to items that no longer exist are left as IDs.
+func (e *Expander) Expand(item []byte, fields ...string) ([]byte, error) {
+	obj, err := object.Decode(item)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the item; error: %+v", err)
+	}
+
+	collectionID, _ := obj["_cid"].(string)
+	if collectionID == "" {
+		return nil, fmt.Errorf("item %v has no _cid naming its collection", obj["_id"])
+	}
+
+	if err := e.expand(obj, collectionID, toPaths(fields), e.Depth); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(obj)
+}
+
+// ExpandAll Expand each of the items. See Expand.
+func (e *Expander) ExpandAll(items [][]byte, fields ...string) ([][]byte, error) {
+	expanded := make([][]byte, len(items))
+	for i, item := range items {
+		var err error
+		if expanded[i], err = e.Expand(item, fields...); err != nil {
+			return nil, err
+		}
+	}
+
+	return expanded, nil
+}
+
+// paths Field slugs to expand, each with the paths to expand within the referenced items. nil expands every field.
+type paths map[string]paths
+
+// toPaths Split dotted field paths into a tree.
+func toPaths(fields []string) paths {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	tree := paths{}
+	for _, field := range fields {
+		node := tree
+		for _, slug := range strings.Split(field, ".") {
+			if node[slug] == nil {
+				node[slug] = paths{}
+			}
+			node = node[slug]
+		}
+	}
+
+	return tree
+}
+
+// expand Expand the references of a decoded item of the collection in place. With no paths, every reference is
+// expanded to depth.
+func (e *Expander) expand(item map[string]interface{}, collectionID string, want paths, depth int) error {
+	if want == nil && depth <= 0 {
+		return nil
+	}
+
+	schema, err := e.schema(collectionID)
+	if err != nil {
+		return err
+	}
+
+	for _, field := range schema.Fields {
+		if field.Type != webflowAPI.FieldTypeItemRef && field.Type != webflowAPI.FieldTypeItemRefSet {
+			continue
+		}
+		if field.Validations == nil || field.Validations.CollectionID == "" {
+			continue
+		}
+
+		var nested paths
+		if want != nil {
+			var ok bool
+			if nested, ok = want[field.Slug]; !ok {
+				continue
+			}
+		}
+
+		val, ok := item[field.Slug]
+		if !ok || val == nil {
+			continue
+		}
+
+		if item[field.Slug], err = e.resolve(field.Validations.CollectionID, val, nested, want == nil, depth-1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolve Replace an ID, or list of IDs, with the referenced items, expanding them in turn.
+func (e *Expander) resolve(collectionID string, val interface{}, nested paths, all bool, depth int) (interface{}, error) {
+	switch v := val.(type) {
+	case string:
+		raw, err := e.item(collectionID, v)
+		if err != nil || raw == nil {
+			return val, err
+		}
+		ref, err := object.Decode(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		if all {
+			err = e.expand(ref, collectionID, nil, depth)
+		} else if len(nested) > 0 {
+			err = e.expand(ref, collectionID, nested, depth)
+		}
+		return ref, err
+
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, id := range v {
+			var err error
+			if resolved[i], err = e.resolve(collectionID, id, nested, all, depth); err != nil {
+				return nil, err
+			}
+		}
+		return resolved, nil
+	}
+
+	return val, nil
+}
+
+// schema The collection with its fields, requested on first use.
+func (e *Expander) schema(collectionID string) (*webflowAPI.Collection, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if schema, ok := e.schemas[collectionID]; ok {
+		return schema, nil
+	}
+
+	schema, err := e.api.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read collection '%s'; error: %+v", collectionID, err)
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("collection '%s' not found", collectionID)
+	}
+	e.schemas[collectionID] = schema
+
+	return schema, nil
+}
+
+// item The raw referenced item, requesting its collection's items on first use; nil when it does not exist.
+func (e *Expander) item(collectionID, itemID string) (json.RawMessage, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	items, ok := e.items[collectionID]
+	if !ok {
+		all, err := e.api.GetAllItemsInCollectionByID(collectionID, e.MaxPages)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the items of collection '%s'; error: %+v", collectionID, err)
+		}
+
+		items = map[string]json.RawMessage{}
+		for _, raw := range all {
+			ref := struct {
+				ID string `json:"_id"`
+			}{}
+			if err := json.Unmarshal(raw, &ref); err == nil {
+				items[ref.ID] = raw
+			}
+		}
+		e.items[collectionID] = items
+	}
+
+	return items[itemID], nil
+}
@@ -0,0 +1,83 @@
+package expand
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/redeemed2011/webflowAPI/memory"
+	"github.com/tidwall/gjson"
+)
+
+// countingStore Counts the collection item requests.
+type countingStore struct {
+	*memory.Store
+	requests int
+}
+
+func (s *countingStore) GetAllItemsInCollectionByID(ID string, maxPages int) ([][]byte, error) {
+	s.requests++
+	return s.Store.GetAllItemsInCollectionByID(ID, maxPages)
+}
+
+func newTestExpander(t *testing.T) (*Expander, *countingStore, []byte) {
+	store, err := memory.Load("mysiteid", "testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	post, _ := store.GetItem("", "", "posts1", "", "p1")
+	cs := &countingStore{Store: store}
+
+	return New(cs), cs, post
+}
+
+func TestExpand(t *testing.T) {
+	e, store, post := newTestExpander(t)
+
+	expanded, err := e.Expand(post)
+	if err != nil {
+		t.Fatalf("Expand() is expected to expand the references: %+v", err)
+	}
+
+	result := gjson.ParseBytes(expanded)
+	if result.Get("author.name").String() != "Ada" || result.Get("editors.0.name").String() != "Grace" {
+		t.Errorf("Expand() is expected to replace the IDs with the referenced items! Got %s.", expanded)
+	}
+	if result.Get("editors.1").String() != "gone" {
+		t.Errorf("Expand() is expected to keep the IDs of missing items! Got %s.", expanded)
+	}
+	if result.Get("author.company").String() != "c1" {
+		t.Errorf("Expand() is expected to stop at the default depth! Got %s.", expanded)
+	}
+	if !strings.Contains(string(expanded), "12345678901234567") {
+		t.Errorf("Expand() is expected to keep numbers exactly! Got %s.", expanded)
+	}
+
+	e.Depth = 2
+	expanded, _ = e.Expand(post)
+	if gjson.GetBytes(expanded, "author.company.name").String() != "Acme" {
+		t.Errorf("Expand() is expected to expand nested references to the given depth! Got %s.", expanded)
+	}
+
+	if store.requests != 2 {
+		t.Errorf("Expand() is expected to request each collection's items once! Got %d requests.", store.requests)
+	}
+}
+
+func TestExpandFields(t *testing.T) {
+	e, _, post := newTestExpander(t)
+
+	expanded, err := e.Expand(post, "author.company")
+	if err != nil {
+		t.Fatalf("Expand() is expected to expand the given fields: %+v", err)
+	}
+
+	result := gjson.ParseBytes(expanded)
+	if result.Get("author.company.name").String() != "Acme" || result.Get("editors.0").String() != "a2" {
+		t.Errorf("Expand() is expected to expand only the given field paths! Got %s.", expanded)
+	}
+
+	if _, err := e.Expand([]byte(`{"_id":"x"}`)); err == nil {
+		t.Error("Expand() is expected to error for items without a _cid.")
+	}
+}
@@ -0,0 +1,404 @@
+// Package export Write all the items of a collection to NDJSON, a JSON array or CSV. CSV columns follow the
+// collection's fields, with references, images & options flattened into readable cells for spreadsheets.
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+// Formats accepted by Exporter.Export.
+const (
+	FormatNDJSON = "ndjson"
+	FormatJSON   = "json"
+	FormatCSV    = "csv"
+
+	// DefaultMaxPages Number of additional pages of items requested per collection.
+	DefaultMaxPages = 100
+
+	// Separator between the values of multi-value cells such as reference sets.
+	cellSeparator = "; "
+)
+
+// Item metadata exported as CSV columns in addition to the collection's fields.
+var metadataColumns = []string{"_id", "_draft", "_archived", "created-on", "updated-on", "published-on"}
+
+// Exporter Writes the items of collections.
+type Exporter struct {
+	// MaxPages Number of additional pages of items to request. See webflowAPI.Interface.GetAllItemsInCollectionByID.
+	MaxPages int
+	// Checkpoint Called with the position after each page of an NDJSON export has been written & flushed, e.g. to save
+	// it, so an interrupted export may be continued with Resume. Nil for none.
+	Checkpoint func(Position) error
+
+	api webflowAPI.Interface
+}
+
+// Position How far an NDJSON export got: the cursor after its last complete page & the length of the output written
+// for the items before the cursor. Output past Bytes is of a page that was not completely written.
+type Position struct {
+	webflowAPI.Cursor
+	Bytes int64 `json:"bytes"`
+}
+
+// LoadPosition Read a position saved with Save.
+func LoadPosition(path string) (*Position, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	position := &Position{}
+	if err := json.Unmarshal(data, position); err != nil {
+		return nil, fmt.Errorf("unable to decode the export position %s; error: %+v", path, err)
+	}
+
+	return position, nil
+}
+
+// Save Atomically write the position to the file: write to a temp file, sync it then rename it into place.
+func (position Position) Save(path string) error {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("unable to save the export position; error: %+v", err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save the export position; error: %+v", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save the export position; error: %+v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save the export position; error: %+v", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// New Create an exporter reading collections through the given API.
+func New(api webflowAPI.Interface) *Exporter {
+	return &Exporter{
+		MaxPages: DefaultMaxPages,
+		api:      api,
+	}
+}
+
+// Export Write all the items of the collection in the given format, returning the number of items written.
+func (e *Exporter) Export(w io.Writer, collectionID, format string) (int, error) {
+	switch format {
+	case FormatNDJSON:
+		return e.NDJSON(w, collectionID)
+	case FormatJSON:
+		return e.JSON(w, collectionID)
+	case FormatCSV:
+		return e.CSV(w, collectionID)
+	}
+
+	return 0, fmt.Errorf("unknown export format '%s'; expected ndjson, json or csv", format)
+}
+
+// NDJSON Write each item of the collection as compacted JSON on its own line.
+func (e *Exporter) NDJSON(w io.Writer, collectionID string) (int, error) {
+	return e.Resume(w, Position{Cursor: webflowAPI.NewCursor(collectionID)})
+}
+
+// Resume Continue an NDJSON export from the position, e.g. one saved by Checkpoint, writing the items after its cursor.
+// w should append to the earlier output truncated to the position's Bytes, dropping any partly written page, e.g. a
+// line cut short by a crash. Returns the number of items written.
+func (e *Exporter) Resume(w io.Writer, from Position) (int, error) {
+	cw := &countingWriter{w: w, n: from.Bytes}
+	bw := bufio.NewWriter(cw)
+	paginator := webflowAPI.NewPaginator(e.api, from.Cursor)
+
+	n := 0
+	for page := 0; !paginator.Done() && (page == 0 || page <= e.MaxPages); page++ {
+		items, err := paginator.Next()
+		if err != nil {
+			return n, err
+		}
+
+		for _, item := range items {
+			if err := writeCompact(bw, item); err != nil {
+				return n, err
+			}
+			bw.WriteString("\n")
+			n++
+		}
+		if err := bw.Flush(); err != nil {
+			return n, err
+		}
+
+		if e.Checkpoint != nil {
+			if err := e.Checkpoint(Position{Cursor: paginator.Cursor(), Bytes: cw.n}); err != nil {
+				return n, fmt.Errorf("unable to checkpoint the export; error: %+v", err)
+			}
+		}
+	}
+
+	return n, nil
+}
+
+// countingWriter Counts the bytes written through it, in addition to n.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// JSON Write the items of the collection as a single JSON array, one item per line.
+func (e *Exporter) JSON(w io.Writer, collectionID string) (int, error) {
+	items, err := e.api.GetAllItemsInCollectionByID(collectionID, e.MaxPages)
+	if err != nil {
+		return 0, err
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("[")
+	for i, item := range items {
+		if i > 0 {
+			bw.WriteString(",")
+		}
+		bw.WriteString("\n")
+		if err := writeCompact(bw, item); err != nil {
+			return i, err
+		}
+	}
+	bw.WriteString("\n]\n")
+
+	return len(items), bw.Flush()
+}
+
+// CSV Write the items of the collection as CSV with a header row of field names. References are written as the
+// names of the referenced items, images & files as their URLs and options as their names.
+func (e *Exporter) CSV(w io.Writer, collectionID string) (int, error) {
+	collection, err := e.api.GetCollectionByID(collectionID)
+	if err != nil {
+		return 0, err
+	}
+	if collection == nil {
+		return 0, fmt.Errorf("collection '%s' not found", collectionID)
+	}
+
+	items, err := e.api.GetAllItemsInCollectionByID(collectionID, e.MaxPages)
+	if err != nil {
+		return 0, err
+	}
+
+	columns := csvColumns(collection.Fields)
+	names := map[string]map[string]string{}
+
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+	if err := cw.Write(header); err != nil {
+		return 0, err
+	}
+
+	for i, raw := range items {
+		item := map[string]json.RawMessage{}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return i, fmt.Errorf("unable to decode item %d; error: %+v", i, err)
+		}
+
+		row := make([]string, len(columns))
+		for j, col := range columns {
+			if row[j], err = e.cell(col.field, item[col.slug], names); err != nil {
+				return i, err
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return i, err
+		}
+	}
+
+	cw.Flush()
+	return len(items), cw.Error()
+}
+
+// column A CSV column and the field it is read from. Metadata columns have no field.
+type column struct {
+	name  string
+	slug  string
+	field *webflowAPI.CollectionField
+}
+
+// csvColumns The item ID, then the collection's fields in order, then the remaining item metadata.
+func csvColumns(fields []webflowAPI.CollectionField) []column {
+	columns := []column{{name: "_id", slug: "_id"}}
+	seen := map[string]bool{"_id": true}
+
+	for i := range fields {
+		field := &fields[i]
+		if seen[field.Slug] {
+			continue
+		}
+		seen[field.Slug] = true
+		columns = append(columns, column{name: field.Slug, slug: field.Slug, field: field})
+	}
+
+	for _, slug := range metadataColumns {
+		if !seen[slug] {
+			seen[slug] = true
+			columns = append(columns, column{name: slug, slug: slug})
+		}
+	}
+
+	return columns
+}
+
+// cell Flatten a field value into a readable CSV cell. names caches the item names of referenced collections.
+func (e *Exporter) cell(field *webflowAPI.CollectionField, raw json.RawMessage, names map[string]map[string]string) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+
+	fieldType := ""
+	if field != nil {
+		fieldType = field.Type
+	}
+
+	switch fieldType {
+	case webflowAPI.FieldTypeItemRef, webflowAPI.FieldTypeItemRefSet:
+		ids := []string{}
+		if err := decodeOneOrMany(raw, &ids); err != nil {
+			return "", err
+		}
+		refNames, err := e.itemNames(field, names)
+		if err != nil {
+			return "", err
+		}
+		for i, id := range ids {
+			if name, ok := refNames[id]; ok {
+				ids[i] = name
+			}
+		}
+		return strings.Join(ids, cellSeparator), nil
+
+	case webflowAPI.FieldTypeImageRef, webflowAPI.FieldTypeExtFileRef, webflowAPI.FieldTypeSet:
+		files := []struct {
+			URL string `json:"url"`
+		}{}
+		if err := decodeOneOrMany(raw, &files); err != nil {
+			return "", err
+		}
+		urls := make([]string, len(files))
+		for i, file := range files {
+			urls[i] = file.URL
+		}
+		return strings.Join(urls, cellSeparator), nil
+
+	case webflowAPI.FieldTypeOption:
+		id := ""
+		if err := json.Unmarshal(raw, &id); err != nil {
+			return "", err
+		}
+		if field.Validations != nil {
+			for _, option := range field.Validations.Options {
+				if option.ID == id {
+					return option.Name, nil
+				}
+			}
+		}
+		return id, nil
+	}
+
+	var val interface{}
+	if err := json.Unmarshal(raw, &val); err != nil {
+		return "", err
+	}
+
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	}
+
+	// Anything else, e.g. video embeds, is kept as JSON.
+	return string(raw), nil
+}
+
+// itemNames Map the IDs of the referenced collection's items to their names, requesting them on first use.
+func (e *Exporter) itemNames(field *webflowAPI.CollectionField, names map[string]map[string]string) (map[string]string, error) {
+	if field.Validations == nil || field.Validations.CollectionID == "" {
+		return nil, nil
+	}
+
+	collectionID := field.Validations.CollectionID
+	if refNames, ok := names[collectionID]; ok {
+		return refNames, nil
+	}
+
+	items, err := e.api.GetAllItemsInCollectionByID(collectionID, e.MaxPages)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the items referenced by field '%s'; error: %+v", field.Slug, err)
+	}
+
+	refNames := map[string]string{}
+	for _, raw := range items {
+		item := struct {
+			ID   string `json:"_id"`
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(raw, &item); err == nil && item.Name != "" {
+			refNames[item.ID] = item.Name
+		}
+	}
+	names[collectionID] = refNames
+
+	return refNames, nil
+}
+
+// decodeOneOrMany Decode a JSON value, or array of values, into the slice pointed to by vals.
+func decodeOneOrMany(raw json.RawMessage, vals interface{}) error {
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		return json.Unmarshal(raw, vals)
+	}
+
+	return json.Unmarshal(append(append([]byte("["), raw...), ']'), vals)
+}
+
+// writeCompact Write JSON without insignificant whitespace.
+func writeCompact(w *bufio.Writer, raw []byte) error {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, raw); err != nil {
+		return err
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,151 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/memory"
+)
+
+func newTestExporter(t *testing.T) *Exporter {
+	store, err := memory.Load("mysiteid", "testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(store)
+}
+
+func TestNDJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	n, err := newTestExporter(t).Export(buf, "posts1", FormatNDJSON)
+	if err != nil || n != 2 {
+		t.Fatalf("Export() is expected to write both items! Got %d; error %+v.", n, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"_id":"p1"`) {
+		t.Errorf("Export() is expected to write one compacted item per line! Got %s", buf.String())
+	}
+}
+
+// failingStore Fails to read the page at the given offset.
+type failingStore struct {
+	*memory.Store
+	offset string
+}
+
+func (s *failingStore) MethodGet(uri string, queryParams map[string]string, decodedResponse interface{}) error {
+	if queryParams["offset"] == s.offset {
+		return errors.New("Service Unavailable")
+	}
+	return s.Store.MethodGet(uri, queryParams, decodedResponse)
+}
+
+func TestResume(t *testing.T) {
+	store, err := memory.Load("mysiteid", "testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.PageSize = 1
+
+	checkpoints := []Position{}
+	e := New(&failingStore{Store: store, offset: "1"})
+	e.Checkpoint = func(position Position) error {
+		checkpoints = append(checkpoints, position)
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	if n, err := e.Export(buf, "posts1", FormatNDJSON); err == nil || n != 1 {
+		t.Fatalf("Export() is expected to fail on the second page! Got %d; error %+v.", n, err)
+	}
+	expected := []Position{{Cursor: webflowAPI.Cursor{CollectionID: "posts1", Offset: 1, Total: 2}, Bytes: int64(buf.Len())}}
+	if !reflect.DeepEqual(checkpoints, expected) {
+		t.Fatalf("Export() is expected to checkpoint after the first page & its output! Got %+v.", checkpoints)
+	}
+
+	// A page partly written before a crash is dropped by truncating to the checkpoint.
+	buf.WriteString(`{"_id":"p2","na`)
+	buf.Truncate(int(checkpoints[0].Bytes))
+
+	e = New(store)
+	e.Checkpoint = func(position Position) error {
+		checkpoints = append(checkpoints, position)
+		return nil
+	}
+	n, err := e.Resume(buf, checkpoints[0])
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if err != nil || n != 1 || len(lines) != 2 || !strings.Contains(lines[1], `"_id":"p2"`) {
+		t.Errorf("Resume() is expected to write the remaining item! Got %d; %s; error %+v.", n, buf.String(), err)
+	}
+	if last := checkpoints[len(checkpoints)-1]; last.Bytes != int64(buf.Len()) {
+		t.Errorf("Resume() is expected to count the output from the checkpoint's! Got %d of %d bytes.", last.Bytes, buf.Len())
+	}
+
+	if n, err := e.Resume(buf, Position{Cursor: webflowAPI.Cursor{CollectionID: "posts1", Offset: 2, Total: 2}}); err != nil || n != 0 {
+		t.Errorf("Resume() is expected to write nothing once done! Got %d; error %+v.", n, err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if _, err := newTestExporter(t).Export(buf, "posts1", FormatJSON); err != nil {
+		t.Fatalf("Export() is expected to write the items: %+v", err)
+	}
+
+	items := []map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &items); err != nil || len(items) != 2 || items[1]["_id"] != "p2" {
+		t.Errorf("Export() is expected to write a JSON array of the items! Got %s; error %+v.", buf.String(), err)
+	}
+
+	buf.Reset()
+	if _, err := newTestExporter(t).Export(buf, "authors1", "xml"); err == nil {
+		t.Error("Export() is expected to error for an unknown format.")
+	}
+}
+
+func TestCSV(t *testing.T) {
+	buf := &bytes.Buffer{}
+	if _, err := newTestExporter(t).Export(buf, "posts1", FormatCSV); err != nil {
+		t.Fatalf("Export() is expected to write the items: %+v", err)
+	}
+
+	rows, err := csv.NewReader(buf).ReadAll()
+	if err != nil || len(rows) != 3 {
+		t.Fatalf("Export() is expected to write a header & a row per item! Got %+v; error %+v.", rows, err)
+	}
+
+	expected := map[string][]string{
+		"_id":      {"p1", "p2"},
+		"name":     {"Hello, world", "Second"},
+		"author":   {"Ada", "gone"},
+		"editors":  {"Ada; Grace", ""},
+		"image":    {"https://example.com/hello.png", ""},
+		"category": {"Opinion", ""},
+		"views":    {"1200", ""},
+		"featured": {"true", ""},
+		"_draft":   {"false", ""},
+	}
+	for col, header := range rows[0] {
+		vals, ok := expected[header]
+		if !ok {
+			continue
+		}
+		delete(expected, header)
+		for i, val := range vals {
+			if rows[i+1][col] != val {
+				t.Errorf("Column '%s' of row %d is expected to be '%s'! Got '%s'.", header, i+1, val, rows[i+1][col])
+			}
+		}
+	}
+	if len(expected) != 0 {
+		t.Errorf("Export() is missing the columns %+v! Got header %+v.", expected, rows[0])
+	}
+}
@@ -0,0 +1,546 @@
+// Package importer Create or update the items of a collection from CSV or NDJSON, the inverse of the export pkg.
+// Columns are mapped to field slugs, values are coerced to the collection's field types and reference columns are
+// resolved by the slug or name of the referenced item. A dry run reports what would change without writing.
+package importer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/internal/object"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Formats accepted by Importer.Import.
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+
+	// Actions reported for each record.
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionError  = "error"
+
+	// DefaultKey Field used to match records to existing items.
+	DefaultKey = "slug"
+	// DefaultMaxPages Number of additional pages of items requested per collection.
+	DefaultMaxPages = 100
+
+	// Separator between the values of multi-value cells, as written by the export pkg. Surrounding space is trimmed.
+	cellSeparator = ";"
+
+	timeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+	// toBeCreated Stands in, during a dry run, for the ID of an item an earlier record would create.
+	toBeCreated = "\x00create"
+)
+
+// Layouts accepted for date fields, tried in order.
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02", "01/02/2006"}
+
+// Mapping How the columns of a file relate to the collection's fields, e.g.:
+//
+//	key: slug
+//	columns:
+//	  Title: name
+//	  Written By: author
+//
+// Columns that are not mapped are matched to fields by slug, then by name. Any others are ignored.
+type Mapping struct {
+	// Key Slug of the field used to match records to existing items. A record with an _id is matched by ID.
+	Key string `yaml:"key"`
+	// Columns Field slug for each column name.
+	Columns map[string]string `yaml:"columns"`
+}
+
+// Result What happened, or would happen in a dry run, to one record.
+type Result struct {
+	// Row 1-based number of the record, not counting the CSV header.
+	Row    int                    `json:"row"`
+	Action string                 `json:"action"`
+	ItemID string                 `json:"itemId,omitempty"`
+	Key    string                 `json:"key,omitempty"`
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// Report The outcome of an import.
+type Report struct {
+	DryRun bool `json:"dryRun"`
+	// Ignored Columns that did not match a field.
+	Ignored []string `json:"ignored,omitempty"`
+	Results []Result `json:"results"`
+}
+
+// Count The number of records with the given action.
+func (report *Report) Count(action string) int {
+	n := 0
+	for _, result := range report.Results {
+		if result.Action == action {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Importer Writes records to collections.
+type Importer struct {
+	// Mapping Column to field mapping. Defaults to matching columns by field slug or name.
+	Mapping *Mapping
+	// DryRun Report the changes without making them.
+	DryRun bool
+	// Live Publish the created & updated items immediately.
+	Live bool
+	// MaxPages Number of additional pages of items to request. See webflowAPI.Interface.GetAllItemsInCollectionByID.
+	MaxPages int
+
+	api webflowAPI.Interface
+}
+
+// New Create an importer writing collections through the given API.
+func New(api webflowAPI.Interface) *Importer {
+	return &Importer{
+		Mapping:  &Mapping{},
+		MaxPages: DefaultMaxPages,
+		api:      api,
+	}
+}
+
+// LoadMapping Read a YAML, or JSON, mapping file.
+func LoadMapping(path string) (*Mapping, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := &Mapping{}
+	if err := yaml.UnmarshalStrict(data, mapping); err != nil {
+		return nil, fmt.Errorf("unable to decode the mapping file %s; error: %+v", path, err)
+	}
+
+	return mapping, nil
+}
+
+// Import Read records in the given format into the collection. Records that fail are reported rather than stopping
+// the import; the error is only for problems reading the file or the collection.
+func (im *Importer) Import(r io.Reader, collectionID, format string) (*Report, error) {
+	switch format {
+	case FormatCSV:
+		return im.CSV(r, collectionID)
+	case FormatNDJSON:
+		return im.NDJSON(r, collectionID)
+	}
+
+	return nil, fmt.Errorf("unknown import format '%s'; expected csv or ndjson", format)
+}
+
+// CSV Read records from CSV with a header row of column names.
+func (im *Importer) CSV(r io.Reader, collectionID string) (*Report, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("the CSV has no header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := []map[string]interface{}{}
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record := map[string]interface{}{}
+		for i, val := range row {
+			record[header[i]] = val
+		}
+		records = append(records, record)
+	}
+
+	return im.write(collectionID, header, records)
+}
+
+// NDJSON Read records from one JSON object per line.
+func (im *Importer) NDJSON(r io.Reader, collectionID string) (*Report, error) {
+	columns := []string{}
+	seen := map[string]bool{}
+	records := []map[string]interface{}{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		record := map[string]interface{}{}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("unable to decode line %d; error: %+v", line, err)
+		}
+		for column := range record {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return im.write(collectionID, columns, records)
+}
+
+// write Map, coerce & write each record.
+func (im *Importer) write(collectionID string, columns []string, records []map[string]interface{}) (*Report, error) {
+	collection, err := im.api.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("collection '%s' not found", collectionID)
+	}
+
+	report := &Report{DryRun: im.DryRun, Results: []Result{}}
+	fields := im.mapColumns(collection.Fields, columns, report)
+
+	key := DefaultKey
+	if im.Mapping != nil && im.Mapping.Key != "" {
+		key = im.Mapping.Key
+	}
+
+	refs := &references{im: im, ids: map[string]map[string]string{}}
+	existing, err := refs.index(collectionID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, record := range records {
+		result := Result{Row: i + 1, Fields: map[string]interface{}{}}
+
+		if err := coerceRecord(record, fields, key, refs, &result); err != nil {
+			result.Action = ActionError
+			result.Error = err.Error()
+			report.Results = append(report.Results, result)
+			continue
+		}
+
+		if result.ItemID == "" && result.Key != "" {
+			result.ItemID = existing[result.Key]
+		}
+
+		var item []byte
+		if result.ItemID == "" {
+			result.Action = ActionCreate
+			if err = checkRequired(collection.Fields, result.Fields); err == nil && !im.DryRun {
+				item, err = im.api.CreateItem(collectionID, object.WithDefaults(result.Fields), im.Live)
+			}
+		} else {
+			result.Action = ActionUpdate
+			if result.ItemID == toBeCreated {
+				// Created by an earlier record of this dry run.
+				result.ItemID = ""
+			} else if !im.DryRun {
+				item, err = im.api.PatchItem(collectionID, result.ItemID, result.Fields, im.Live)
+			}
+		}
+		if err != nil {
+			result.Action = ActionError
+			result.Error = err.Error()
+		} else if item != nil {
+			created := struct {
+				ID string `json:"_id"`
+			}{}
+			json.Unmarshal(item, &created)
+			result.ItemID = created.ID
+		}
+
+		// Later records with the same key update rather than duplicate this one.
+		if result.Action == ActionCreate && result.Key != "" {
+			existing[result.Key] = result.ItemID
+			if im.DryRun {
+				existing[result.Key] = toBeCreated
+			}
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+// mapColumns The field of each column, by the mapping, then slug, then name. Unmatched columns are reported.
+func (im *Importer) mapColumns(fields []webflowAPI.CollectionField, columns []string, report *Report) map[string]*webflowAPI.CollectionField {
+	bySlug := map[string]*webflowAPI.CollectionField{}
+	byName := map[string]*webflowAPI.CollectionField{}
+	for i := range fields {
+		bySlug[fields[i].Slug] = &fields[i]
+		byName[strings.ToLower(fields[i].Name)] = &fields[i]
+	}
+
+	mapped := map[string]*webflowAPI.CollectionField{}
+	for _, column := range columns {
+		slug := column
+		if im.Mapping != nil && im.Mapping.Columns[column] != "" {
+			slug = im.Mapping.Columns[column]
+		}
+
+		switch {
+		case bySlug[slug] != nil:
+			mapped[column] = bySlug[slug]
+		case byName[strings.ToLower(slug)] != nil:
+			mapped[column] = byName[strings.ToLower(slug)]
+		case slug == "_id" || slug == "_draft" || slug == "_archived":
+			mapped[column] = &webflowAPI.CollectionField{Slug: slug, Type: metadataType(slug)}
+		default:
+			report.Ignored = append(report.Ignored, column)
+		}
+	}
+
+	return mapped
+}
+
+// coerceRecord Convert the record's mapped values into item fields, noting the item ID & key in the result.
+func coerceRecord(record map[string]interface{}, fields map[string]*webflowAPI.CollectionField, key string, refs *references, result *Result) error {
+	for column, val := range record {
+		field, ok := fields[column]
+		if !ok || isEmpty(val) {
+			continue
+		}
+
+		coerced, err := coerce(field, val, refs)
+		if err != nil {
+			return fmt.Errorf("column '%s': %+v", column, err)
+		}
+
+		switch field.Slug {
+		case "_id":
+			result.ItemID = fmt.Sprint(coerced)
+		case key:
+			result.Key = fmt.Sprint(coerced)
+			result.Fields[field.Slug] = coerced
+		default:
+			result.Fields[field.Slug] = coerced
+		}
+	}
+
+	return nil
+}
+
+// coerce Convert a value to the field's type. Strings are parsed; other JSON values are passed through.
+func coerce(field *webflowAPI.CollectionField, val interface{}, refs *references) (interface{}, error) {
+	s, isString := val.(string)
+	if isString {
+		s = strings.TrimSpace(s)
+	}
+
+	switch field.Type {
+	case webflowAPI.FieldTypeNumber:
+		if !isString {
+			return val, nil
+		}
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", s)
+		}
+		return n, nil
+
+	case webflowAPI.FieldTypeBool:
+		if !isString {
+			return val, nil
+		}
+		switch strings.ToLower(s) {
+		case "yes", "y":
+			return true, nil
+		case "no", "n":
+			return false, nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not true or false", s)
+		}
+		return b, nil
+
+	case webflowAPI.FieldTypeDate:
+		if !isString {
+			return val, nil
+		}
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t.UTC().Format(timeFormat), nil
+			}
+		}
+		return nil, fmt.Errorf("'%s' is not a date", s)
+
+	case webflowAPI.FieldTypeOption:
+		if !isString {
+			return val, nil
+		}
+		if field.Validations != nil {
+			for _, option := range field.Validations.Options {
+				if option.ID == s || strings.EqualFold(option.Name, s) {
+					return option.ID, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("'%s' is not an option of field '%s'", s, field.Slug)
+
+	case webflowAPI.FieldTypeItemRef:
+		if !isString {
+			return val, nil
+		}
+		return refs.resolve(field, s)
+
+	case webflowAPI.FieldTypeItemRefSet:
+		ids := []string{}
+		for _, ref := range splitValues(val) {
+			id, err := refs.resolve(field, ref)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return ids, nil
+
+	case webflowAPI.FieldTypeImageRef, webflowAPI.FieldTypeExtFileRef:
+		if !isString {
+			return val, nil
+		}
+		return map[string]interface{}{"url": s}, nil
+
+	case webflowAPI.FieldTypeSet:
+		if !isString {
+			return val, nil
+		}
+		files := []map[string]interface{}{}
+		for _, url := range splitValues(s) {
+			files = append(files, map[string]interface{}{"url": url})
+		}
+		return files, nil
+	}
+
+	return val, nil
+}
+
+// references Resolves references by the slug, name or ID of the referenced item.
+type references struct {
+	im *Importer
+	// ids Item ID by slug, name & ID for each referenced collection.
+	ids map[string]map[string]string
+}
+
+// resolve The ID of the item the reference names.
+func (refs *references) resolve(field *webflowAPI.CollectionField, ref string) (string, error) {
+	if field.Validations == nil || field.Validations.CollectionID == "" {
+		return ref, nil
+	}
+
+	collectionID := field.Validations.CollectionID
+	ids, ok := refs.ids[collectionID]
+	if !ok {
+		var err error
+		if ids, err = refs.index(collectionID, "name", "slug", "_id"); err != nil {
+			return "", err
+		}
+		refs.ids[collectionID] = ids
+	}
+
+	if id, ok := ids[ref]; ok {
+		return id, nil
+	}
+
+	return "", fmt.Errorf("no item with the slug, name or ID '%s' in the collection referenced by field '%s'", ref, field.Slug)
+}
+
+// index Map the values of the given fields of the collection's items to the item IDs. A value is kept for the first
+// field, then item, it is found in.
+func (refs *references) index(collectionID string, keys ...string) (map[string]string, error) {
+	items, err := refs.im.api.GetAllItemsInCollectionByID(collectionID, refs.im.MaxPages)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the items of collection '%s'; error: %+v", collectionID, err)
+	}
+
+	ids := map[string]string{}
+	for _, raw := range items {
+		item := map[string]interface{}{}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			continue
+		}
+		id, _ := item["_id"].(string)
+		for _, k := range keys {
+			if val, ok := item[k]; ok && val != nil && val != "" {
+				if _, taken := ids[fmt.Sprint(val)]; !taken {
+					ids[fmt.Sprint(val)] = id
+				}
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// checkRequired Error when a field the collection requires has no value.
+func checkRequired(fields []webflowAPI.CollectionField, item map[string]interface{}) error {
+	missing := []string{}
+	for _, field := range fields {
+		if _, ok := item[field.Slug]; field.Required && !ok {
+			missing = append(missing, field.Slug)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New("missing required fields: " + strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// metadataType The field type of item metadata that may be imported.
+func metadataType(slug string) string {
+	if slug == "_id" {
+		return webflowAPI.FieldTypePlainText
+	}
+
+	return webflowAPI.FieldTypeBool
+}
+
+// splitValues The values of a multi-value cell, or of a JSON array.
+func splitValues(val interface{}) []string {
+	vals := []string{}
+
+	switch v := val.(type) {
+	case string:
+		for _, s := range strings.Split(v, cellSeparator) {
+			if s = strings.TrimSpace(s); s != "" {
+				vals = append(vals, s)
+			}
+		}
+	case []interface{}:
+		for _, s := range v {
+			vals = append(vals, fmt.Sprint(s))
+		}
+	}
+
+	return vals
+}
+
+// isEmpty Whether a value is missing, so the field is left unchanged.
+func isEmpty(val interface{}) bool {
+	s, ok := val.(string)
+	return val == nil || ok && strings.TrimSpace(s) == ""
+}
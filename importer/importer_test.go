@@ -0,0 +1,148 @@
+package importer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/redeemed2011/webflowAPI/memory"
+)
+
+const (
+	exampleCSV = `Title,slug,Written By,Editors,Category,Views,Featured,Notes
+Hello again,hello,Grace,,news,,,ignored
+New post,new-post,ada,ada; Grace,Opinion,12,yes,
+Broken,broken,nobody,,,,,
+Bad number,bad-number,,,,lots,,
+,no-name,,,,,,
+`
+)
+
+func newTestImporter(t *testing.T) (*Importer, *memory.Store) {
+	store, err := memory.Load("mysiteid", "testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	im := New(store)
+	if im.Mapping, err = LoadMapping("testdata/mapping.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	return im, store
+}
+
+func TestCSVDryRun(t *testing.T) {
+	im, store := newTestImporter(t)
+	im.DryRun = true
+
+	report, err := im.Import(strings.NewReader(exampleCSV), "posts1", FormatCSV)
+	if err != nil {
+		t.Fatalf("Import() is expected to read the CSV: %+v", err)
+	}
+
+	if report.Count(ActionUpdate) != 1 || report.Count(ActionCreate) != 1 || report.Count(ActionError) != 3 {
+		t.Errorf("Import() is expected to report 1 update, 1 create & 3 errors! Got %+v.", report.Results)
+	}
+	if len(report.Ignored) != 1 || report.Ignored[0] != "Notes" {
+		t.Errorf("Import() is expected to report the unmapped column! Got %+v.", report.Ignored)
+	}
+
+	created := report.Results[1]
+	if created.Fields["author"] != "a1" || created.Fields["category"] != "o2" || created.Fields["views"] != 12.0 || created.Fields["featured"] != true {
+		t.Errorf("Import() is expected to coerce the values to the field types! Got %+v.", created.Fields)
+	}
+	if editors, _ := created.Fields["editors"].([]string); len(editors) != 2 || editors[1] != "a2" {
+		t.Errorf("Import() is expected to resolve reference sets by slug & name! Got %+v.", created.Fields["editors"])
+	}
+
+	for _, i := range []int{2, 3, 4} {
+		if report.Results[i].Error == "" {
+			t.Errorf("Row %d is expected to fail! Got %+v.", i+1, report.Results[i])
+		}
+	}
+
+	if items, _ := store.GetAllItemsInCollectionByID("posts1", 0); len(items) != 2 {
+		t.Errorf("A dry run is expected not to write! Got %d items.", len(items))
+	}
+}
+
+func TestCSV(t *testing.T) {
+	im, store := newTestImporter(t)
+
+	report, err := im.Import(strings.NewReader(exampleCSV), "posts1", FormatCSV)
+	if err != nil || report.Count(ActionCreate) != 1 || report.Count(ActionUpdate) != 1 {
+		t.Fatalf("Import() is expected to create & update items! Got %+v; error %+v.", report, err)
+	}
+
+	updated, _ := store.GetItem("", "", "posts1", "", "p1")
+	item := map[string]interface{}{}
+	json.Unmarshal(updated, &item)
+	if item["name"] != "Hello again" || item["author"] != "a2" || item["views"] != 1200.0 {
+		t.Errorf("Import() is expected to patch the existing item! Got %+v.", item)
+	}
+
+	created, _ := store.GetItem("", "", "posts1", "New post", "")
+	if created == nil || report.Results[1].ItemID == "" {
+		t.Errorf("Import() is expected to create the new item & report its ID! Got %+v.", report.Results[1])
+	}
+}
+
+func TestNDJSON(t *testing.T) {
+	im, store := newTestImporter(t)
+	im.Mapping = &Mapping{}
+
+	ndjson := `{"name":"Third","slug":"third","editors":["Ada"],"views":3}
+{"_id":"p2","name":"Second, edited"}
+`
+	report, err := im.Import(strings.NewReader(ndjson), "posts1", FormatNDJSON)
+	if err != nil || report.Count(ActionCreate) != 1 || report.Count(ActionUpdate) != 1 {
+		t.Fatalf("Import() is expected to create & update items! Got %+v; error %+v.", report, err)
+	}
+
+	if item, _ := store.GetItem("", "", "posts1", "", "p2"); !strings.Contains(string(item), "Second, edited") {
+		t.Errorf("Import() is expected to update the item matched by _id! Got %s.", item)
+	}
+
+	if _, err := im.Import(strings.NewReader(ndjson), "posts1", "xlsx"); err == nil {
+		t.Error("Import() is expected to error for an unknown format.")
+	}
+}
+
+func TestKey(t *testing.T) {
+	im, store := newTestImporter(t)
+	im.Mapping = &Mapping{Key: "name"}
+
+	// A key equal to another item's slug or ID does not match that item.
+	ndjson := `{"name":"hello","slug":"hello-by-name"}
+{"name":"p2","slug":"p2-by-name"}
+{"name":"Second","slug":"second-renamed"}
+`
+	report, err := im.Import(strings.NewReader(ndjson), "posts1", FormatNDJSON)
+	if err != nil || report.Count(ActionCreate) != 2 || report.Count(ActionUpdate) != 1 {
+		t.Fatalf("Import() is expected to match records by the key field only! Got %+v; error %+v.", report.Results, err)
+	}
+
+	if item, _ := store.GetItem("", "", "posts1", "", "p1"); !strings.Contains(string(item), `"Hello, world"`) {
+		t.Errorf("Import() is expected not to patch the item whose slug equals a key! Got %s.", item)
+	}
+	if item, _ := store.GetItem("", "", "posts1", "", "p2"); !strings.Contains(string(item), "second-renamed") {
+		t.Errorf("Import() is expected to patch the item matched by the key! Got %s.", item)
+	}
+}
+
+func TestDryRunRepeatedKey(t *testing.T) {
+	im, _ := newTestImporter(t)
+	im.DryRun = true
+
+	ndjson := `{"name":"Third","slug":"third"}
+{"name":"Third, edited","slug":"third"}
+`
+	report, err := im.Import(strings.NewReader(ndjson), "posts1", FormatNDJSON)
+	if err != nil || report.Count(ActionCreate) != 1 || report.Count(ActionUpdate) != 1 {
+		t.Fatalf("Import() is expected to plan a create then an update for a repeated key! Got %+v; error %+v.", report.Results, err)
+	}
+	if report.Results[1].ItemID != "" {
+		t.Errorf("Import() is expected not to report an ID for an item a dry run would create! Got %+v.", report.Results[1])
+	}
+}
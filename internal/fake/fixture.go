@@ -0,0 +1,44 @@
+// Package fake Content & rules shared by the fake Webflow sites: the in-memory store in package memory and the HTTP
+// server in package webflowtest. Both load the same fixtures and accept or reject items the same way the API does.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+// Fixture Initial content of a fake Webflow site.
+type Fixture struct {
+	Sites []SiteFixture `json:"sites"`
+}
+
+// SiteFixture A site along with its collections.
+type SiteFixture struct {
+	webflowAPI.Site
+	Collections []CollectionFixture `json:"collections"`
+	Webhooks    webflowAPI.Webhooks `json:"webhooks"`
+}
+
+// CollectionFixture A collection, including its fields, along with its items.
+type CollectionFixture struct {
+	webflowAPI.Collection
+	Items []json.RawMessage `json:"items"`
+}
+
+// LoadFixture Read a fixture from a JSON file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read fixture; error: %+v", err)
+	}
+
+	fixture := &Fixture{}
+	if err := json.Unmarshal(data, fixture); err != nil {
+		return nil, fmt.Errorf("unable to decode fixture %s; error: %+v", path, err)
+	}
+
+	return fixture, nil
+}
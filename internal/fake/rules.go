@@ -0,0 +1,72 @@
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+// Validate Check an item has a name, a unique slug and every required field, as the API would. items are the other
+// items of the collection & id is the item's own ID, empty for a new item.
+func Validate(info *webflowAPI.Collection, items []map[string]interface{}, item map[string]interface{}, id string) error {
+	missing := []string{}
+	for _, slug := range []string{"name", "slug"} {
+		if val, _ := item[slug].(string); val == "" {
+			missing = append(missing, slug)
+		}
+	}
+	for _, field := range info.Fields {
+		if field.Slug == "name" || field.Slug == "slug" || !field.Required {
+			continue
+		}
+		if val, ok := item[field.Slug]; !ok || val == nil || val == "" {
+			missing = append(missing, field.Slug)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New("ValidationError: missing required fields: " + strings.Join(missing, ", "))
+	}
+
+	for _, other := range items {
+		if other["slug"] == item["slug"] && other["_id"] != id {
+			return fmt.Errorf("ValidationError: slug '%s' is already in use", item["slug"])
+		}
+	}
+
+	return nil
+}
+
+// FindField Index of a field by ID or slug; -1 when not found.
+func FindField(info *webflowAPI.Collection, ref string) int {
+	for i, field := range info.Fields {
+		if field.ID == ref || field.Slug == ref {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// BuiltinFields The fields Webflow adds to every collection.
+func BuiltinFields() []webflowAPI.FieldDefinition {
+	return []webflowAPI.FieldDefinition{
+		{Name: "Name", Slug: "name", Type: webflowAPI.FieldTypePlainText, Required: true},
+		{Name: "Slug", Slug: "slug", Type: webflowAPI.FieldTypePlainText, Required: true},
+	}
+}
+
+// Slugify A slug made from a name, e.g. "Blog Posts" is blog-posts.
+func Slugify(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	})
+
+	return strings.Join(words, "-")
+}
+
+// NewID An ID shaped like the ones Webflow uses, made from a counter.
+func NewID(n int) string {
+	return fmt.Sprintf("%024x", n)
+}
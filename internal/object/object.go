@@ -0,0 +1,79 @@
+// Package object Helpers for items held as decoded JSON objects.
+package object
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// IsMetadata Whether the field is item metadata maintained by Webflow rather than content.
+func IsMetadata(key string) bool {
+	switch key {
+	case "_id", "_cid", "created-on", "created-by", "updated-on", "updated-by", "published-on", "published-by":
+		return true
+	}
+
+	return false
+}
+
+// WithDefaults Add the metadata fields Webflow requires when creating an item.
+func WithDefaults(fields map[string]interface{}) map[string]interface{} {
+	item := map[string]interface{}{"_archived": false, "_draft": false}
+	for key, val := range fields {
+		item[key] = val
+	}
+
+	return item
+}
+
+// SetDefault Set the field only when it has not been provided.
+func SetDefault(item map[string]interface{}, key string, val interface{}) {
+	if _, ok := item[key]; !ok {
+		item[key] = val
+	}
+}
+
+// Decode Decode a JSON object, keeping numbers exactly as they were given.
+func Decode(raw []byte) (map[string]interface{}, error) {
+	obj := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, err
+	}
+
+	// A JSON null leaves the map nil.
+	if obj == nil {
+		obj = map[string]interface{}{}
+	}
+
+	return obj, nil
+}
+
+// ID The _id of a raw item.
+func ID(raw []byte) string {
+	item := struct {
+		ID string `json:"_id"`
+	}{}
+	json.Unmarshal(raw, &item)
+
+	return item.ID
+}
+
+// Str A value as a string; empty when it is not one.
+func Str(val interface{}) string {
+	s, _ := val.(string)
+	return s
+}
+
+// SortedKeys The keys of an object in order.
+func SortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
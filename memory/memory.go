@@ -0,0 +1,729 @@
+// Package memory In-memory implementation of webflowAPI.Interface for unit tests. Collections & items are held in maps
+// and looked up with the same semantics as the real client: case insensitive collection names & slugs, nil results
+// when nothing is found and maxPages limiting how many pages of items are returned.
+package memory
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/internal/fake"
+	"github.com/redeemed2011/webflowAPI/internal/object"
+)
+
+const (
+	// DefaultPageSize Number of items the Webflow API returns per page.
+	DefaultPageSize = 100
+	// Format Webflow uses for item timestamps.
+	timeFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// Store In-memory Webflow site.
+type Store struct {
+	// PageSize Number of items per simulated page. Used with maxPages to limit the number of items returned.
+	PageSize int
+	SiteID   string
+
+	mu          sync.RWMutex
+	site        webflowAPI.Site
+	published   [][]string
+	order       []string
+	collections map[string]*collection
+	nextID      int
+	// Override for the clock. Use only for internal testing of the pkg.
+	now func() time.Time
+}
+
+// collection A collection and its items, in the order they were added.
+type collection struct {
+	info  webflowAPI.Collection
+	items []map[string]interface{}
+}
+
+// Ensure the store can stand in for the real client.
+var _ webflowAPI.Interface = &Store{}
+
+// New Create an empty store for the given site.
+func New(siteID string) *Store {
+	return &Store{
+		PageSize:    DefaultPageSize,
+		SiteID:      siteID,
+		site:        webflowAPI.Site{ID: siteID},
+		collections: map[string]*collection{},
+		nextID:      1,
+		now:         time.Now,
+	}
+}
+
+// Load Create a store seeded with the given site's collections & items from one or more webflowtest fixture files.
+func Load(siteID string, paths ...string) (*Store, error) {
+	store := New(siteID)
+
+	for _, path := range paths {
+		fixture, err := fake.LoadFixture(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := store.Seed(fixture); err != nil {
+			return nil, fmt.Errorf("unable to seed from %s; error: %+v", path, err)
+		}
+	}
+
+	return store, nil
+}
+
+// Seed Add the store's site's collections & items from a fixture.
+func (s *Store) Seed(fixture *fake.Fixture) error {
+	for _, site := range fixture.Sites {
+		if site.ID != s.SiteID {
+			continue
+		}
+
+		s.mu.Lock()
+		s.site = site.Site
+		s.mu.Unlock()
+
+		for _, cf := range site.Collections {
+			s.AddCollection(cf.Collection)
+			for _, item := range cf.Items {
+				if err := s.AddItems(cf.ID, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddCollection Add a collection, replacing any collection with the same ID. Its items are kept.
+func (s *Store) AddCollection(info webflowAPI.Collection) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c, ok := s.collections[info.ID]; ok {
+		c.info = info
+		return
+	}
+
+	s.order = append(s.order, info.ID)
+	s.collections[info.ID] = &collection{info: info}
+}
+
+// AddItems Add raw JSON items to a collection as is, without validation.
+func (s *Store) AddItems(collectionID string, items ...[]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionID]
+	if !ok {
+		return fmt.Errorf("collection %s does not exist", collectionID)
+	}
+
+	for _, raw := range items {
+		item, err := object.Decode(raw)
+		if err != nil {
+			return fmt.Errorf("item is not a JSON object; error: %+v", err)
+		}
+		c.items = append(c.items, item)
+	}
+
+	return nil
+}
+
+// MethodGet Emulate a HTTP GET for the collection list, a single collection and the collection items URIs.
+func (s *Store) MethodGet(uri string, queryParams map[string]string, decodedResponse interface{}) error {
+	s.mu.RLock()
+	parts := strings.Split(strings.Trim(uri, "/"), "/")
+
+	var res interface{}
+	switch {
+	case len(parts) == 3 && parts[0] == "sites" && parts[2] == "collections":
+		if parts[1] != s.SiteID {
+			s.mu.RUnlock()
+			return errors.New("Site not found")
+		}
+		res = s.allCollections()
+	case len(parts) == 2 && parts[0] == "collections":
+		c, ok := s.collections[parts[1]]
+		if !ok {
+			s.mu.RUnlock()
+			return errors.New("Collection not found")
+		}
+		res = c.info
+	case len(parts) == 3 && parts[0] == "collections" && parts[2] == "items":
+		c, ok := s.collections[parts[1]]
+		if !ok {
+			s.mu.RUnlock()
+			return errors.New("Collection not found")
+		}
+		offset, _ := strconv.Atoi(queryParams["offset"])
+		limit, err := strconv.Atoi(queryParams["limit"])
+		if err != nil || limit <= 0 || limit > s.PageSize {
+			limit = s.PageSize
+		}
+		res = c.page(offset, limit)
+	default:
+		s.mu.RUnlock()
+		return fmt.Errorf("memory.Store does not support GET %s", uri)
+	}
+	s.mu.RUnlock()
+
+	// Round trip through JSON so the caller receives the same shapes the API would send.
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, decodedResponse)
+}
+
+// GetAllSites The store's site.
+func (s *Store) GetAllSites() (*webflowAPI.Sites, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return &webflowAPI.Sites{s.site}, nil
+}
+
+// PublishSite Record that the site was published to the given domains.
+func (s *Store) PublishSite(domains []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.published = append(s.published, domains)
+	s.site.LastPublished = s.now().UTC()
+
+	return nil
+}
+
+// Published The domains given to each PublishSite call, in order.
+func (s *Store) Published() [][]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([][]string{}, s.published...)
+}
+
+// GetAllCollections All the collections of the site, without their fields, as the API reports them.
+func (s *Store) GetAllCollections() (*webflowAPI.Collections, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	collections := s.allCollections()
+	return &collections, nil
+}
+
+// GetCollectionByID Find a collection, including its fields, by ID. Like the API, a missing collection is an error.
+func (s *Store) GetCollectionByID(ID string) (*webflowAPI.Collection, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.collections[ID]
+	if !ok {
+		return nil, errors.New("Collection not found")
+	}
+
+	info := c.info
+	return &info, nil
+}
+
+// GetCollectionByName Find a collection by name, case insensitive. Returns nil when it does not exist.
+func (s *Store) GetCollectionByName(name string) (*webflowAPI.Collection, error) {
+	collections, _ := s.GetAllCollections()
+
+	for _, collection := range *collections {
+		if strings.ToLower(collection.Name) == strings.ToLower(name) {
+			return &collection, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetCollectionBySlug Find a collection by slug, case insensitive. Returns nil when it does not exist.
+func (s *Store) GetCollectionBySlug(slug string) (*webflowAPI.Collection, error) {
+	collections, _ := s.GetAllCollections()
+
+	for _, collection := range *collections {
+		if strings.ToLower(collection.Slug) == strings.ToLower(slug) {
+			return &collection, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetAllItemsInCollectionByID The raw JSON of a collection's items. Like the real client, only maxPages+1 pages of
+// PageSize items are returned.
+func (s *Store) GetAllItemsInCollectionByID(ID string, maxPages int) ([][]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.collections[ID]
+	if !ok {
+		return nil, errors.New("Collection not found")
+	}
+
+	if maxPages < 0 {
+		maxPages = 0
+	}
+
+	items := [][]byte{}
+	for i, item := range c.items {
+		if i >= (maxPages+1)*s.PageSize {
+			break
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, data)
+	}
+
+	return items, nil
+}
+
+// GetAllItemsInCollectionByName The raw JSON of the items of a collection found by name, case insensitive.
+func (s *Store) GetAllItemsInCollectionByName(name string, maxPages int) ([][]byte, error) {
+	collection, _ := s.GetCollectionByName(name)
+	if collection == nil {
+		return nil, nil
+	}
+
+	return s.GetAllItemsInCollectionByID(collection.ID, maxPages)
+}
+
+// GetAllItemsInCollectionBySlug The raw JSON of the items of a collection found by slug, case insensitive.
+func (s *Store) GetAllItemsInCollectionBySlug(slug string, maxPages int) ([][]byte, error) {
+	collection, _ := s.GetCollectionBySlug(slug)
+	if collection == nil {
+		return nil, nil
+	}
+
+	return s.GetAllItemsInCollectionByID(collection.ID, maxPages)
+}
+
+// GetItem Search the items of a collection for the desired item name or ID. See webflowAPI.Interface.GetItem.
+func (s *Store) GetItem(cName, cSlug, cID, iName, iID string) ([]byte, error) {
+	if (cName == "" && cSlug == "" && cID == "") || (iName == "" && iID == "") {
+		return nil, nil
+	}
+
+	var items [][]byte
+	var err error
+
+	if cName != "" {
+		items, err = s.GetAllItemsInCollectionByName(cName, 10)
+	} else if cSlug != "" {
+		items, err = s.GetAllItemsInCollectionBySlug(cSlug, 10)
+	} else {
+		items, err = s.GetAllItemsInCollectionByID(cID, 10)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rawItem := range items {
+		item := &webflowAPI.CollectionItem{}
+		if err := json.Unmarshal(rawItem, item); err != nil {
+			return nil, err
+		}
+
+		if iName != "" && item.Name != iName {
+			continue
+		}
+
+		if iID != "" && item.ID != iID {
+			continue
+		}
+
+		return rawItem, nil
+	}
+
+	return nil, nil
+}
+
+// CreateItem Add an item to a collection, filling in the metadata Webflow maintains.
+func (s *Store) CreateItem(collectionID string, fields interface{}, live bool) ([]byte, error) {
+	item, err := toObject(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionID]
+	if !ok {
+		return nil, errors.New("Collection not found")
+	}
+
+	if err := fake.Validate(&c.info, c.items, item, ""); err != nil {
+		return nil, err
+	}
+
+	now := s.now().UTC().Format(timeFormat)
+	item["_id"] = s.newID()
+	item["_cid"] = collectionID
+	item["created-on"] = now
+	item["updated-on"] = now
+	item["published-on"] = nil
+	if live {
+		item["published-on"] = now
+	}
+	object.SetDefault(item, "_archived", false)
+	object.SetDefault(item, "_draft", false)
+
+	c.items = append(c.items, item)
+
+	return json.Marshal(item)
+}
+
+// UpdateItem Replace all the fields of an item but its metadata.
+func (s *Store) UpdateItem(collectionID, itemID string, fields interface{}, live bool) ([]byte, error) {
+	return s.writeItem(collectionID, itemID, fields, live, false)
+}
+
+// PatchItem Update only the given fields of an item.
+func (s *Store) PatchItem(collectionID, itemID string, fields interface{}, live bool) ([]byte, error) {
+	return s.writeItem(collectionID, itemID, fields, live, true)
+}
+
+// DeleteItem Remove an item from a collection.
+func (s *Store) DeleteItem(collectionID, itemID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionID]
+	if !ok {
+		return errors.New("Collection not found")
+	}
+
+	i := c.find(itemID)
+	if i < 0 {
+		return errors.New("Item not found")
+	}
+
+	c.items = append(c.items[:i], c.items[i+1:]...)
+
+	return nil
+}
+
+// writeItem Update an item, merging the fields into the existing ones when patching.
+func (s *Store) writeItem(collectionID, itemID string, fields interface{}, live, patch bool) ([]byte, error) {
+	changes, err := toObject(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionID]
+	if !ok {
+		return nil, errors.New("Collection not found")
+	}
+
+	i := c.find(itemID)
+	if i < 0 {
+		return nil, errors.New("Item not found")
+	}
+
+	item := map[string]interface{}{}
+	for key, val := range c.items[i] {
+		if patch || object.IsMetadata(key) {
+			item[key] = val
+		}
+	}
+	for key, val := range changes {
+		if key != "_id" && key != "_cid" {
+			item[key] = val
+		}
+	}
+
+	if err := fake.Validate(&c.info, c.items, item, itemID); err != nil {
+		return nil, err
+	}
+
+	item["updated-on"] = s.now().UTC().Format(timeFormat)
+	if live {
+		item["published-on"] = item["updated-on"]
+	}
+	c.items[i] = item
+
+	return json.Marshal(item)
+}
+
+// PublishItems Set the published-on time of the items. Drafts & missing items are reported as errors.
+func (s *Store) PublishItems(collectionID string, itemIDs []string) (*webflowAPI.PublishedItems, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionID]
+	if !ok {
+		return nil, errors.New("Collection not found")
+	}
+
+	res := &webflowAPI.PublishedItems{PublishedItemIDs: []string{}, Errors: []string{}}
+	now := s.now().UTC().Format(timeFormat)
+	for _, id := range itemIDs {
+		i := c.find(id)
+		switch {
+		case i < 0:
+			res.Errors = append(res.Errors, fmt.Sprintf("item %s not found", id))
+		case c.items[i]["_draft"] == true:
+			res.Errors = append(res.Errors, fmt.Sprintf("item %s is a draft", id))
+		default:
+			c.items[i]["published-on"] = now
+			res.PublishedItemIDs = append(res.PublishedItemIDs, id)
+		}
+	}
+
+	return res, nil
+}
+
+// CreateCollection Create a collection with the name & slug fields along with the given ones.
+func (s *Store) CreateCollection(definition webflowAPI.CollectionDefinition) (*webflowAPI.Collection, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if definition.Name == "" {
+		return nil, errors.New("ValidationError: name is required")
+	}
+	slug := definition.Slug
+	if slug == "" {
+		slug = fake.Slugify(definition.Name)
+	}
+	for _, c := range s.collections {
+		if c.info.Slug == slug {
+			return nil, fmt.Errorf("ValidationError: slug '%s' is already in use", slug)
+		}
+	}
+
+	now := s.now().UTC()
+	info := webflowAPI.Collection{
+		ID:           s.newID(),
+		CreatedOn:    now,
+		LastUpdated:  now,
+		Name:         definition.Name,
+		Slug:         slug,
+		SingularName: definition.SingularName,
+	}
+	for _, def := range append(fake.BuiltinFields(), definition.Fields...) {
+		field, err := s.newField(&info, def)
+		if err != nil {
+			return nil, err
+		}
+		info.Fields = append(info.Fields, field)
+	}
+
+	s.order = append(s.order, info.ID)
+	s.collections[info.ID] = &collection{info: info}
+
+	return &info, nil
+}
+
+// DeleteCollection Remove a collection, along with its items.
+func (s *Store) DeleteCollection(collectionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.collections[collectionID]; !ok {
+		return errors.New("Collection not found")
+	}
+
+	delete(s.collections, collectionID)
+	for i, id := range s.order {
+		if id == collectionID {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// CreateField Add a field to a collection.
+func (s *Store) CreateField(collectionID string, definition webflowAPI.FieldDefinition) (*webflowAPI.CollectionField, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionID]
+	if !ok {
+		return nil, errors.New("Collection not found")
+	}
+
+	field, err := s.newField(&c.info, definition)
+	if err != nil {
+		return nil, err
+	}
+	c.info.Fields = append(c.info.Fields, field)
+	c.info.LastUpdated = s.now().UTC()
+
+	return &field, nil
+}
+
+// UpdateField Change a field's name, slug, help text, required flag or validations. Its type cannot be changed.
+func (s *Store) UpdateField(collectionID, fieldID string, definition webflowAPI.FieldDefinition) (*webflowAPI.CollectionField, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionID]
+	if !ok {
+		return nil, errors.New("Collection not found")
+	}
+	i := fake.FindField(&c.info, fieldID)
+	if i < 0 {
+		return nil, errors.New("Field not found")
+	}
+
+	field := c.info.Fields[i]
+	if definition.Type != "" && definition.Type != field.Type {
+		return nil, errors.New("ValidationError: a field's type cannot be changed")
+	}
+	if definition.Slug != "" && definition.Slug != field.Slug {
+		if field.Slug == "name" || field.Slug == "slug" {
+			return nil, fmt.Errorf("ValidationError: the slug of the %s field cannot be changed", field.Slug)
+		}
+		if fake.FindField(&c.info, definition.Slug) >= 0 {
+			return nil, fmt.Errorf("ValidationError: field slug '%s' is already in use", definition.Slug)
+		}
+		field.Slug = definition.Slug
+	}
+	if definition.Name != "" {
+		field.Name = definition.Name
+	}
+	field.Required = definition.Required
+	field.HelpText = definition.HelpText
+	field.Validations = definition.Validations
+
+	c.info.Fields[i] = field
+	c.info.LastUpdated = s.now().UTC()
+
+	return &field, nil
+}
+
+// DeleteField Remove a field from a collection, along with its values in every item. The name & slug fields cannot be
+// removed.
+func (s *Store) DeleteField(collectionID, fieldID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.collections[collectionID]
+	if !ok {
+		return errors.New("Collection not found")
+	}
+	i := fake.FindField(&c.info, fieldID)
+	if i < 0 {
+		return errors.New("Field not found")
+	}
+
+	slug := c.info.Fields[i].Slug
+	if slug == "name" || slug == "slug" {
+		return fmt.Errorf("ValidationError: the %s field cannot be removed", slug)
+	}
+
+	c.info.Fields = append(c.info.Fields[:i], c.info.Fields[i+1:]...)
+	for _, item := range c.items {
+		delete(item, slug)
+	}
+	c.info.LastUpdated = s.now().UTC()
+
+	return nil
+}
+
+// newField Create a field of the collection from its definition, checking it as the API would. Must be called with the
+// lock held.
+func (s *Store) newField(info *webflowAPI.Collection, definition webflowAPI.FieldDefinition) (webflowAPI.CollectionField, error) {
+	if definition.Name == "" || definition.Type == "" {
+		return webflowAPI.CollectionField{}, errors.New("ValidationError: a field's name & type are required")
+	}
+	slug := definition.Slug
+	if slug == "" {
+		slug = fake.Slugify(definition.Name)
+	}
+	if fake.FindField(info, slug) >= 0 {
+		return webflowAPI.CollectionField{}, fmt.Errorf("ValidationError: field slug '%s' is already in use", slug)
+	}
+
+	return webflowAPI.CollectionField{
+		ID:          s.newID(),
+		Name:        definition.Name,
+		Slug:        slug,
+		Type:        definition.Type,
+		Required:    definition.Required,
+		Editable:    true,
+		HelpText:    definition.HelpText,
+		Validations: definition.Validations,
+	}, nil
+}
+
+// newID Generate an ID shaped like the ones Webflow uses. Must be called with the lock held.
+func (s *Store) newID() string {
+	id := fake.NewID(s.nextID)
+	s.nextID++
+	return id
+}
+
+// allCollections The collections, in the order they were added, without fields. Must be called with the lock held.
+func (s *Store) allCollections() webflowAPI.Collections {
+	collections := webflowAPI.Collections{}
+	for _, id := range s.order {
+		info := s.collections[id].info
+		info.Fields = nil
+		collections = append(collections, info)
+	}
+
+	return collections
+}
+
+// page A page of items shaped like the API's response.
+func (c *collection) page(offset, limit int) *webflowAPI.CollectionItems {
+	if offset < 0 {
+		offset = 0
+	}
+
+	page := []map[string]interface{}{}
+	for i := offset; i < len(c.items) && i < offset+limit; i++ {
+		page = append(page, c.items[i])
+	}
+
+	data, _ := json.Marshal(page)
+
+	return &webflowAPI.CollectionItems{
+		Items:  data,
+		Count:  len(page),
+		Limit:  limit,
+		Offset: offset,
+		Total:  len(c.items),
+	}
+}
+
+// find Index of an item by ID; -1 when not found.
+func (c *collection) find(id string) int {
+	for i, item := range c.items {
+		if item["_id"] == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// toObject Convert fields, a struct or a map, into a JSON object.
+func toObject(fields interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode the item fields; error: %+v", err)
+	}
+
+	return object.Decode(data)
+}
@@ -0,0 +1,281 @@
+package memory
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+const (
+	siteID = "mysiteid"
+)
+
+// newTestStore Load the test fixture into a store with a frozen clock.
+func newTestStore(t *testing.T) *Store {
+	store, err := Load(siteID, "testdata/site.json")
+	if err != nil {
+		t.Fatalf("Load() is expected to seed the store from the fixture: %+v", err)
+	}
+	store.now = func() time.Time {
+		return time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+	}
+
+	return store
+}
+
+func TestLoad(t *testing.T) {
+	store := newTestStore(t)
+
+	collections, err := store.GetAllCollections()
+	if err != nil || len(*collections) != 2 {
+		t.Errorf("Load() is expected to only seed the requested site's collections! Got %+v; error %+v.", collections, err)
+	}
+
+	collection := &webflowAPI.Collection{}
+	if err := store.MethodGet("/collections/1", nil, collection); err != nil || len(collection.Fields) != 3 {
+		t.Errorf("MethodGet() is expected to return a single collection with its fields! Got %+v; error %+v.", collection, err)
+	}
+}
+
+func TestCollectionLookup(t *testing.T) {
+	store := newTestStore(t)
+
+	{
+		collection, err := store.GetCollectionByName("DOGS")
+		if err != nil || collection == nil || collection.ID != "1" {
+			t.Errorf("GetCollectionByName() is expected to search case insensitive! Got %+v; error %+v.", collection, err)
+		}
+	}
+	{
+		collection, err := store.GetCollectionBySlug("Cats1")
+		if err != nil || collection == nil || collection.ID != "2" {
+			t.Errorf("GetCollectionBySlug() is expected to search case insensitive! Got %+v; error %+v.", collection, err)
+		}
+	}
+	{
+		collection, err := store.GetCollectionByName("birds")
+		if err != nil || collection != nil {
+			t.Errorf("GetCollectionByName() is expected to return nil when not found! Got %+v; error %+v.", collection, err)
+		}
+
+		items, err := store.GetAllItemsInCollectionBySlug("birds", 10)
+		if err != nil || items != nil {
+			t.Errorf("GetAllItemsInCollectionBySlug() is expected to return nil when not found! Got %+v; error %+v.", items, err)
+		}
+	}
+}
+
+func TestMaxPages(t *testing.T) {
+	store := newTestStore(t)
+	store.PageSize = 1
+
+	{
+		items, _ := store.GetAllItemsInCollectionByID("1", 0)
+		if len(items) != 1 {
+			t.Errorf("GetAllItemsInCollectionByID() is expected to return one page when maxPages is 0! Got %d items.", len(items))
+		}
+	}
+	{
+		items, _ := store.GetAllItemsInCollectionByName("dogs", 1)
+		if len(items) != 2 {
+			t.Errorf("GetAllItemsInCollectionByName() is expected to return maxPages+1 pages! Got %d items.", len(items))
+		}
+	}
+	{
+		page := &webflowAPI.CollectionItems{}
+		store.MethodGet("/collections/1/items", map[string]string{"offset": "2", "limit": "100"}, page)
+		if page.Count != 1 || page.Offset != 2 || page.Total != 3 {
+			t.Errorf("MethodGet() is expected to paginate items like the API! Got %+v.", page)
+		}
+	}
+}
+
+func TestGetItem(t *testing.T) {
+	store := newTestStore(t)
+
+	item, err := store.GetItem("", "dogs1", "", "green", "")
+	if err != nil || item == nil {
+		t.Fatalf("GetItem() is expected to find the item by name! Error %+v.", err)
+	}
+
+	decoded := &webflowAPI.CollectionItem{}
+	json.Unmarshal(item, decoded)
+	if decoded.ID != "d2" {
+		t.Errorf("GetItem() returned the wrong item! Got %s.", item)
+	}
+
+	if item, _ := store.GetItem("", "", "1", "GREEN", ""); item != nil {
+		t.Errorf("GetItem() is expected to match item names exactly, like the real client! Got %s.", item)
+	}
+}
+
+func TestWrites(t *testing.T) {
+	store := newTestStore(t)
+
+	{
+		_, err := store.CreateItem("1", map[string]string{"name": "no color", "slug": "no-color"}, false)
+		if err == nil {
+			t.Error("CreateItem() is expected to reject items missing a required field.")
+		}
+
+		_, err = store.CreateItem("1", map[string]string{"name": "blue", "slug": "blue", "color": "blue"}, false)
+		if err == nil {
+			t.Error("CreateItem() is expected to reject items with a duplicate slug.")
+		}
+	}
+
+	created := &webflowAPI.CollectionItem{}
+	{
+		item, err := store.CreateItem("1", map[string]string{"name": "brown", "slug": "brown", "color": "brown"}, true)
+		if err != nil {
+			t.Fatalf("CreateItem() is expected to create a valid item: %+v", err)
+		}
+		json.Unmarshal(item, created)
+
+		if created.ID == "" || created.Cid != "1" {
+			t.Errorf("CreateItem() is expected to fill in the item's metadata! Got %s.", item)
+		}
+	}
+	{
+		item, err := store.PatchItem("1", created.ID, map[string]string{"name": "tan"}, false)
+		fields := map[string]interface{}{}
+		json.Unmarshal(item, &fields)
+		if err != nil || fields["name"] != "tan" || fields["color"] != "brown" {
+			t.Errorf("PatchItem() is expected to merge the fields! Got %s; error %+v.", item, err)
+		}
+
+		_, err = store.UpdateItem("1", created.ID, map[string]string{"name": "tan", "slug": "tan"}, false)
+		if err == nil {
+			t.Error("UpdateItem() is expected to replace every field, failing when a required field is left out.")
+		}
+	}
+	{
+		if err := store.DeleteItem("1", created.ID); err != nil {
+			t.Errorf("DeleteItem() is expected to remove the item: %+v", err)
+		}
+
+		items, _ := store.GetAllItemsInCollectionByID("1", 10)
+		if len(items) != 3 {
+			t.Errorf("DeleteItem() is expected to leave the original items! Got %d.", len(items))
+		}
+
+		if err := store.DeleteItem("1", created.ID); err == nil {
+			t.Error("DeleteItem() is expected to error when the item does not exist.")
+		}
+	}
+}
+
+func TestChanges(t *testing.T) {
+	store := newTestStore(t)
+	store.now = func() time.Time { return time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC) }
+
+	item, _ := store.CreateItem("1", map[string]string{"name": "brown", "slug": "brown", "color": "brown"}, false)
+	created := &webflowAPI.CollectionItem{}
+	json.Unmarshal(item, created)
+
+	items, err := webflowAPI.ItemsChangedSince(store, "1", time.Date(2020, 1, 9, 0, 0, 0, 0, time.UTC), false)
+	if err != nil || len(items) != 1 || !strings.Contains(string(items[0]), created.ID) {
+		t.Errorf("ItemsChangedSince() is expected to return only the new item! Got %d items; error %+v.", len(items), err)
+	}
+
+	store.DeleteItem("1", "d2")
+	deleted, err := webflowAPI.DeletedItemIDs(store, "1", []string{"d1", "d2", created.ID})
+	if err != nil || len(deleted) != 1 || deleted[0] != "d2" {
+		t.Errorf("DeletedItemIDs() is expected to return the deleted item! Got %+v; error %+v.", deleted, err)
+	}
+}
+
+func TestUpsertItem(t *testing.T) {
+	store := newTestStore(t)
+
+	action, _, err := webflowAPI.UpsertItem(store, "1", "", map[string]string{"slug": "blue", "color": "navy"}, false)
+	if err != nil || action != webflowAPI.UpsertUpdated {
+		t.Fatalf("UpsertItem() is expected to update the item with the slug! Got %s; error %+v.", action, err)
+	}
+	item, _ := store.GetItem("", "", "1", "", "d1")
+	if !strings.Contains(string(item), `"color":"navy"`) || !strings.Contains(string(item), `"name":"blue"`) {
+		t.Errorf("UpsertItem() is expected to patch only the given fields! Got %s.", item)
+	}
+
+	action, _, err = webflowAPI.UpsertItem(store, "1", "color", map[string]string{"name": "brown", "slug": "brown", "color": "brown"}, false)
+	if err != nil || action != webflowAPI.UpsertCreated {
+		t.Errorf("UpsertItem() is expected to create the missing item! Got %s; error %+v.", action, err)
+	}
+}
+
+func TestItemStates(t *testing.T) {
+	store := newTestStore(t)
+
+	webflowAPI.SetDraft(store, "1", "d1", true)
+	webflowAPI.ArchiveItem(store, "1", "d2")
+	if items, _ := webflowAPI.ListItems(store, "1", 0, webflowAPI.Live); len(items) != 1 || !strings.Contains(string(items[0]), "d3") {
+		t.Errorf("ListItems() is expected to leave out drafts & archived items! Got %s.", items)
+	}
+	if items, _ := webflowAPI.ListItems(store, "1", 0, webflowAPI.ListOptions{Archived: webflowAPI.OnlyItems}); len(items) != 1 || !strings.Contains(string(items[0]), "d2") {
+		t.Errorf("ListItems() is expected to list only archived items! Got %s.", items)
+	}
+
+	res, err := store.PublishItems("1", []string{"d1", "d3", "missing"})
+	if err != nil || len(res.PublishedItemIDs) != 1 || res.PublishedItemIDs[0] != "d3" || len(res.Errors) != 2 {
+		t.Errorf("PublishItems() is expected to publish d3 only! Got %+v; error %+v.", res, err)
+	}
+	if item, _ := store.GetItem("", "", "1", "", "d3"); !strings.Contains(string(item), `"published-on":"2019-04-01T12:00:00.000Z"`) {
+		t.Errorf("PublishItems() is expected to set the published-on time! Got %s.", item)
+	}
+
+	webflowAPI.UnarchiveItem(store, "1", "d2")
+	webflowAPI.SetDraft(store, "1", "d1", false)
+	if items, _ := webflowAPI.ListItems(store, "1", 0, webflowAPI.Live); len(items) != 3 {
+		t.Errorf("UnarchiveItem() & SetDraft() are expected to clear the flags! Got %s.", items)
+	}
+}
+
+func TestCollectionManagement(t *testing.T) {
+	store := newTestStore(t)
+
+	collection, err := store.CreateCollection(webflowAPI.CollectionDefinition{
+		Name:   "Blog Posts",
+		Fields: []webflowAPI.FieldDefinition{{Name: "Post Body", Type: webflowAPI.FieldTypeRichText}},
+	})
+	if err != nil || collection.Slug != "blog-posts" || len(collection.Fields) != 3 || collection.Fields[2].Slug != "post-body" {
+		t.Fatalf("CreateCollection() is expected to add the name, slug & given fields! Got %+v; error %+v.", collection, err)
+	}
+	if _, err := store.CreateCollection(webflowAPI.CollectionDefinition{Name: "Blog posts"}); err == nil {
+		t.Error("CreateCollection() is expected to reject a slug in use!")
+	}
+
+	field, err := store.CreateField(collection.ID, webflowAPI.FieldDefinition{Name: "Views", Type: webflowAPI.FieldTypeNumber})
+	if err != nil || field.Slug != "views" {
+		t.Fatalf("CreateField() is expected to add the field! Got %+v; error %+v.", field, err)
+	}
+	store.CreateItem(collection.ID, map[string]interface{}{"name": "Hi", "slug": "hi", "views": 3}, false)
+
+	if _, err := store.UpdateField(collection.ID, field.ID, webflowAPI.FieldDefinition{Type: webflowAPI.FieldTypePlainText}); err == nil {
+		t.Error("UpdateField() is expected to reject a change of type!")
+	}
+	updated, err := store.UpdateField(collection.ID, field.ID, webflowAPI.FieldDefinition{Name: "Page Views", Required: true})
+	if err != nil || updated.Name != "Page Views" || !updated.Required || updated.Slug != "views" {
+		t.Errorf("UpdateField() is expected to update the field! Got %+v; error %+v.", updated, err)
+	}
+
+	if err := store.DeleteField(collection.ID, collection.Fields[0].ID); err == nil {
+		t.Error("DeleteField() is expected to keep the name field!")
+	}
+	if err := store.DeleteField(collection.ID, field.ID); err != nil {
+		t.Errorf("DeleteField() is expected to remove the field: %+v", err)
+	}
+	items, _ := store.GetAllItemsInCollectionByID(collection.ID, 0)
+	if len(items) != 1 || strings.Contains(string(items[0]), "views") {
+		t.Errorf("DeleteField() is expected to remove the field's values! Got %s.", items)
+	}
+
+	if err := store.DeleteCollection(collection.ID); err != nil {
+		t.Errorf("DeleteCollection() is expected to remove the collection: %+v", err)
+	}
+	if found, _ := store.GetCollectionBySlug("blog-posts"); found != nil {
+		t.Errorf("DeleteCollection() is expected to remove the collection! Got %+v.", found)
+	}
+}
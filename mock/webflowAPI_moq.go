@@ -9,14 +9,27 @@ import (
 )
 
 var (
+	lockInterfaceMockCreateCollection              sync.RWMutex
+	lockInterfaceMockCreateField                   sync.RWMutex
+	lockInterfaceMockCreateItem                    sync.RWMutex
+	lockInterfaceMockDeleteCollection              sync.RWMutex
+	lockInterfaceMockDeleteField                   sync.RWMutex
+	lockInterfaceMockDeleteItem                    sync.RWMutex
 	lockInterfaceMockGetAllCollections             sync.RWMutex
 	lockInterfaceMockGetAllItemsInCollectionByID   sync.RWMutex
 	lockInterfaceMockGetAllItemsInCollectionByName sync.RWMutex
 	lockInterfaceMockGetAllItemsInCollectionBySlug sync.RWMutex
+	lockInterfaceMockGetAllSites                   sync.RWMutex
+	lockInterfaceMockGetCollectionByID             sync.RWMutex
 	lockInterfaceMockGetCollectionByName           sync.RWMutex
 	lockInterfaceMockGetCollectionBySlug           sync.RWMutex
 	lockInterfaceMockGetItem                       sync.RWMutex
 	lockInterfaceMockMethodGet                     sync.RWMutex
+	lockInterfaceMockPatchItem                     sync.RWMutex
+	lockInterfaceMockPublishItems                  sync.RWMutex
+	lockInterfaceMockPublishSite                   sync.RWMutex
+	lockInterfaceMockUpdateField                   sync.RWMutex
+	lockInterfaceMockUpdateItem                    sync.RWMutex
 )
 
 // Ensure, that InterfaceMock does implement Interface.
@@ -29,6 +42,24 @@ var _ webflowAPI.Interface = &InterfaceMock{}
 //
 //         // make and configure a mocked Interface
 //         mockedInterface := &InterfaceMock{
+//             CreateCollectionFunc: func(definition webflowAPI.CollectionDefinition) (*webflowAPI.Collection, error) {
+// 	               panic("mock out the CreateCollection method")
+//             },
+//             CreateFieldFunc: func(collectionID string, definition webflowAPI.FieldDefinition) (*webflowAPI.CollectionField, error) {
+// 	               panic("mock out the CreateField method")
+//             },
+//             CreateItemFunc: func(collectionID string, fields interface{}, live bool) ([]byte, error) {
+// 	               panic("mock out the CreateItem method")
+//             },
+//             DeleteCollectionFunc: func(collectionID string) error {
+// 	               panic("mock out the DeleteCollection method")
+//             },
+//             DeleteFieldFunc: func(collectionID string, fieldID string) error {
+// 	               panic("mock out the DeleteField method")
+//             },
+//             DeleteItemFunc: func(collectionID string, itemID string) error {
+// 	               panic("mock out the DeleteItem method")
+//             },
 //             GetAllCollectionsFunc: func() (*webflowAPI.Collections, error) {
 // 	               panic("mock out the GetAllCollections method")
 //             },
@@ -41,6 +72,12 @@ var _ webflowAPI.Interface = &InterfaceMock{}
 //             GetAllItemsInCollectionBySlugFunc: func(slug string, maxPages int) ([][]byte, error) {
 // 	               panic("mock out the GetAllItemsInCollectionBySlug method")
 //             },
+//             GetAllSitesFunc: func() (*webflowAPI.Sites, error) {
+// 	               panic("mock out the GetAllSites method")
+//             },
+//             GetCollectionByIDFunc: func(ID string) (*webflowAPI.Collection, error) {
+// 	               panic("mock out the GetCollectionByID method")
+//             },
 //             GetCollectionByNameFunc: func(name string) (*webflowAPI.Collection, error) {
 // 	               panic("mock out the GetCollectionByName method")
 //             },
@@ -53,6 +90,21 @@ var _ webflowAPI.Interface = &InterfaceMock{}
 //             MethodGetFunc: func(uri string, queryParams map[string]string, decodedResponse interface{}) error {
 // 	               panic("mock out the MethodGet method")
 //             },
+//             PatchItemFunc: func(collectionID string, itemID string, fields interface{}, live bool) ([]byte, error) {
+// 	               panic("mock out the PatchItem method")
+//             },
+//             PublishItemsFunc: func(collectionID string, itemIDs []string) (*webflowAPI.PublishedItems, error) {
+// 	               panic("mock out the PublishItems method")
+//             },
+//             PublishSiteFunc: func(domains []string) error {
+// 	               panic("mock out the PublishSite method")
+//             },
+//             UpdateFieldFunc: func(collectionID string, fieldID string, definition webflowAPI.FieldDefinition) (*webflowAPI.CollectionField, error) {
+// 	               panic("mock out the UpdateField method")
+//             },
+//             UpdateItemFunc: func(collectionID string, itemID string, fields interface{}, live bool) ([]byte, error) {
+// 	               panic("mock out the UpdateItem method")
+//             },
 //         }
 //
 //         // use mockedInterface in code that requires Interface
@@ -60,6 +112,24 @@ var _ webflowAPI.Interface = &InterfaceMock{}
 //
 //     }
 type InterfaceMock struct {
+	// CreateCollectionFunc mocks the CreateCollection method.
+	CreateCollectionFunc func(definition webflowAPI.CollectionDefinition) (*webflowAPI.Collection, error)
+
+	// CreateFieldFunc mocks the CreateField method.
+	CreateFieldFunc func(collectionID string, definition webflowAPI.FieldDefinition) (*webflowAPI.CollectionField, error)
+
+	// CreateItemFunc mocks the CreateItem method.
+	CreateItemFunc func(collectionID string, fields interface{}, live bool) ([]byte, error)
+
+	// DeleteCollectionFunc mocks the DeleteCollection method.
+	DeleteCollectionFunc func(collectionID string) error
+
+	// DeleteFieldFunc mocks the DeleteField method.
+	DeleteFieldFunc func(collectionID string, fieldID string) error
+
+	// DeleteItemFunc mocks the DeleteItem method.
+	DeleteItemFunc func(collectionID string, itemID string) error
+
 	// GetAllCollectionsFunc mocks the GetAllCollections method.
 	GetAllCollectionsFunc func() (*webflowAPI.Collections, error)
 
@@ -72,6 +142,12 @@ type InterfaceMock struct {
 	// GetAllItemsInCollectionBySlugFunc mocks the GetAllItemsInCollectionBySlug method.
 	GetAllItemsInCollectionBySlugFunc func(slug string, maxPages int) ([][]byte, error)
 
+	// GetAllSitesFunc mocks the GetAllSites method.
+	GetAllSitesFunc func() (*webflowAPI.Sites, error)
+
+	// GetCollectionByIDFunc mocks the GetCollectionByID method.
+	GetCollectionByIDFunc func(ID string) (*webflowAPI.Collection, error)
+
 	// GetCollectionByNameFunc mocks the GetCollectionByName method.
 	GetCollectionByNameFunc func(name string) (*webflowAPI.Collection, error)
 
@@ -84,8 +160,63 @@ type InterfaceMock struct {
 	// MethodGetFunc mocks the MethodGet method.
 	MethodGetFunc func(uri string, queryParams map[string]string, decodedResponse interface{}) error
 
+	// PatchItemFunc mocks the PatchItem method.
+	PatchItemFunc func(collectionID string, itemID string, fields interface{}, live bool) ([]byte, error)
+
+	// PublishItemsFunc mocks the PublishItems method.
+	PublishItemsFunc func(collectionID string, itemIDs []string) (*webflowAPI.PublishedItems, error)
+
+	// PublishSiteFunc mocks the PublishSite method.
+	PublishSiteFunc func(domains []string) error
+
+	// UpdateFieldFunc mocks the UpdateField method.
+	UpdateFieldFunc func(collectionID string, fieldID string, definition webflowAPI.FieldDefinition) (*webflowAPI.CollectionField, error)
+
+	// UpdateItemFunc mocks the UpdateItem method.
+	UpdateItemFunc func(collectionID string, itemID string, fields interface{}, live bool) ([]byte, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
+		// CreateCollection holds details about calls to the CreateCollection method.
+		CreateCollection []struct {
+			// Definition is the definition argument value.
+			Definition webflowAPI.CollectionDefinition
+		}
+		// CreateField holds details about calls to the CreateField method.
+		CreateField []struct {
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// Definition is the definition argument value.
+			Definition webflowAPI.FieldDefinition
+		}
+		// CreateItem holds details about calls to the CreateItem method.
+		CreateItem []struct {
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// Fields is the fields argument value.
+			Fields interface{}
+			// Live is the live argument value.
+			Live bool
+		}
+		// DeleteCollection holds details about calls to the DeleteCollection method.
+		DeleteCollection []struct {
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+		}
+		// DeleteField holds details about calls to the DeleteField method.
+		DeleteField []struct {
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// FieldID is the fieldID argument value.
+			FieldID string
+		}
+		// DeleteItem holds details about calls to the DeleteItem method.
+		DeleteItem []struct {
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// ItemID is the itemID argument value.
+			ItemID string
+		}
 		// GetAllCollections holds details about calls to the GetAllCollections method.
 		GetAllCollections []struct {
 		}
@@ -110,6 +241,14 @@ type InterfaceMock struct {
 			// MaxPages is the maxPages argument value.
 			MaxPages int
 		}
+		// GetAllSites holds details about calls to the GetAllSites method.
+		GetAllSites []struct {
+		}
+		// GetCollectionByID holds details about calls to the GetCollectionByID method.
+		GetCollectionByID []struct {
+			// ID is the ID argument value.
+			ID string
+		}
 		// GetCollectionByName holds details about calls to the GetCollectionByName method.
 		GetCollectionByName []struct {
 			// Name is the name argument value.
@@ -142,7 +281,256 @@ type InterfaceMock struct {
 			// DecodedResponse is the decodedResponse argument value.
 			DecodedResponse interface{}
 		}
+		// PatchItem holds details about calls to the PatchItem method.
+		PatchItem []struct {
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// ItemID is the itemID argument value.
+			ItemID string
+			// Fields is the fields argument value.
+			Fields interface{}
+			// Live is the live argument value.
+			Live bool
+		}
+		// PublishItems holds details about calls to the PublishItems method.
+		PublishItems []struct {
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// ItemIDs is the itemIDs argument value.
+			ItemIDs []string
+		}
+		// PublishSite holds details about calls to the PublishSite method.
+		PublishSite []struct {
+			// Domains is the domains argument value.
+			Domains []string
+		}
+		// UpdateField holds details about calls to the UpdateField method.
+		UpdateField []struct {
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// FieldID is the fieldID argument value.
+			FieldID string
+			// Definition is the definition argument value.
+			Definition webflowAPI.FieldDefinition
+		}
+		// UpdateItem holds details about calls to the UpdateItem method.
+		UpdateItem []struct {
+			// CollectionID is the collectionID argument value.
+			CollectionID string
+			// ItemID is the itemID argument value.
+			ItemID string
+			// Fields is the fields argument value.
+			Fields interface{}
+			// Live is the live argument value.
+			Live bool
+		}
+	}
+}
+
+// CreateCollection calls CreateCollectionFunc.
+func (mock *InterfaceMock) CreateCollection(definition webflowAPI.CollectionDefinition) (*webflowAPI.Collection, error) {
+	if mock.CreateCollectionFunc == nil {
+		panic("InterfaceMock.CreateCollectionFunc: method is nil but Interface.CreateCollection was just called")
+	}
+	callInfo := struct {
+		Definition webflowAPI.CollectionDefinition
+	}{
+		Definition: definition,
 	}
+	lockInterfaceMockCreateCollection.Lock()
+	mock.calls.CreateCollection = append(mock.calls.CreateCollection, callInfo)
+	lockInterfaceMockCreateCollection.Unlock()
+	return mock.CreateCollectionFunc(definition)
+}
+
+// CreateCollectionCalls gets all the calls that were made to CreateCollection.
+// Check the length with:
+//     len(mockedInterface.CreateCollectionCalls())
+func (mock *InterfaceMock) CreateCollectionCalls() []struct {
+	Definition webflowAPI.CollectionDefinition
+} {
+	var calls []struct {
+		Definition webflowAPI.CollectionDefinition
+	}
+	lockInterfaceMockCreateCollection.RLock()
+	calls = mock.calls.CreateCollection
+	lockInterfaceMockCreateCollection.RUnlock()
+	return calls
+}
+
+// CreateField calls CreateFieldFunc.
+func (mock *InterfaceMock) CreateField(collectionID string, definition webflowAPI.FieldDefinition) (*webflowAPI.CollectionField, error) {
+	if mock.CreateFieldFunc == nil {
+		panic("InterfaceMock.CreateFieldFunc: method is nil but Interface.CreateField was just called")
+	}
+	callInfo := struct {
+		CollectionID string
+		Definition   webflowAPI.FieldDefinition
+	}{
+		CollectionID: collectionID,
+		Definition:   definition,
+	}
+	lockInterfaceMockCreateField.Lock()
+	mock.calls.CreateField = append(mock.calls.CreateField, callInfo)
+	lockInterfaceMockCreateField.Unlock()
+	return mock.CreateFieldFunc(collectionID, definition)
+}
+
+// CreateFieldCalls gets all the calls that were made to CreateField.
+// Check the length with:
+//     len(mockedInterface.CreateFieldCalls())
+func (mock *InterfaceMock) CreateFieldCalls() []struct {
+	CollectionID string
+	Definition   webflowAPI.FieldDefinition
+} {
+	var calls []struct {
+		CollectionID string
+		Definition   webflowAPI.FieldDefinition
+	}
+	lockInterfaceMockCreateField.RLock()
+	calls = mock.calls.CreateField
+	lockInterfaceMockCreateField.RUnlock()
+	return calls
+}
+
+// CreateItem calls CreateItemFunc.
+func (mock *InterfaceMock) CreateItem(collectionID string, fields interface{}, live bool) ([]byte, error) {
+	if mock.CreateItemFunc == nil {
+		panic("InterfaceMock.CreateItemFunc: method is nil but Interface.CreateItem was just called")
+	}
+	callInfo := struct {
+		CollectionID string
+		Fields       interface{}
+		Live         bool
+	}{
+		CollectionID: collectionID,
+		Fields:       fields,
+		Live:         live,
+	}
+	lockInterfaceMockCreateItem.Lock()
+	mock.calls.CreateItem = append(mock.calls.CreateItem, callInfo)
+	lockInterfaceMockCreateItem.Unlock()
+	return mock.CreateItemFunc(collectionID, fields, live)
+}
+
+// CreateItemCalls gets all the calls that were made to CreateItem.
+// Check the length with:
+//     len(mockedInterface.CreateItemCalls())
+func (mock *InterfaceMock) CreateItemCalls() []struct {
+	CollectionID string
+	Fields       interface{}
+	Live         bool
+} {
+	var calls []struct {
+		CollectionID string
+		Fields       interface{}
+		Live         bool
+	}
+	lockInterfaceMockCreateItem.RLock()
+	calls = mock.calls.CreateItem
+	lockInterfaceMockCreateItem.RUnlock()
+	return calls
+}
+
+// DeleteCollection calls DeleteCollectionFunc.
+func (mock *InterfaceMock) DeleteCollection(collectionID string) error {
+	if mock.DeleteCollectionFunc == nil {
+		panic("InterfaceMock.DeleteCollectionFunc: method is nil but Interface.DeleteCollection was just called")
+	}
+	callInfo := struct {
+		CollectionID string
+	}{
+		CollectionID: collectionID,
+	}
+	lockInterfaceMockDeleteCollection.Lock()
+	mock.calls.DeleteCollection = append(mock.calls.DeleteCollection, callInfo)
+	lockInterfaceMockDeleteCollection.Unlock()
+	return mock.DeleteCollectionFunc(collectionID)
+}
+
+// DeleteCollectionCalls gets all the calls that were made to DeleteCollection.
+// Check the length with:
+//     len(mockedInterface.DeleteCollectionCalls())
+func (mock *InterfaceMock) DeleteCollectionCalls() []struct {
+	CollectionID string
+} {
+	var calls []struct {
+		CollectionID string
+	}
+	lockInterfaceMockDeleteCollection.RLock()
+	calls = mock.calls.DeleteCollection
+	lockInterfaceMockDeleteCollection.RUnlock()
+	return calls
+}
+
+// DeleteField calls DeleteFieldFunc.
+func (mock *InterfaceMock) DeleteField(collectionID string, fieldID string) error {
+	if mock.DeleteFieldFunc == nil {
+		panic("InterfaceMock.DeleteFieldFunc: method is nil but Interface.DeleteField was just called")
+	}
+	callInfo := struct {
+		CollectionID string
+		FieldID      string
+	}{
+		CollectionID: collectionID,
+		FieldID:      fieldID,
+	}
+	lockInterfaceMockDeleteField.Lock()
+	mock.calls.DeleteField = append(mock.calls.DeleteField, callInfo)
+	lockInterfaceMockDeleteField.Unlock()
+	return mock.DeleteFieldFunc(collectionID, fieldID)
+}
+
+// DeleteFieldCalls gets all the calls that were made to DeleteField.
+// Check the length with:
+//     len(mockedInterface.DeleteFieldCalls())
+func (mock *InterfaceMock) DeleteFieldCalls() []struct {
+	CollectionID string
+	FieldID      string
+} {
+	var calls []struct {
+		CollectionID string
+		FieldID      string
+	}
+	lockInterfaceMockDeleteField.RLock()
+	calls = mock.calls.DeleteField
+	lockInterfaceMockDeleteField.RUnlock()
+	return calls
+}
+
+// DeleteItem calls DeleteItemFunc.
+func (mock *InterfaceMock) DeleteItem(collectionID string, itemID string) error {
+	if mock.DeleteItemFunc == nil {
+		panic("InterfaceMock.DeleteItemFunc: method is nil but Interface.DeleteItem was just called")
+	}
+	callInfo := struct {
+		CollectionID string
+		ItemID       string
+	}{
+		CollectionID: collectionID,
+		ItemID:       itemID,
+	}
+	lockInterfaceMockDeleteItem.Lock()
+	mock.calls.DeleteItem = append(mock.calls.DeleteItem, callInfo)
+	lockInterfaceMockDeleteItem.Unlock()
+	return mock.DeleteItemFunc(collectionID, itemID)
+}
+
+// DeleteItemCalls gets all the calls that were made to DeleteItem.
+// Check the length with:
+//     len(mockedInterface.DeleteItemCalls())
+func (mock *InterfaceMock) DeleteItemCalls() []struct {
+	CollectionID string
+	ItemID       string
+} {
+	var calls []struct {
+		CollectionID string
+		ItemID       string
+	}
+	lockInterfaceMockDeleteItem.RLock()
+	calls = mock.calls.DeleteItem
+	lockInterfaceMockDeleteItem.RUnlock()
+	return calls
 }
 
 // GetAllCollections calls GetAllCollectionsFunc.
@@ -276,6 +664,63 @@ func (mock *InterfaceMock) GetAllItemsInCollectionBySlugCalls() []struct {
 	return calls
 }
 
+// GetAllSites calls GetAllSitesFunc.
+func (mock *InterfaceMock) GetAllSites() (*webflowAPI.Sites, error) {
+	if mock.GetAllSitesFunc == nil {
+		panic("InterfaceMock.GetAllSitesFunc: method is nil but Interface.GetAllSites was just called")
+	}
+	callInfo := struct {
+	}{}
+	lockInterfaceMockGetAllSites.Lock()
+	mock.calls.GetAllSites = append(mock.calls.GetAllSites, callInfo)
+	lockInterfaceMockGetAllSites.Unlock()
+	return mock.GetAllSitesFunc()
+}
+
+// GetAllSitesCalls gets all the calls that were made to GetAllSites.
+// Check the length with:
+//     len(mockedInterface.GetAllSitesCalls())
+func (mock *InterfaceMock) GetAllSitesCalls() []struct {
+} {
+	var calls []struct {
+	}
+	lockInterfaceMockGetAllSites.RLock()
+	calls = mock.calls.GetAllSites
+	lockInterfaceMockGetAllSites.RUnlock()
+	return calls
+}
+
+// GetCollectionByID calls GetCollectionByIDFunc.
+func (mock *InterfaceMock) GetCollectionByID(ID string) (*webflowAPI.Collection, error) {
+	if mock.GetCollectionByIDFunc == nil {
+		panic("InterfaceMock.GetCollectionByIDFunc: method is nil but Interface.GetCollectionByID was just called")
+	}
+	callInfo := struct {
+		ID string
+	}{
+		ID: ID,
+	}
+	lockInterfaceMockGetCollectionByID.Lock()
+	mock.calls.GetCollectionByID = append(mock.calls.GetCollectionByID, callInfo)
+	lockInterfaceMockGetCollectionByID.Unlock()
+	return mock.GetCollectionByIDFunc(ID)
+}
+
+// GetCollectionByIDCalls gets all the calls that were made to GetCollectionByID.
+// Check the length with:
+//     len(mockedInterface.GetCollectionByIDCalls())
+func (mock *InterfaceMock) GetCollectionByIDCalls() []struct {
+	ID string
+} {
+	var calls []struct {
+		ID string
+	}
+	lockInterfaceMockGetCollectionByID.RLock()
+	calls = mock.calls.GetCollectionByID
+	lockInterfaceMockGetCollectionByID.RUnlock()
+	return calls
+}
+
 // GetCollectionByName calls GetCollectionByNameFunc.
 func (mock *InterfaceMock) GetCollectionByName(name string) (*webflowAPI.Collection, error) {
 	if mock.GetCollectionByNameFunc == nil {
@@ -423,3 +868,194 @@ func (mock *InterfaceMock) MethodGetCalls() []struct {
 	lockInterfaceMockMethodGet.RUnlock()
 	return calls
 }
+
+// PatchItem calls PatchItemFunc.
+func (mock *InterfaceMock) PatchItem(collectionID string, itemID string, fields interface{}, live bool) ([]byte, error) {
+	if mock.PatchItemFunc == nil {
+		panic("InterfaceMock.PatchItemFunc: method is nil but Interface.PatchItem was just called")
+	}
+	callInfo := struct {
+		CollectionID string
+		ItemID       string
+		Fields       interface{}
+		Live         bool
+	}{
+		CollectionID: collectionID,
+		ItemID:       itemID,
+		Fields:       fields,
+		Live:         live,
+	}
+	lockInterfaceMockPatchItem.Lock()
+	mock.calls.PatchItem = append(mock.calls.PatchItem, callInfo)
+	lockInterfaceMockPatchItem.Unlock()
+	return mock.PatchItemFunc(collectionID, itemID, fields, live)
+}
+
+// PatchItemCalls gets all the calls that were made to PatchItem.
+// Check the length with:
+//     len(mockedInterface.PatchItemCalls())
+func (mock *InterfaceMock) PatchItemCalls() []struct {
+	CollectionID string
+	ItemID       string
+	Fields       interface{}
+	Live         bool
+} {
+	var calls []struct {
+		CollectionID string
+		ItemID       string
+		Fields       interface{}
+		Live         bool
+	}
+	lockInterfaceMockPatchItem.RLock()
+	calls = mock.calls.PatchItem
+	lockInterfaceMockPatchItem.RUnlock()
+	return calls
+}
+
+// PublishItems calls PublishItemsFunc.
+func (mock *InterfaceMock) PublishItems(collectionID string, itemIDs []string) (*webflowAPI.PublishedItems, error) {
+	if mock.PublishItemsFunc == nil {
+		panic("InterfaceMock.PublishItemsFunc: method is nil but Interface.PublishItems was just called")
+	}
+	callInfo := struct {
+		CollectionID string
+		ItemIDs      []string
+	}{
+		CollectionID: collectionID,
+		ItemIDs:      itemIDs,
+	}
+	lockInterfaceMockPublishItems.Lock()
+	mock.calls.PublishItems = append(mock.calls.PublishItems, callInfo)
+	lockInterfaceMockPublishItems.Unlock()
+	return mock.PublishItemsFunc(collectionID, itemIDs)
+}
+
+// PublishItemsCalls gets all the calls that were made to PublishItems.
+// Check the length with:
+//     len(mockedInterface.PublishItemsCalls())
+func (mock *InterfaceMock) PublishItemsCalls() []struct {
+	CollectionID string
+	ItemIDs      []string
+} {
+	var calls []struct {
+		CollectionID string
+		ItemIDs      []string
+	}
+	lockInterfaceMockPublishItems.RLock()
+	calls = mock.calls.PublishItems
+	lockInterfaceMockPublishItems.RUnlock()
+	return calls
+}
+
+// PublishSite calls PublishSiteFunc.
+func (mock *InterfaceMock) PublishSite(domains []string) error {
+	if mock.PublishSiteFunc == nil {
+		panic("InterfaceMock.PublishSiteFunc: method is nil but Interface.PublishSite was just called")
+	}
+	callInfo := struct {
+		Domains []string
+	}{
+		Domains: domains,
+	}
+	lockInterfaceMockPublishSite.Lock()
+	mock.calls.PublishSite = append(mock.calls.PublishSite, callInfo)
+	lockInterfaceMockPublishSite.Unlock()
+	return mock.PublishSiteFunc(domains)
+}
+
+// PublishSiteCalls gets all the calls that were made to PublishSite.
+// Check the length with:
+//     len(mockedInterface.PublishSiteCalls())
+func (mock *InterfaceMock) PublishSiteCalls() []struct {
+	Domains []string
+} {
+	var calls []struct {
+		Domains []string
+	}
+	lockInterfaceMockPublishSite.RLock()
+	calls = mock.calls.PublishSite
+	lockInterfaceMockPublishSite.RUnlock()
+	return calls
+}
+
+// UpdateField calls UpdateFieldFunc.
+func (mock *InterfaceMock) UpdateField(collectionID string, fieldID string, definition webflowAPI.FieldDefinition) (*webflowAPI.CollectionField, error) {
+	if mock.UpdateFieldFunc == nil {
+		panic("InterfaceMock.UpdateFieldFunc: method is nil but Interface.UpdateField was just called")
+	}
+	callInfo := struct {
+		CollectionID string
+		FieldID      string
+		Definition   webflowAPI.FieldDefinition
+	}{
+		CollectionID: collectionID,
+		FieldID:      fieldID,
+		Definition:   definition,
+	}
+	lockInterfaceMockUpdateField.Lock()
+	mock.calls.UpdateField = append(mock.calls.UpdateField, callInfo)
+	lockInterfaceMockUpdateField.Unlock()
+	return mock.UpdateFieldFunc(collectionID, fieldID, definition)
+}
+
+// UpdateFieldCalls gets all the calls that were made to UpdateField.
+// Check the length with:
+//     len(mockedInterface.UpdateFieldCalls())
+func (mock *InterfaceMock) UpdateFieldCalls() []struct {
+	CollectionID string
+	FieldID      string
+	Definition   webflowAPI.FieldDefinition
+} {
+	var calls []struct {
+		CollectionID string
+		FieldID      string
+		Definition   webflowAPI.FieldDefinition
+	}
+	lockInterfaceMockUpdateField.RLock()
+	calls = mock.calls.UpdateField
+	lockInterfaceMockUpdateField.RUnlock()
+	return calls
+}
+
+// UpdateItem calls UpdateItemFunc.
+func (mock *InterfaceMock) UpdateItem(collectionID string, itemID string, fields interface{}, live bool) ([]byte, error) {
+	if mock.UpdateItemFunc == nil {
+		panic("InterfaceMock.UpdateItemFunc: method is nil but Interface.UpdateItem was just called")
+	}
+	callInfo := struct {
+		CollectionID string
+		ItemID       string
+		Fields       interface{}
+		Live         bool
+	}{
+		CollectionID: collectionID,
+		ItemID:       itemID,
+		Fields:       fields,
+		Live:         live,
+	}
+	lockInterfaceMockUpdateItem.Lock()
+	mock.calls.UpdateItem = append(mock.calls.UpdateItem, callInfo)
+	lockInterfaceMockUpdateItem.Unlock()
+	return mock.UpdateItemFunc(collectionID, itemID, fields, live)
+}
+
+// UpdateItemCalls gets all the calls that were made to UpdateItem.
+// Check the length with:
+//     len(mockedInterface.UpdateItemCalls())
+func (mock *InterfaceMock) UpdateItemCalls() []struct {
+	CollectionID string
+	ItemID       string
+	Fields       interface{}
+	Live         bool
+} {
+	var calls []struct {
+		CollectionID string
+		ItemID       string
+		Fields       interface{}
+		Live         bool
+	}
+	lockInterfaceMockUpdateItem.RLock()
+	calls = mock.calls.UpdateItem
+	lockInterfaceMockUpdateItem.RUnlock()
+	return calls
+}
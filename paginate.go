@@ -0,0 +1,146 @@
+package webflowAPI
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/tidwall/gjson"
+)
+
+// DefaultPageLimit Number of items requested per page; the most Webflow returns.
+const DefaultPageLimit = 100
+
+// Cursor Position of a read of a collection's items, page by page. It may be saved after every page, e.g. with Save,
+// and the read resumed from it later, even by another process. Items created or deleted before the position while the
+// read is paused shift the later items, so some may be read twice or missed.
+type Cursor struct {
+	CollectionID string `json:"collectionId"`
+	// Offset Number of items read so far.
+	Offset int `json:"offset"`
+	// Total Number of items in the collection as of the last page read; -1 until a page is read.
+	Total int `json:"total"`
+}
+
+// NewCursor A cursor at the start of the collection's items.
+func NewCursor(collectionID string) Cursor {
+	return Cursor{CollectionID: collectionID, Total: -1}
+}
+
+// LoadCursor Read a cursor saved with Save.
+func LoadCursor(path string) (*Cursor, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := &Cursor{}
+	if err := json.Unmarshal(data, cursor); err != nil {
+		return nil, fmt.Errorf("unable to decode the cursor %s; error: %+v", path, err)
+	}
+
+	return cursor, nil
+}
+
+// Done Whether every item has been read.
+func (cursor Cursor) Done() bool {
+	return cursor.Total >= 0 && cursor.Offset >= cursor.Total
+}
+
+// Save Atomically write the cursor to the file: write to a temp file, sync it then rename it into place.
+func (cursor Cursor) Save(path string) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("unable to save the cursor; error: %+v", err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save the cursor; error: %+v", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save the cursor; error: %+v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save the cursor; error: %+v", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Paginator Reads a collection's items a page at a time from a cursor.
+type Paginator struct {
+	// Limit Number of items requested per page.
+	Limit int
+
+	api    Interface
+	cursor Cursor
+}
+
+// NewPaginator Create a paginator reading through the given API from the cursor: NewCursor to read from the start, or a
+// saved cursor to resume a read.
+func NewPaginator(api Interface, cursor Cursor) *Paginator {
+	return &Paginator{
+		Limit:  DefaultPageLimit,
+		api:    api,
+		cursor: cursor,
+	}
+}
+
+// Next Read the raw JSON of the next page of items, advancing the cursor. Returns no items once done. When the request
+// fails the cursor is left as it was, so the page may be read again.
+func (p *Paginator) Next() ([][]byte, error) {
+	if p.cursor.Done() {
+		return [][]byte{}, nil
+	}
+
+	queryParams := map[string]string{
+		"offset": strconv.Itoa(p.cursor.Offset),
+		"limit":  strconv.Itoa(p.Limit),
+	}
+
+	collectionItems := &CollectionItems{}
+	err := p.api.MethodGet(fmt.Sprintf(listCollectionItemsURL, p.cursor.CollectionID), queryParams, collectionItems)
+	if err != nil {
+		return nil, err
+	}
+
+	items := [][]byte{}
+	gjson.ParseBytes(collectionItems.Items).ForEach(func(key, value gjson.Result) bool {
+		items = append(items, []byte(value.Raw))
+		return true
+	})
+
+	p.cursor.Offset = collectionItems.Offset + collectionItems.Count
+	p.cursor.Total = collectionItems.Total
+	// An empty page means the collection shrank since the total was reported; there is nothing more to read.
+	if collectionItems.Count == 0 && p.cursor.Offset < p.cursor.Total {
+		p.cursor.Total = p.cursor.Offset
+	}
+
+	return items, nil
+}
+
+// Done Whether every item has been read.
+func (p *Paginator) Done() bool {
+	return p.cursor.Done()
+}
+
+// Cursor The position after the pages read so far.
+func (p *Paginator) Cursor() Cursor {
+	return p.cursor
+}
@@ -0,0 +1,188 @@
+package webflowAPI
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sethgrid/pester"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// ProfileEnv Environment variable naming the profile to use when none is given.
+	ProfileEnv = "WEBFLOW_PROFILE"
+	// Name of the profile used when neither a name, the environment nor the config file's default name one.
+	defaultProfileName = "default"
+)
+
+// Config Contents of the profiles config file, e.g. ~/.config/webflow/config.yaml:
+//
+//	default: staging
+//	profiles:
+//	  staging:
+//	    tokenEnv: WEBFLOW_STAGING_TOKEN
+//	    siteId: 5c0000000000000000000001
+//	  production:
+//	    tokenFile: ~/.secrets/webflow-production
+//	    siteId: 5c0000000000000000000002
+//	    rateLimit:
+//	      maxRetries: 3
+//	      backoff: linear
+type Config struct {
+	Default  string             `yaml:"default"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// Profile Settings for one site & environment. The token is taken from Token, the TokenEnv environment variable or
+// the TokenFile file, in that order.
+type Profile struct {
+	Token      string           `yaml:"token"`
+	TokenEnv   string           `yaml:"tokenEnv"`
+	TokenFile  string           `yaml:"tokenFile"`
+	SiteID     string           `yaml:"siteId"`
+	BaseURL    string           `yaml:"baseUrl"`
+	APIVersion string           `yaml:"apiVersion"`
+	RateLimit  RateLimitProfile `yaml:"rateLimit"`
+}
+
+// RateLimitProfile Retry settings used when Webflow rate limits requests or has server errors.
+type RateLimitProfile struct {
+	// MaxRetries Number of attempts for each request. Defaults to that of New.
+	MaxRetries int `yaml:"maxRetries"`
+	// Backoff One of constant, linear, exponential or exponential-jitter. Defaults to exponential.
+	Backoff string `yaml:"backoff"`
+}
+
+// DefaultConfigPath Location of the profiles config file in the user's config directory.
+func DefaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "webflow", "config.yaml")
+}
+
+// LoadConfig Read a profiles config file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("unable to decode the config file %s; error: %+v", path, err)
+	}
+
+	return config, nil
+}
+
+// LoadProfile Create a client configured by the named profile of the default config file. An empty name uses
+// $WEBFLOW_PROFILE, the config file's default or the profile named "default", in that order.
+func LoadProfile(name string) (*apiConfig, error) {
+	config, err := LoadConfig(DefaultConfigPath())
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := config.Profile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile.New(nil)
+}
+
+// Profile Find a profile by name. An empty name uses $WEBFLOW_PROFILE, the config's default or the profile named
+// "default", in that order.
+func (config *Config) Profile(name string) (*Profile, error) {
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+	}
+	if name == "" {
+		name = config.Default
+	}
+	if name == "" {
+		name = defaultProfileName
+	}
+
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile '%s' is not defined", name)
+	}
+
+	return &profile, nil
+}
+
+// New Create a client configured by the profile. See New for the use of hc.
+func (profile *Profile) New(hc *http.Client) (*apiConfig, error) {
+	token, err := profile.ResolveToken()
+	if err != nil {
+		return nil, err
+	}
+
+	api := New(token, profile.SiteID, hc)
+
+	if profile.BaseURL != "" {
+		api.BaseURL = strings.TrimRight(profile.BaseURL, "/")
+	}
+
+	if profile.APIVersion != "" {
+		api.Version = profile.APIVersion
+	}
+
+	if profile.RateLimit.MaxRetries > 0 {
+		api.Client.MaxRetries = profile.RateLimit.MaxRetries
+	}
+
+	switch profile.RateLimit.Backoff {
+	case "", "exponential":
+		api.Client.Backoff = pester.ExponentialBackoff
+	case "exponential-jitter":
+		api.Client.Backoff = pester.ExponentialJitterBackoff
+	case "linear":
+		api.Client.Backoff = pester.LinearBackoff
+	case "constant":
+		api.Client.Backoff = pester.DefaultBackoff
+	default:
+		return nil, fmt.Errorf("unknown rate limit backoff '%s'", profile.RateLimit.Backoff)
+	}
+
+	return api, nil
+}
+
+// ResolveToken The profile's token from Token, the TokenEnv environment variable or the TokenFile file.
+func (profile *Profile) ResolveToken() (string, error) {
+	if profile.Token != "" {
+		return profile.Token, nil
+	}
+
+	if profile.TokenEnv != "" {
+		if token := os.Getenv(profile.TokenEnv); token != "" {
+			return token, nil
+		}
+		return "", fmt.Errorf("the environment variable %s is empty", profile.TokenEnv)
+	}
+
+	if profile.TokenFile != "" {
+		path := profile.TokenFile
+		if strings.HasPrefix(path, "~/") {
+			home, _ := os.UserHomeDir()
+			path = filepath.Join(home, path[2:])
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to read the token file; error: %+v", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	return "", errors.New("profile has no token, tokenEnv or tokenFile")
+}
@@ -0,0 +1,153 @@
+package webflowAPI
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	exampleConfig = `
+default: staging
+profiles:
+  staging:
+    token: stagingtoken
+    siteId: stagingsite
+  production:
+    tokenEnv: WEBFLOW_TEST_PRODUCTION_TOKEN
+    siteId: productionsite
+    baseUrl: https://example.com/
+    apiVersion: 2.0.0
+    rateLimit:
+      maxRetries: 3
+      backoff: linear
+  fromfile:
+    tokenFile: %s
+  broken:
+    token: x
+    rateLimit:
+      backoff: sideways
+`
+)
+
+// writeTestConfig Write the example config, and a token file, to a temp dir.
+func writeTestConfig(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "webflow-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokenFile := filepath.Join(dir, "token")
+	ioutil.WriteFile(tokenFile, []byte("filetoken\n"), 0600)
+
+	path := filepath.Join(dir, "webflow", "config.yaml")
+	os.MkdirAll(filepath.Dir(path), 0700)
+	ioutil.WriteFile(path, []byte(fmt.Sprintf(exampleConfig, tokenFile)), 0600)
+
+	return path, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestConfigProfile(t *testing.T) {
+	path, cleanup := writeTestConfig(t)
+	defer cleanup()
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() is expected to read the config file: %+v", err)
+	}
+
+	{
+		os.Unsetenv(ProfileEnv)
+		profile, err := config.Profile("")
+		if err != nil || profile.SiteID != "stagingsite" {
+			t.Errorf("Profile() is expected to use the config's default profile! Got %+v; error %+v.", profile, err)
+		}
+	}
+	{
+		os.Setenv(ProfileEnv, "production")
+		defer os.Unsetenv(ProfileEnv)
+		profile, err := config.Profile("")
+		if err != nil || profile.SiteID != "productionsite" {
+			t.Errorf("Profile() is expected to prefer $%s over the default! Got %+v; error %+v.", ProfileEnv, profile, err)
+		}
+	}
+	{
+		if _, err := config.Profile("nope"); err == nil {
+			t.Error("Profile() is expected to error when the profile is not defined.")
+		}
+	}
+}
+
+func TestProfileNew(t *testing.T) {
+	path, cleanup := writeTestConfig(t)
+	defer cleanup()
+
+	config, _ := LoadConfig(path)
+
+	{
+		os.Setenv("WEBFLOW_TEST_PRODUCTION_TOKEN", "envtoken")
+		defer os.Unsetenv("WEBFLOW_TEST_PRODUCTION_TOKEN")
+
+		profile, _ := config.Profile("production")
+		api, err := profile.New(nil)
+		if err != nil {
+			t.Fatalf("New() is expected to configure the client from the profile: %+v", err)
+		}
+
+		if api.Token != "envtoken" || api.SiteID != "productionsite" || api.BaseURL != "https://example.com" {
+			t.Errorf("New() did not apply the profile's token, site & base URL! Got %+v.", api)
+		}
+
+		if api.Version != "2.0.0" || api.Client.MaxRetries != 3 {
+			t.Errorf("New() did not apply the profile's API version & rate limit settings! Got %+v.", api)
+		}
+	}
+	{
+		profile, _ := config.Profile("fromfile")
+		api, err := profile.New(nil)
+		if err != nil || api.Token != "filetoken" {
+			t.Errorf("New() is expected to read the token from the token file! Got %+v; error %+v.", api, err)
+		}
+	}
+	{
+		profile, _ := config.Profile("broken")
+		if _, err := profile.New(nil); err == nil {
+			t.Error("New() is expected to error for an unknown backoff.")
+		}
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	path, cleanup := writeTestConfig(t)
+	defer cleanup()
+
+	os.Setenv("XDG_CONFIG_HOME", filepath.Dir(filepath.Dir(path)))
+	defer os.Unsetenv("XDG_CONFIG_HOME")
+
+	api, err := LoadProfile("staging")
+	if err != nil || api.Token != "stagingtoken" {
+		t.Fatalf("LoadProfile() is expected to load the profile from the default config file! Got %+v; error %+v.", api, err)
+	}
+
+	// The API version of the profile is sent with every request.
+	version := ""
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		version = req.Header.Get("Accept-Version")
+		rw.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	api.BaseURL = server.URL
+	api.Version = "1.2.3"
+	api.GetAllCollections()
+
+	if version != "1.2.3" {
+		t.Errorf("Requests are expected to send the configured API version! Got '%s'.", version)
+	}
+}
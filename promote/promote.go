@@ -0,0 +1,717 @@
+// Package promote Copy content authored in one site, e.g. staging, to another, e.g. production. Collections are matched
+// by slug and items by slug, so promoted items update their counterparts or are created. References are remapped to
+// the destination's items and images & files are re-uploaded from their URLs. Plans may be reviewed before applying.
+package promote
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/diff"
+	"github.com/redeemed2011/webflowAPI/internal/object"
+)
+
+const (
+	// Kinds of Action.
+	Create = "create"
+	Update = "update"
+
+	// DefaultMaxPages Number of additional pages of items requested per collection.
+	DefaultMaxPages = 100
+)
+
+// Filter Whether to promote an item of the source collection with the given slug. Items are decoded JSON objects.
+type Filter func(collection string, item map[string]interface{}) bool
+
+// Action A change to one destination item.
+type Action struct {
+	Kind       string `json:"kind"`
+	Collection string `json:"collection"`
+	Key        string `json:"key"`
+	SourceID   string `json:"sourceId"`
+	// ItemID The destination item; set once created.
+	ItemID string `json:"itemId,omitempty"`
+	// Fields The fields written, with references remapped to destination items.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// Pending References to source items that are created by the plan, written once they exist.
+	Pending map[string]interface{} `json:"pending,omitempty"`
+	// Changes The before & after values of the fields an update changes.
+	Changes []diff.FieldChange `json:"changes,omitempty"`
+	// Assets Image & file fields re-uploaded from their source URLs.
+	Assets []string `json:"assets,omitempty"`
+	// Warnings e.g. references to source items that have no counterpart in the destination, which are dropped.
+	Warnings []string `json:"warnings,omitempty"`
+	// CollectionID The destination collection.
+	CollectionID string `json:"collectionId"`
+}
+
+// Plan The actions promoting the items, in the order they are applied: referenced collections first.
+type Plan struct {
+	Actions []Action `json:"actions"`
+	// Unchanged Number of items already the same in the destination.
+	Unchanged int `json:"unchanged"`
+	// IDs Destination item IDs by the source item IDs of their counterparts. Kept with the plan so it may be written as
+	// JSON, reviewed then applied.
+	IDs map[string]string `json:"ids"`
+}
+
+// Failure An action that could not be applied.
+type Failure struct {
+	Action Action `json:"action"`
+	Error  string `json:"error"`
+}
+
+// Report The outcome of applying a plan.
+type Report struct {
+	Applied []Action  `json:"applied"`
+	Failed  []Failure `json:"failed,omitempty"`
+}
+
+// Promoter Promotes items from a source site to a destination site.
+type Promoter struct {
+	// Live Publish created & updated items immediately.
+	Live bool
+	// MaxPages Number of additional pages of items to request. See webflowAPI.Interface.GetAllItemsInCollectionByID.
+	MaxPages int
+
+	src, dst webflowAPI.Interface
+}
+
+// New Create a promoter reading from the src site's client & writing through the dst site's client.
+func New(src, dst webflowAPI.Interface) *Promoter {
+	return &Promoter{
+		MaxPages: DefaultMaxPages,
+		src:      src,
+		dst:      dst,
+	}
+}
+
+// Promote Plan promoting the items of the collections, given by slug, name or ID, that pass the filter; every
+// collection & item when none are given. Unless dryRun is set, the plan is applied; see Apply.
+func (p *Promoter) Promote(collections []string, filter Filter, dryRun bool) (*Plan, *Report, error) {
+	plan, err := p.Plan(collections, filter)
+	if err != nil || dryRun {
+		return plan, nil, err
+	}
+
+	return plan, p.Apply(plan), nil
+}
+
+// Plan Compare the source items to their destination counterparts without writing anything.
+func (p *Promoter) Plan(collections []string, filter Filter) (*Plan, error) {
+	st, err := p.load(collections)
+	if err != nil {
+		return nil, err
+	}
+
+	// Source items that will exist in the destination once created.
+	promoted := map[string]bool{}
+	selected := map[string][]map[string]interface{}{}
+	for _, c := range st.chosen {
+		for _, item := range st.srcItems[c.src.ID] {
+			if filter == nil || filter(c.src.Slug, item) {
+				selected[c.src.ID] = append(selected[c.src.ID], item)
+				promoted[object.Str(item["_id"])] = true
+			}
+		}
+	}
+
+	plan := &Plan{Actions: []Action{}, IDs: map[string]string{}}
+	for srcID, ref := range st.srcRefs {
+		if dstID, ok := st.dstIDs[ref]; ok {
+			plan.IDs[srcID] = dstID
+		}
+	}
+	for _, c := range st.chosen {
+		for _, item := range selected[c.src.ID] {
+			action, changed := st.action(c, item, promoted)
+			if !changed {
+				plan.Unchanged++
+				continue
+			}
+			plan.Actions = append(plan.Actions, action)
+		}
+	}
+
+	return plan, nil
+}
+
+// Apply Make the plan's changes. Created items are recorded so pending references to them are written, with the item
+// when it exists by then, else patched in once every item has been written. Every action is attempted; those that
+// fail are reported.
+func (p *Promoter) Apply(plan *Plan) *Report {
+	report := &Report{Applied: []Action{}}
+
+	// Source item IDs mapped to the destination items promoted from them.
+	ids := map[string]string{}
+	for srcID, dstID := range plan.IDs {
+		ids[srcID] = dstID
+	}
+
+	deferred := []int{}
+	for _, action := range plan.Actions {
+		fields := map[string]interface{}{}
+		for key, val := range action.Fields {
+			fields[key] = val
+		}
+
+		later := false
+		for key, ref := range action.Pending {
+			if resolved(ref, ids) {
+				fields[key] = remap(ref, ids)
+			} else {
+				later = true
+			}
+		}
+
+		var err error
+		if action.Kind == Create {
+			var item []byte
+			if item, err = p.dst.CreateItem(action.CollectionID, object.WithDefaults(fields), p.Live); err == nil {
+				action.ItemID = object.ID(item)
+				ids[action.SourceID] = action.ItemID
+			}
+		} else {
+			_, err = p.dst.PatchItem(action.CollectionID, action.ItemID, fields, p.Live)
+		}
+
+		if err != nil {
+			report.Failed = append(report.Failed, Failure{Action: action, Error: err.Error()})
+			continue
+		}
+		if later {
+			deferred = append(deferred, len(report.Applied))
+		}
+		report.Applied = append(report.Applied, action)
+	}
+
+	for _, i := range deferred {
+		action := report.Applied[i]
+		fields := map[string]interface{}{}
+		for key, ref := range action.Pending {
+			fields[key] = remap(ref, ids)
+		}
+		if _, err := p.dst.PatchItem(action.CollectionID, action.ItemID, fields, p.Live); err != nil {
+			report.Failed = append(report.Failed, Failure{Action: action, Error: fmt.Sprintf("unable to write references; error: %+v", err)})
+		}
+	}
+
+	return report
+}
+
+// Empty Whether the destination already has the promoted content.
+func (plan *Plan) Empty() bool {
+	return len(plan.Actions) == 0
+}
+
+// Count The number of actions of the given kind.
+func (plan *Plan) Count(kind string) int {
+	n := 0
+	for _, action := range plan.Actions {
+		if action.Kind == kind {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Render Write the plan for review: + for creates & ~ for updates with their changed fields, by collection.
+func (plan *Plan) Render(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	collection := ""
+	for _, action := range plan.Actions {
+		if action.Collection != collection {
+			collection = action.Collection
+			fmt.Fprintf(bw, "# %s\n", collection)
+		}
+
+		switch action.Kind {
+		case Create:
+			fmt.Fprintf(bw, "+ create %s\n", action.Key)
+		case Update:
+			fmt.Fprintf(bw, "~ update %s\n", action.Key)
+			for _, change := range action.Changes {
+				before := "(none)"
+				if change.Before != nil {
+					before = string(change.Before)
+				}
+				fmt.Fprintf(bw, "    %s: %s -> %s\n", change.Field, before, change.After)
+			}
+		}
+		for _, slug := range object.SortedKeys(action.Pending) {
+			fmt.Fprintf(bw, "    %s: references items created by the plan\n", slug)
+		}
+		for _, slug := range action.Assets {
+			fmt.Fprintf(bw, "    %s: re-upload\n", slug)
+		}
+		for _, warning := range action.Warnings {
+			fmt.Fprintf(bw, "    warning: %s\n", warning)
+		}
+	}
+
+	fmt.Fprintf(bw, "Plan: %d to create, %d to update, %d unchanged.\n", plan.Count(Create), plan.Count(Update), plan.Unchanged)
+
+	return bw.Flush()
+}
+
+// pair A source collection & its destination counterpart.
+type pair struct {
+	src, dst *webflowAPI.Collection
+}
+
+// state The collections & items a plan is made from.
+type state struct {
+	chosen []pair
+	// srcItems & dstItems Decoded items by collection ID.
+	srcItems, dstItems map[string][]map[string]interface{}
+	// srcRefs The collection slug & item slug of source items by ID.
+	srcRefs map[string][2]string
+	// dstIDs Destination item IDs by collection slug & item slug.
+	dstIDs map[[2]string]string
+}
+
+// load Read the chosen collections of both sites, ordered so referenced ones come first, along with the items of them
+// & the collections they reference.
+func (p *Promoter) load(refs []string) (*state, error) {
+	srcList, err := p.src.GetAllCollections()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the source collections; error: %+v", err)
+	}
+	dstList, err := p.dst.GetAllCollections()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the destination collections; error: %+v", err)
+	}
+
+	dstBySlug := map[string]webflowAPI.Collection{}
+	for _, c := range *dstList {
+		dstBySlug[c.Slug] = c
+	}
+
+	chosen := []webflowAPI.Collection{}
+	if len(refs) == 0 {
+		chosen = append(chosen, *srcList...)
+	}
+	for _, ref := range refs {
+		found := false
+		for _, c := range *srcList {
+			if c.Slug == ref || c.ID == ref || strings.EqualFold(c.Name, ref) {
+				chosen = append(chosen, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the source site has no collection '%s'", ref)
+		}
+	}
+
+	st := &state{
+		srcItems: map[string][]map[string]interface{}{},
+		dstItems: map[string][]map[string]interface{}{},
+		srcRefs:  map[string][2]string{},
+		dstIDs:   map[[2]string]string{},
+	}
+
+	schemas := map[string]*webflowAPI.Collection{}
+	for _, c := range chosen {
+		schema, err := p.src.GetCollectionByID(c.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the source collection '%s'; error: %+v", c.Slug, err)
+		}
+		schemas[c.ID] = schema
+	}
+
+	// The items of the chosen collections & those they reference are read from both sites.
+	srcSlugs := map[string]string{}
+	for _, c := range *srcList {
+		srcSlugs[c.ID] = c.Slug
+	}
+	needed := map[string]bool{}
+	for _, c := range chosen {
+		needed[c.ID] = true
+		for _, field := range schemas[c.ID].Fields {
+			if isRef(field) && field.Validations != nil && srcSlugs[field.Validations.CollectionID] != "" {
+				needed[field.Validations.CollectionID] = true
+			}
+		}
+	}
+
+	for id := range needed {
+		slug := srcSlugs[id]
+		dst, ok := dstBySlug[slug]
+		if !ok {
+			return nil, fmt.Errorf("the destination site has no collection '%s'", slug)
+		}
+
+		if st.srcItems[id], err = p.items(p.src, id); err != nil {
+			return nil, fmt.Errorf("unable to read the source items of '%s'; error: %+v", slug, err)
+		}
+		if st.dstItems[dst.ID], err = p.items(p.dst, dst.ID); err != nil {
+			return nil, fmt.Errorf("unable to read the destination items of '%s'; error: %+v", slug, err)
+		}
+
+		for _, item := range st.srcItems[id] {
+			st.srcRefs[object.Str(item["_id"])] = [2]string{slug, object.Str(item["slug"])}
+		}
+		for _, item := range st.dstItems[dst.ID] {
+			st.dstIDs[[2]string{slug, object.Str(item["slug"])}] = object.Str(item["_id"])
+		}
+	}
+
+	for _, c := range orderCollections(chosen, schemas) {
+		dst := dstBySlug[c.Slug]
+		st.chosen = append(st.chosen, pair{src: schemas[c.ID], dst: &dst})
+	}
+
+	return st, nil
+}
+
+// items The decoded items of a collection.
+func (p *Promoter) items(api webflowAPI.Interface, collectionID string) ([]map[string]interface{}, error) {
+	raw, err := api.GetAllItemsInCollectionByID(collectionID, p.MaxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []map[string]interface{}{}
+	for _, data := range raw {
+		item, err := object.Decode(data)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// action The action promoting a source item, and whether it changes anything.
+func (st *state) action(c pair, item map[string]interface{}, promoted map[string]bool) (Action, bool) {
+	slug := object.Str(item["slug"])
+	action := Action{
+		Collection:   c.src.Slug,
+		Key:          slug,
+		SourceID:     object.Str(item["_id"]),
+		Fields:       map[string]interface{}{},
+		CollectionID: c.dst.ID,
+	}
+
+	types := map[string]string{}
+	for _, field := range c.src.Fields {
+		types[field.Slug] = field.Type
+	}
+
+	for _, key := range object.SortedKeys(item) {
+		val := item[key]
+		if object.IsMetadata(key) {
+			continue
+		}
+
+		switch types[key] {
+		case webflowAPI.FieldTypeItemRef, webflowAPI.FieldTypeItemRefSet:
+			remapped, pending, dropped := st.remapRef(val, promoted)
+			for _, id := range dropped {
+				action.Warnings = append(action.Warnings, fmt.Sprintf("%s: item %s has no counterpart in the destination", key, id))
+			}
+			if pending != nil {
+				if action.Pending == nil {
+					action.Pending = map[string]interface{}{}
+				}
+				action.Pending[key] = pending
+				continue
+			}
+			action.Fields[key] = remapped
+		case webflowAPI.FieldTypeImageRef, webflowAPI.FieldTypeExtFileRef, webflowAPI.FieldTypeSet:
+			action.Fields[key] = reupload(val)
+		default:
+			action.Fields[key] = val
+		}
+	}
+
+	existing := st.dstIDs[[2]string{c.src.Slug, slug}]
+	if existing == "" {
+		action.Kind = Create
+		for key, val := range action.Fields {
+			if isAsset(types[key]) && val != nil {
+				action.Assets = append(action.Assets, key)
+			}
+		}
+		sort.Strings(action.Assets)
+		return action, true
+	}
+
+	action.Kind = Update
+	action.ItemID = existing
+	current := map[string]interface{}{}
+	for _, other := range st.dstItems[c.dst.ID] {
+		if object.Str(other["_id"]) == existing {
+			current = other
+		}
+	}
+
+	for _, key := range object.SortedKeys(action.Fields) {
+		val := action.Fields[key]
+		same := reflect.DeepEqual(normalize(val), normalize(current[key]))
+		if isAsset(types[key]) {
+			same = sameAssets(val, current[key])
+		}
+		if same {
+			delete(action.Fields, key)
+			continue
+		}
+		if isAsset(types[key]) {
+			action.Assets = append(action.Assets, key)
+		}
+		before, _ := json.Marshal(current[key])
+		after, _ := json.Marshal(val)
+		if _, ok := current[key]; !ok {
+			before = nil
+		}
+		action.Changes = append(action.Changes, diff.FieldChange{Field: key, Before: before, After: after})
+	}
+
+	return action, len(action.Fields) > 0 || len(action.Pending) > 0
+}
+
+// remapRef Replace the source item IDs of a reference with destination IDs, matched by slug. References to promoted
+// items missing from the destination are returned as pending; those to items neither promoted nor in the destination
+// are dropped.
+func (st *state) remapRef(val interface{}, promoted map[string]bool) (interface{}, interface{}, []string) {
+	pending := false
+	dropped := []string{}
+
+	lookup := func(id string) (string, bool) {
+		if dstID, ok := st.dstIDs[st.srcRefs[id]]; ok && st.srcRefs[id] != [2]string{} {
+			return dstID, true
+		}
+		if promoted[id] {
+			pending = true
+		} else {
+			dropped = append(dropped, id)
+		}
+		return "", false
+	}
+
+	switch v := val.(type) {
+	case string:
+		dstID, ok := lookup(v)
+		if pending {
+			return nil, v, dropped
+		}
+		if !ok {
+			return nil, nil, dropped
+		}
+		return dstID, nil, dropped
+	case []interface{}:
+		remapped := []interface{}{}
+		for _, id := range v {
+			if dstID, ok := lookup(object.Str(id)); ok {
+				remapped = append(remapped, dstID)
+			}
+		}
+		if pending {
+			// Written once the promoted items exist, with the source IDs then remapped.
+			return nil, v, dropped
+		}
+		return remapped, nil, dropped
+	}
+
+	return val, nil, dropped
+}
+
+// orderCollections Put the collections that others reference first. Cycles keep the source's order.
+func orderCollections(collections []webflowAPI.Collection, schemas map[string]*webflowAPI.Collection) []webflowAPI.Collection {
+	byID := map[string]webflowAPI.Collection{}
+	for _, c := range collections {
+		byID[c.ID] = c
+	}
+
+	ordered := []webflowAPI.Collection{}
+	state := map[string]int{} // 1 visiting, 2 done
+	var visit func(c webflowAPI.Collection)
+	visit = func(c webflowAPI.Collection) {
+		if state[c.ID] != 0 {
+			return
+		}
+		state[c.ID] = 1
+		for _, field := range schemas[c.ID].Fields {
+			if field.Validations == nil {
+				continue
+			}
+			if ref, ok := byID[field.Validations.CollectionID]; ok {
+				visit(ref)
+			}
+		}
+		state[c.ID] = 2
+		ordered = append(ordered, c)
+	}
+	for _, c := range collections {
+		visit(c)
+	}
+
+	return ordered
+}
+
+// reupload The value of an image or file field without the source site's file IDs, so Webflow uploads the files again
+// from their URLs.
+func reupload(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		file := map[string]interface{}{}
+		for key, elem := range v {
+			if key == "url" || key == "alt" {
+				file[key] = elem
+			}
+		}
+		return file
+	case []interface{}:
+		files := []interface{}{}
+		for _, elem := range v {
+			files = append(files, reupload(elem))
+		}
+		return files
+	}
+
+	return val
+}
+
+// sameAssets Whether two image or file values hold the same files. The sites host files at different URLs, named
+// <file ID>_<file name>, so files are compared by name & alt text.
+func sameAssets(a, b interface{}) bool {
+	return reflect.DeepEqual(assetNames(a), assetNames(b))
+}
+
+// assetNames The file names & alt texts of an image or file value.
+func assetNames(val interface{}) []string {
+	switch v := val.(type) {
+	case string:
+		return []string{fileName(v)}
+	case map[string]interface{}:
+		return []string{fileName(object.Str(v["url"])) + "|" + object.Str(v["alt"])}
+	case []interface{}:
+		names := []string{}
+		for _, elem := range v {
+			names = append(names, assetNames(elem)...)
+		}
+		return names
+	}
+
+	return nil
+}
+
+// fileName The name of a hosted file without its file ID prefix.
+func fileName(url string) string {
+	name := path.Base(url)
+	if i := strings.Index(name, "_"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name
+}
+
+// resolved Whether every source item a reference names has been promoted.
+func resolved(ref interface{}, ids map[string]string) bool {
+	switch v := ref.(type) {
+	case string:
+		_, ok := ids[v]
+		return ok
+	case []interface{}:
+		for _, id := range v {
+			if !resolved(id, ids) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// remap Replace the source item IDs of a reference with those of the promoted items. References to items that were
+// not promoted are dropped.
+func remap(ref interface{}, ids map[string]string) interface{} {
+	switch v := ref.(type) {
+	case string:
+		if id, ok := ids[v]; ok {
+			return id
+		}
+		return nil
+	case []interface{}:
+		remapped := []interface{}{}
+		for _, id := range v {
+			if newID := remap(id, ids); newID != nil {
+				remapped = append(remapped, newID)
+			}
+		}
+		return remapped
+	}
+
+	return ref
+}
+
+// normalize A value as it compares after a JSON round trip, so e.g. []string & []interface{} are equal.
+func normalize(val interface{}) interface{} {
+	data, _ := json.Marshal(val)
+	normalized, _ := decodeValue(data)
+
+	return normalized
+}
+
+// isRef Whether the field references items.
+func isRef(field webflowAPI.CollectionField) bool {
+	return field.Type == webflowAPI.FieldTypeItemRef || field.Type == webflowAPI.FieldTypeItemRefSet
+}
+
+// isAsset Whether fields of the type hold images or files.
+func isAsset(fieldType string) bool {
+	return fieldType == webflowAPI.FieldTypeImageRef || fieldType == webflowAPI.FieldTypeExtFileRef || fieldType == webflowAPI.FieldTypeSet
+}
+
+// decodeValue Decode any JSON value, keeping numbers exactly as they were given.
+func decodeValue(raw []byte) (interface{}, error) {
+	var val interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	err := decoder.Decode(&val)
+
+	return val, err
+}
+
+// ChangedSince A filter promoting items created or updated after the given time.
+func ChangedSince(since time.Time) Filter {
+	return func(collection string, item map[string]interface{}) bool {
+		for _, key := range []string{"updated-on", "created-on"} {
+			if t, err := time.Parse(time.RFC3339Nano, object.Str(item[key])); err == nil && t.After(since) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Slugs A filter promoting only the items with the given slugs.
+func Slugs(slugs ...string) Filter {
+	wanted := map[string]bool{}
+	for _, slug := range slugs {
+		wanted[slug] = true
+	}
+
+	return func(collection string, item map[string]interface{}) bool {
+		return wanted[object.Str(item["slug"])]
+	}
+}
+
+// Published A filter promoting only items that are neither drafts nor archived.
+func Published(collection string, item map[string]interface{}) bool {
+	return item["_draft"] != true && item["_archived"] != true
+}
@@ -0,0 +1,197 @@
+package promote
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI/memory"
+)
+
+func load(t *testing.T) (*memory.Store, *memory.Store) {
+	staging, err := memory.Load("stagingsiteid", "testdata/staging.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	production, err := memory.Load("productionsiteid", "testdata/production.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return staging, production
+}
+
+// items The destination's items of a collection by slug.
+func items(t *testing.T, store *memory.Store, collectionID string) map[string]map[string]interface{} {
+	raw, err := store.GetAllItemsInCollectionByID(collectionID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bySlug := map[string]map[string]interface{}{}
+	for _, data := range raw {
+		item := map[string]interface{}{}
+		if err := json.Unmarshal(data, &item); err != nil {
+			t.Fatal(err)
+		}
+		bySlug[item["slug"].(string)] = item
+	}
+
+	return bySlug
+}
+
+func TestPlan(t *testing.T) {
+	staging, production := load(t)
+
+	plan, err := New(staging, production).Plan(nil, nil)
+	if err != nil {
+		t.Fatalf("Unable to plan: %+v", err)
+	}
+
+	got := []string{}
+	for _, action := range plan.Actions {
+		got = append(got, action.Kind+" "+action.Collection+"/"+action.Key)
+	}
+	expected := []string{"create authors/grace", "update posts/hello", "create posts/new-post", "create posts/wip"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected the actions %v; got %v", expected, got)
+	}
+	if plan.Unchanged != 2 {
+		t.Errorf("Expected 2 unchanged items; got %d", plan.Unchanged)
+	}
+
+	// The author & cover of hello are the same, despite their different IDs & URLs.
+	hello := plan.Actions[1]
+	if hello.ItemID != "q1" || len(hello.Changes) != 1 || hello.Changes[0].Field != "name" {
+		t.Errorf("Expected only the name of q1 to change; got %+v", hello)
+	}
+
+	post := plan.Actions[2]
+	if !reflect.DeepEqual(post.Pending, map[string]interface{}{"author": "a2", "editors": []interface{}{"a1", "a2"}}) {
+		t.Errorf("Expected the references to grace to be pending; got %v", post.Pending)
+	}
+	if !reflect.DeepEqual(post.Fields["cover"], map[string]interface{}{"url": "https://uploads.example.com/s2_new.png", "alt": ""}) {
+		t.Errorf("Expected the cover to be re-uploaded from its URL; got %v", post.Fields["cover"])
+	}
+	if !reflect.DeepEqual(post.Assets, []string{"cover"}) {
+		t.Errorf("Expected the cover to be listed as re-uploaded; got %v", post.Assets)
+	}
+
+	out := &bytes.Buffer{}
+	if err := plan.Render(out); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"# authors\n+ create grace\n",
+		"~ update hello\n    name: \"Hello\" -> \"Hello again\"\n",
+		"    author: references items created by the plan\n",
+		"    cover: re-upload\n",
+		"Plan: 3 to create, 1 to update, 2 unchanged.\n",
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected the plan to contain %q; got:\n%s", line, out)
+		}
+	}
+
+	if _, err := New(staging, production).Plan([]string{"missing"}, nil); err == nil {
+		t.Error("Expected an error for an unknown collection")
+	}
+}
+
+func TestPromote(t *testing.T) {
+	staging, production := load(t)
+
+	plan, report, err := New(staging, production).Promote([]string{"posts"}, Published, false)
+	if err != nil {
+		t.Fatalf("Unable to promote: %+v", err)
+	}
+	if plan.Count(Create) != 1 || plan.Count(Update) != 1 {
+		t.Fatalf("Expected wip to be filtered & grace left alone; got %+v", plan.Actions)
+	}
+	if len(report.Failed) != 0 {
+		t.Fatalf("Expected no failures; got %+v", report.Failed)
+	}
+
+	// Grace was not promoted, so the references to her are dropped.
+	post := items(t, production, "posts2")["new-post"]
+	if post["author"] != nil || !reflect.DeepEqual(post["editors"], []interface{}{"b1"}) {
+		t.Errorf("Expected only the references to ada to be kept; got %v", post)
+	}
+	if items(t, production, "posts2")["hello"]["name"] != "Hello again" {
+		t.Errorf("Expected hello to be updated")
+	}
+
+	// Promoting everything creates grace first, then writes the references to her.
+	staging, production = load(t)
+	_, report, err = New(staging, production).Promote(nil, nil, false)
+	if err != nil {
+		t.Fatalf("Unable to promote: %+v", err)
+	}
+	if len(report.Applied) != 4 || len(report.Failed) != 0 {
+		t.Fatalf("Expected every action to be applied; got %+v", report)
+	}
+
+	grace := items(t, production, "authors2")["grace"]
+	post = items(t, production, "posts2")["new-post"]
+	if post["author"] != grace["_id"] || !reflect.DeepEqual(post["editors"], []interface{}{"b1", grace["_id"]}) {
+		t.Errorf("Expected the references to be remapped to b1 & %v; got %v", grace["_id"], post)
+	}
+	if items(t, production, "posts2")["wip"]["_draft"] != true {
+		t.Errorf("Expected wip to be promoted as a draft")
+	}
+
+	plan, _, err = New(staging, production).Promote(nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("Expected nothing left to promote; got %+v", plan.Actions)
+	}
+}
+
+func TestApplySavedPlan(t *testing.T) {
+	staging, production := load(t)
+
+	plan, err := New(staging, production).Plan(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A plan read back from JSON applies just like the one planned.
+	saved := &Plan{}
+	if err := json.Unmarshal(data, saved); err != nil {
+		t.Fatal(err)
+	}
+	report := New(staging, production).Apply(saved)
+	if len(report.Applied) != 4 || len(report.Failed) != 0 {
+		t.Fatalf("Expected every action of the saved plan to be applied; got %+v", report)
+	}
+
+	grace := items(t, production, "authors2")["grace"]
+	post := items(t, production, "posts2")["new-post"]
+	if post["author"] != grace["_id"] || !reflect.DeepEqual(post["editors"], []interface{}{"b1", grace["_id"]}) {
+		t.Errorf("Expected the references to be remapped to b1 & %v; got %v", grace["_id"], post)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	item := map[string]interface{}{"slug": "hello", "_draft": true, "updated-on": "2020-03-01T00:00:00.000Z"}
+
+	if !Slugs("hello", "other")("posts", item) || Slugs("other")("posts", item) {
+		t.Error("Expected Slugs to match by slug")
+	}
+	if !ChangedSince(time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC))("posts", item) ||
+		ChangedSince(time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC))("posts", item) {
+		t.Error("Expected ChangedSince to compare the updated-on time")
+	}
+	if Published("posts", item) {
+		t.Error("Expected drafts not to be published")
+	}
+}
@@ -0,0 +1,356 @@
+// Package reconcile Manage a collection's items as code. The desired items are read from YAML or JSON files, compared
+// to the live collection to plan the creates, updates & deletes needed, and the plan is applied with the write API at
+// a limited rate. Failed actions are reported rather than stopping the rest of the plan.
+package reconcile
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/diff"
+	"github.com/redeemed2011/webflowAPI/internal/object"
+	"github.com/sethgrid/pester"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// Kinds of Action.
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+
+	// DefaultRequestsPerMinute Write rate used by Apply; Webflow allows 60 requests a minute on most plans.
+	DefaultRequestsPerMinute = 60
+	// DefaultMaxPages Number of additional pages of items requested per collection.
+	DefaultMaxPages = 100
+	// DefaultPageRetries Number of times Plan requests a failed page of items again.
+	DefaultPageRetries = 3
+)
+
+// Desired The items a collection should hold, e.g.:
+//
+//	collection: faqs
+//	key: slug
+//	prune: true
+//	items:
+//	  - slug: shipping
+//	    name: How long does shipping take?
+//	    answer: 3-5 working days.
+//
+// Fields an item leaves out are not changed. Live items missing from Items are only deleted when Prune is set.
+type Desired struct {
+	// Collection Slug, name or ID of the collection.
+	Collection string `yaml:"collection"`
+	// Key Field matching desired items to live ones. Defaults to slug.
+	Key string `yaml:"key"`
+	// Prune Delete live items that are not desired.
+	Prune bool                     `yaml:"prune"`
+	Items []map[string]interface{} `yaml:"items"`
+}
+
+// Action A change to one item.
+type Action struct {
+	Kind   string `json:"kind"`
+	Key    string `json:"key"`
+	ItemID string `json:"itemId,omitempty"`
+	// Fields The fields written by a create or update.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+	// Changes The before & after values of the fields an update changes.
+	Changes []diff.FieldChange `json:"changes,omitempty"`
+}
+
+// Plan The actions that bring a collection to the desired state.
+type Plan struct {
+	CollectionID string   `json:"collectionId"`
+	Key          string   `json:"key"`
+	Actions      []Action `json:"actions"`
+	// Warnings e.g. live items without the key field, which are left alone.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Failure An action that could not be applied.
+type Failure struct {
+	Action Action `json:"action"`
+	Error  string `json:"error"`
+}
+
+// Report The outcome of applying a plan.
+type Report struct {
+	Applied []Action  `json:"applied"`
+	Failed  []Failure `json:"failed,omitempty"`
+}
+
+// Reconciler Plans & applies desired states.
+type Reconciler struct {
+	// Live Publish created & updated items immediately.
+	Live bool
+	// RequestsPerMinute Maximum write rate of Apply; 0 for no limit.
+	RequestsPerMinute int
+	// MaxPages Number of additional pages of items to request. See webflowAPI.Interface.GetAllItemsInCollectionByID.
+	MaxPages int
+	// PageRetries Number of times Plan requests a failed page of items again, resuming the read from where it stopped
+	// rather than starting over.
+	PageRetries int
+	// Backoff Delay before the given retry of a failed page.
+	Backoff func(retry int) time.Duration
+
+	api webflowAPI.Interface
+	// Override for sleeping between writes. Use only for internal testing of the pkg.
+	sleep func(time.Duration)
+}
+
+// New Create a reconciler writing through the given API.
+func New(api webflowAPI.Interface) *Reconciler {
+	return &Reconciler{
+		RequestsPerMinute: DefaultRequestsPerMinute,
+		MaxPages:          DefaultMaxPages,
+		PageRetries:       DefaultPageRetries,
+		Backoff:           pester.ExponentialBackoff,
+		api:               api,
+		sleep:             time.Sleep,
+	}
+}
+
+// LoadDesired Read a YAML, or JSON, desired state file.
+func LoadDesired(path string) (*Desired, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	desired := &Desired{}
+	if err := yaml.UnmarshalStrict(data, desired); err != nil {
+		return nil, fmt.Errorf("unable to decode the desired state %s; error: %+v", path, err)
+	}
+
+	// YAML decodes nested objects with interface{} keys, which JSON cannot encode.
+	for i, item := range desired.Items {
+		desired.Items[i] = toJSONValue(item).(map[string]interface{})
+	}
+
+	return desired, nil
+}
+
+// Plan Compare the desired items to the live collection.
+func (r *Reconciler) Plan(collectionID string, desired *Desired) (*Plan, error) {
+	key := desired.Key
+	if key == "" {
+		key = diff.DefaultKey
+	}
+
+	items, err := r.items(collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{CollectionID: collectionID, Key: key, Actions: []Action{}}
+
+	// Live items without the key cannot be matched to desired ones.
+	live := [][]byte{}
+	for _, item := range items {
+		fields := map[string]json.RawMessage{}
+		json.Unmarshal(item, &fields)
+		k := ""
+		if err := json.Unmarshal(fields[key], &k); err != nil || k == "" {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("live item %s has no '%s' and is left alone", object.ID(item), key))
+			continue
+		}
+		live = append(live, item)
+	}
+
+	wanted := make([][]byte, len(desired.Items))
+	for i, item := range desired.Items {
+		if wanted[i], err = json.Marshal(item); err != nil {
+			return nil, fmt.Errorf("unable to encode desired item %d; error: %+v", i+1, err)
+		}
+	}
+
+	result, err := diff.Diff(live, wanted, key)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range result.Items {
+		switch item.Kind {
+		case diff.Added:
+			action := Action{Kind: Create, Key: item.Key}
+			json.Unmarshal(item.After, &action.Fields)
+			plan.Actions = append(plan.Actions, action)
+
+		case diff.Changed:
+			// Fields the desired item leaves out are not managed.
+			action := Action{Kind: Update, Key: item.Key, ItemID: object.ID(item.Before), Fields: map[string]interface{}{}}
+			for _, change := range item.Changes {
+				if change.After == nil {
+					continue
+				}
+				var val interface{}
+				json.Unmarshal(change.After, &val)
+				action.Fields[change.Field] = val
+				action.Changes = append(action.Changes, change)
+			}
+			if len(action.Changes) > 0 {
+				plan.Actions = append(plan.Actions, action)
+			}
+
+		case diff.Removed:
+			if desired.Prune {
+				plan.Actions = append(plan.Actions, Action{Kind: Delete, Key: item.Key, ItemID: object.ID(item.Before)})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// items Read the collection's items a page at a time, requesting a failed page again from the cursor. Errors when the
+// items do not fit in MaxPages.
+func (r *Reconciler) items(collectionID string) ([][]byte, error) {
+	items := [][]byte{}
+	paginator := webflowAPI.NewPaginator(r.api, webflowAPI.NewCursor(collectionID))
+
+	retry := 0
+	for page := 0; !paginator.Done() && (page == 0 || page <= r.MaxPages); {
+		pageItems, err := paginator.Next()
+		if err != nil {
+			if retry >= r.PageRetries {
+				return nil, fmt.Errorf("unable to read the items from offset %d; error: %+v", paginator.Cursor().Offset, err)
+			}
+			retry++
+			r.sleep(r.Backoff(retry))
+			continue
+		}
+
+		retry = 0
+		items = append(items, pageItems...)
+		page++
+	}
+
+	// Items past the last page read would be planned as creates, duplicating them.
+	if !paginator.Done() {
+		return nil, fmt.Errorf("read %d of the %d items within MaxPages %d; raise MaxPages", len(items), paginator.Cursor().Total, r.MaxPages)
+	}
+
+	return items, nil
+}
+
+// Apply Make the plan's changes, at no more than RequestsPerMinute. Every action is attempted; those that fail are
+// reported.
+func (r *Reconciler) Apply(plan *Plan) *Report {
+	report := &Report{Applied: []Action{}}
+
+	var interval time.Duration
+	if r.RequestsPerMinute > 0 {
+		interval = time.Minute / time.Duration(r.RequestsPerMinute)
+	}
+
+	for i, action := range plan.Actions {
+		if i > 0 && interval > 0 {
+			r.sleep(interval)
+		}
+
+		var err error
+		switch action.Kind {
+		case Create:
+			var item []byte
+			if item, err = r.api.CreateItem(plan.CollectionID, object.WithDefaults(action.Fields), r.Live); err == nil {
+				action.ItemID = object.ID(item)
+			}
+		case Update:
+			_, err = r.api.PatchItem(plan.CollectionID, action.ItemID, action.Fields, r.Live)
+		case Delete:
+			err = r.api.DeleteItem(plan.CollectionID, action.ItemID)
+		default:
+			err = fmt.Errorf("unknown action '%s'", action.Kind)
+		}
+
+		if err != nil {
+			report.Failed = append(report.Failed, Failure{Action: action, Error: err.Error()})
+			continue
+		}
+		report.Applied = append(report.Applied, action)
+	}
+
+	return report
+}
+
+// Empty Whether the collection is already in the desired state.
+func (plan *Plan) Empty() bool {
+	return len(plan.Actions) == 0
+}
+
+// Count The number of actions of the given kind.
+func (plan *Plan) Count(kind string) int {
+	n := 0
+	for _, action := range plan.Actions {
+		if action.Kind == kind {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Render Write the plan for review: + for creates, ~ for updates with their changed fields & - for deletes.
+func (plan *Plan) Render(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	for _, action := range plan.Actions {
+		switch action.Kind {
+		case Create:
+			fmt.Fprintf(bw, "+ create %s\n", action.Key)
+			for _, name := range object.SortedKeys(action.Fields) {
+				val, _ := json.Marshal(action.Fields[name])
+				fmt.Fprintf(bw, "    %s: %s\n", name, val)
+			}
+		case Update:
+			fmt.Fprintf(bw, "~ update %s\n", action.Key)
+			for _, change := range action.Changes {
+				before := "(none)"
+				if change.Before != nil {
+					before = string(change.Before)
+				}
+				fmt.Fprintf(bw, "    %s: %s -> %s\n", change.Field, before, change.After)
+			}
+		case Delete:
+			fmt.Fprintf(bw, "- delete %s\n", action.Key)
+		}
+	}
+
+	for _, warning := range plan.Warnings {
+		fmt.Fprintf(bw, "warning: %s\n", warning)
+	}
+
+	fmt.Fprintf(bw, "Plan: %d to create, %d to update, %d to delete.\n", plan.Count(Create), plan.Count(Update), plan.Count(Delete))
+
+	return bw.Flush()
+}
+
+// toJSONValue Convert the maps decoded from YAML to maps with string keys.
+func toJSONValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[interface{}]interface{}:
+		m := map[string]interface{}{}
+		for key, elem := range v {
+			m[fmt.Sprint(key)] = toJSONValue(elem)
+		}
+		return m
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = toJSONValue(elem)
+		}
+		return v
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = toJSONValue(elem)
+		}
+		return v
+	}
+
+	return val
+}
@@ -0,0 +1,160 @@
+package reconcile
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/memory"
+)
+
+func newTestStore(t *testing.T) *memory.Store {
+	store := memory.New("mysiteid")
+	store.AddCollection(webflowAPI.Collection{ID: "faqs1", Name: "FAQs", Slug: "faqs"})
+	err := store.AddItems("faqs1",
+		[]byte(`{"_id":"q1","slug":"shipping","name":"How long does shipping take?","answer":"A week.","featured":true}`),
+		[]byte(`{"_id":"q2","slug":"payment","name":"How can I pay?","answer":"By card."}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func TestPlan(t *testing.T) {
+	desired, err := LoadDesired("testdata/faqs.yaml")
+	if err != nil {
+		t.Fatalf("LoadDesired() is expected to read the YAML: %+v", err)
+	}
+
+	r := New(newTestStore(t))
+	plan, err := r.Plan("faqs1", desired)
+	if err != nil {
+		t.Fatalf("Plan() is expected to compare the desired & live items: %+v", err)
+	}
+
+	if plan.Count(Create) != 2 || plan.Count(Update) != 1 || plan.Count(Delete) != 1 {
+		t.Fatalf("Plan() is expected to create 2, update 1 & delete 1 item! Got %+v.", plan.Actions)
+	}
+
+	for _, action := range plan.Actions {
+		if action.Kind == Update && (action.ItemID != "q1" || len(action.Fields) != 1 || action.Fields["answer"] != "3-5 working days.") {
+			t.Errorf("Plan() is expected to update only the changed, managed fields! Got %+v.", action)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	plan.Render(buf)
+	for _, line := range []string{"+ create returns", `    answer: "A week." -> "3-5 working days."`, "- delete payment", "Plan: 2 to create, 1 to update, 1 to delete."} {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("Render() is expected to write '%s'! Got:\n%s", line, buf.String())
+		}
+	}
+
+	desired.Prune = false
+	if plan, _ := r.Plan("faqs1", desired); plan.Count(Delete) != 0 {
+		t.Errorf("Plan() is expected not to delete without prune! Got %+v.", plan.Actions)
+	}
+}
+
+func TestPlanKeyless(t *testing.T) {
+	desired, _ := LoadDesired("testdata/faqs.yaml")
+	store := newTestStore(t)
+	store.AddItems("faqs1", []byte(`{"_id":"q3","name":"Made by hand"}`))
+
+	plan, err := New(store).Plan("faqs1", desired)
+	if err != nil || plan.Count(Update) != 1 {
+		t.Fatalf("Plan() is expected to plan around live items without the key! Got %+v; error %+v.", plan, err)
+	}
+	if len(plan.Warnings) != 1 || !strings.Contains(plan.Warnings[0], "q3") {
+		t.Errorf("Plan() is expected to warn about the live item without the key! Got %+v.", plan.Warnings)
+	}
+
+	buf := &bytes.Buffer{}
+	plan.Render(buf)
+	if !strings.Contains(buf.String(), "warning: live item q3 has no 'slug'") {
+		t.Errorf("Render() is expected to write the warnings! Got:\n%s", buf.String())
+	}
+}
+
+// flakyStore Fails the first requests for the page at the given offset.
+type flakyStore struct {
+	*memory.Store
+	offset   string
+	failures int
+	requests []string
+}
+
+func (s *flakyStore) MethodGet(uri string, queryParams map[string]string, decodedResponse interface{}) error {
+	s.requests = append(s.requests, queryParams["offset"])
+	if queryParams["offset"] == s.offset && s.failures > 0 {
+		s.failures--
+		return errors.New("Service Unavailable")
+	}
+	return s.Store.MethodGet(uri, queryParams, decodedResponse)
+}
+
+func TestPlanRetries(t *testing.T) {
+	desired, _ := LoadDesired("testdata/faqs.yaml")
+	store := newTestStore(t)
+	store.PageSize = 1
+
+	flaky := &flakyStore{Store: store, offset: "1", failures: 2}
+	r := New(flaky)
+	slept := 0
+	r.sleep = func(d time.Duration) { slept++ }
+
+	plan, err := r.Plan("faqs1", desired)
+	if err != nil || plan.Count(Update) != 1 {
+		t.Fatalf("Plan() is expected to recover from the failed page! Got %+v; error %+v.", plan, err)
+	}
+	if expected := []string{"0", "1", "1", "1"}; !reflect.DeepEqual(flaky.requests, expected) || slept != 2 {
+		t.Errorf("Plan() is expected to request only the failed page again! Got %v after %d sleeps.", flaky.requests, slept)
+	}
+
+	// Live items past MaxPages would be planned as creates, so the plan fails instead.
+	r.MaxPages = 0
+	if _, err := r.Plan("faqs1", desired); err == nil || !strings.Contains(err.Error(), "MaxPages") {
+		t.Errorf("Plan() is expected to fail when the items do not fit in MaxPages! Got %+v.", err)
+	}
+	r.MaxPages = DefaultMaxPages
+
+	flaky.failures, flaky.requests = 10, nil
+	if _, err := r.Plan("faqs1", desired); err == nil || len(flaky.requests) != 1+1+r.PageRetries {
+		t.Errorf("Plan() is expected to give up after %d retries! Got %v; error %+v.", r.PageRetries, flaky.requests, err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	desired, _ := LoadDesired("testdata/faqs.yaml")
+	store := newTestStore(t)
+
+	slept := []time.Duration{}
+	r := New(store)
+	r.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	plan, _ := r.Plan("faqs1", desired)
+	report := r.Apply(plan)
+
+	if len(report.Applied) != 3 || len(report.Failed) != 1 || report.Failed[0].Action.Key != "nameless" {
+		t.Fatalf("Apply() is expected to apply every action & report the failure! Got %+v.", report)
+	}
+	if len(slept) != 3 || slept[0] != time.Second {
+		t.Errorf("Apply() is expected to space the writes by the rate limit! Got %+v.", slept)
+	}
+
+	item, _ := store.GetItem("", "", "faqs1", "", "q1")
+	if !strings.Contains(string(item), "3-5 working days.") || !strings.Contains(string(item), `"featured":true`) {
+		t.Errorf("Apply() is expected to patch the managed fields only! Got %s.", item)
+	}
+
+	plan, _ = r.Plan("faqs1", desired)
+	if plan.Count(Create) != 1 || plan.Count(Update) != 0 || plan.Count(Delete) != 0 {
+		t.Errorf("Plan() is expected to only retry the failed create after applying! Got %+v.", plan.Actions)
+	}
+}
@@ -0,0 +1,253 @@
+// Package recorder http.RoundTripper that records real Webflow request/response pairs to a cassette file and replays
+// them offline, for deterministic tests without a network. The bearer token is redacted before anything is written.
+//
+//	rec, err := recorder.New("testdata/collections.json", recorder.ModeAuto)
+//	api := webflowAPI.New(os.Getenv("WEBFLOW_TOKEN"), siteID, rec.Client())
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mode How the recorder treats requests.
+type Mode int
+
+const (
+	// ModeReplay Only replay recorded interactions; requests that were not recorded fail.
+	ModeReplay Mode = iota
+	// ModeRecord Make every request over the network and record it, replacing the cassette's previous content.
+	ModeRecord
+	// ModeAuto Replay when the cassette file exists, otherwise record.
+	ModeAuto
+)
+
+const (
+	redacted = "REDACTED"
+)
+
+// Interaction A recorded request & its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request The parts of a request used to match it on replay.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+// Response A recorded response.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Cassette File format of the recorded interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder Recording & replaying http.RoundTripper.
+type Recorder struct {
+	// Transport Used to make real requests while recording. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	mode     Mode
+	path     string
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New Create a recorder for the cassette file at path. Replaying loads the cassette immediately.
+func New(path string, mode Mode) (*Recorder, error) {
+	if mode == ModeAuto {
+		mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			mode = ModeReplay
+		}
+	}
+
+	r := &Recorder{
+		Transport: http.DefaultTransport,
+		mode:      mode,
+		path:      path,
+		cassette:  &Cassette{},
+	}
+
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read cassette; error: %+v", err)
+		}
+		if err := json.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("unable to decode cassette %s; error: %+v", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// Mode Whether the recorder is replaying or recording.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client An http.Client using the recorder, suitable for webflowAPI.New.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip Replay the matching recorded interaction, or make the request and record it.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, string(body))
+	}
+
+	return r.record(req, string(body))
+}
+
+// replay Respond with the first unused interaction matching the request. Once every match has been used the last one
+// is repeated. Unmatched requests receive a 404 rather than an error so retrying clients fail fast.
+func (r *Recorder) replay(req *http.Request, body string) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	found := -1
+	for i, interaction := range r.cassette.Interactions {
+		if !matches(interaction.Request, req, body) {
+			continue
+		}
+		found = i
+		if !r.used[i] {
+			break
+		}
+	}
+
+	if found < 0 {
+		msg := fmt.Sprintf("no recorded interaction for %s %s", req.Method, req.URL.RequestURI())
+		data, _ := json.Marshal(map[string]interface{}{
+			"msg":  msg,
+			"code": http.StatusNotFound,
+			"name": "NotRecorded",
+			"path": req.URL.Path,
+			"err":  "NotRecorded: " + msg,
+		})
+		return newResponse(req, http.StatusNotFound, http.Header{"Content-Type": {"application/json"}}, data), nil
+	}
+
+	r.used[found] = true
+	res := r.cassette.Interactions[found].Response
+
+	return newResponse(req, res.StatusCode, res.Headers, []byte(res.Body)), nil
+}
+
+// record Make the request then append it, redacted, to the cassette.
+func (r *Recorder) record(req *http.Request, body string) (*http.Response, error) {
+	res, err := r.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	headers := http.Header{}
+	for key, vals := range req.Header {
+		headers[key] = vals
+	}
+	if headers.Get("Authorization") != "" {
+		headers.Set("Authorization", "Bearer "+redacted)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.RequestURI(),
+			Headers: headers,
+			Body:    body,
+		},
+		Response: Response{
+			StatusCode: res.StatusCode,
+			Headers:    res.Header,
+			Body:       string(data),
+		},
+	})
+
+	// Save after every interaction so a failing test still leaves a usable cassette behind.
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// save Write the cassette to its file. Must be called with the lock held.
+func (r *Recorder) save() error {
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("unable to write cassette; error: %+v", err)
+	}
+
+	return nil
+}
+
+// matches Whether a recorded request matches a live one. The host is ignored so the base URL may change.
+func matches(recorded Request, req *http.Request, body string) bool {
+	return strings.EqualFold(recorded.Method, req.Method) &&
+		recorded.URL == req.URL.RequestURI() &&
+		recorded.Body == body
+}
+
+// newResponse Build a response for the request.
+func newResponse(req *http.Request, status int, headers http.Header, body []byte) *http.Response {
+	if headers == nil {
+		headers = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        headers,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,88 @@
+package recorder
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/webflowtest"
+)
+
+const (
+	siteID = "mysiteid"
+	token  = "supersecrettoken"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webflow-recorder")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+
+	server := webflowtest.NewServer(&webflowtest.Fixture{Sites: []webflowtest.SiteFixture{{
+		Site: webflowAPI.Site{ID: siteID},
+		Collections: []webflowtest.CollectionFixture{{
+			Collection: webflowAPI.Collection{ID: "1", Name: "Dogs", Slug: "dogs"},
+			Items:      []json.RawMessage{json.RawMessage(`{"_id":"d1","name":"blue"}`)},
+		}},
+	}}})
+	baseURL := server.URL
+
+	// Record.
+	{
+		rec, err := New(path, ModeAuto)
+		if err != nil {
+			t.Fatalf("New() is expected to create the recorder: %+v", err)
+		}
+		if rec.Mode() != ModeRecord {
+			t.Errorf("New() is expected to record when the cassette does not exist! Got mode %d.", rec.Mode())
+		}
+
+		api := webflowAPI.New(token, siteID, rec.Client())
+		api.BaseURL = baseURL
+		items, err := api.GetAllItemsInCollectionBySlug("dogs", 10)
+		if err != nil || len(items) != 1 {
+			t.Errorf("Recording is expected to pass requests through! Got %d items; error %+v.", len(items), err)
+		}
+	}
+
+	server.Close()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Recording is expected to write the cassette: %+v", err)
+	}
+
+	if strings.Contains(string(data), token) || !strings.Contains(string(data), redacted) {
+		t.Error("Recording is expected to redact the bearer token from the cassette.")
+	}
+
+	// Replay, with the server gone.
+	{
+		rec, err := New(path, ModeAuto)
+		if err != nil {
+			t.Fatalf("New() is expected to create the recorder: %+v", err)
+		}
+		if rec.Mode() != ModeReplay {
+			t.Errorf("New() is expected to replay when the cassette exists! Got mode %d.", rec.Mode())
+		}
+
+		api := webflowAPI.New("any token", siteID, rec.Client())
+		api.BaseURL = baseURL
+		items, err := api.GetAllItemsInCollectionBySlug("dogs", 10)
+		if err != nil || len(items) != 1 || !strings.Contains(string(items[0]), "blue") {
+			t.Errorf("Replaying is expected to return the recorded items! Got %s; error %+v.", items, err)
+		}
+
+		_, err = api.GetAllItemsInCollectionByID("unrecorded", 10)
+		if err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+			t.Errorf("Replaying is expected to fail for requests that were not recorded! Got %+v.", err)
+		}
+	}
+}
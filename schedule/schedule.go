@@ -0,0 +1,330 @@
+// Package schedule Publish & expire items at the times given by their date fields. Each pass publishes the items whose
+// publish-at time has passed and archives those whose expire-at time has passed, either once, e.g. from cron, or in a
+// long-lived loop. What was done is recorded in a file, so a restarted scheduler does not act on an item twice.
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// Actions of an Entry.
+	Publish = "publish"
+	Expire  = "expire"
+
+	// DefaultPublishField Date field holding when an item is to be published.
+	DefaultPublishField = "publish-at"
+	// DefaultExpireField Date field holding when an item is to be archived.
+	DefaultExpireField = "expire-at"
+	// DefaultMaxPages Number of additional pages of items requested per collection.
+	DefaultMaxPages = 100
+	// DefaultInterval Time between passes of Run.
+	DefaultInterval = time.Minute
+)
+
+// Entry An action taken, or due, on an item.
+type Entry struct {
+	Action       string `json:"action"`
+	CollectionID string `json:"collectionId"`
+	ItemID       string `json:"itemId"`
+	// Due The time of the item's date field the action was taken for.
+	Due time.Time `json:"due"`
+	// At When the action was taken.
+	At time.Time `json:"at"`
+}
+
+// Failure An action that could not be taken. It is attempted again by the next pass.
+type Failure struct {
+	Entry Entry  `json:"entry"`
+	Error string `json:"error"`
+}
+
+// Report The outcome of a pass.
+type Report struct {
+	Done   []Entry   `json:"done"`
+	Failed []Failure `json:"failed,omitempty"`
+}
+
+// Scheduler Publishes & expires items of a site's collections.
+type Scheduler struct {
+	// PublishField Date field holding when an item is to be published; empty to publish nothing.
+	PublishField string
+	// ExpireField Date field holding when an item is to be archived; empty to archive nothing.
+	ExpireField string
+	// MaxPages Number of additional pages of items to request. See webflowAPI.Interface.GetAllItemsInCollectionByID.
+	MaxPages int
+	// Interval Time between passes of Run.
+	Interval time.Duration
+
+	api  webflowAPI.Interface
+	path string
+	mu   sync.Mutex
+	// done The entries taken, by action, collection & item.
+	done map[string]Entry
+	// Override for the clock. Use only for internal testing of the pkg.
+	now func() time.Time
+}
+
+// New Create a scheduler acting through the given API & recording what it does in the file at path, which is read
+// when it exists.
+func New(api webflowAPI.Interface, path string) (*Scheduler, error) {
+	s := &Scheduler{
+		PublishField: DefaultPublishField,
+		ExpireField:  DefaultExpireField,
+		MaxPages:     DefaultMaxPages,
+		Interval:     DefaultInterval,
+		api:          api,
+		path:         path,
+		done:         map[string]Entry{},
+		now:          time.Now,
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the schedule record; error: %+v", err)
+	}
+
+	entries := []Entry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("unable to decode the schedule record %s; error: %+v", path, err)
+	}
+	for _, entry := range entries {
+		s.done[key(entry)] = entry
+	}
+
+	return s, nil
+}
+
+// Run Make a pass over the collections every Interval until the context is done, handing each pass's report to the
+// report func, which may be nil.
+func (s *Scheduler) Run(ctx context.Context, collectionIDs []string, report func(*Report)) error {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		r, err := s.RunOnce(collectionIDs)
+		if err != nil {
+			return err
+		}
+		if report != nil {
+			report(r)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce Make one pass over the collections. Items whose publish time has passed, and that have not been published
+// since, are un-marked as drafts & published. Items whose expire time has passed are archived, then published unless
+// they are drafts, and are never published by their publish time. The record is saved once per collection acted on.
+// Failed actions are reported and attempted again by the next pass; an error is only returned when the record cannot
+// be saved.
+func (s *Scheduler) RunOnce(collectionIDs []string) (*Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &Report{Done: []Entry{}}
+	now := s.now()
+
+	for _, collectionID := range collectionIDs {
+		items, err := s.api.GetAllItemsInCollectionByID(collectionID, s.MaxPages)
+		if err != nil {
+			report.Failed = append(report.Failed, Failure{
+				Entry: Entry{CollectionID: collectionID},
+				Error: fmt.Sprintf("unable to read the items; error: %+v", err),
+			})
+			continue
+		}
+
+		done := len(report.Done)
+		for _, item := range items {
+			for _, entry := range s.due(collectionID, item, now) {
+				if err := s.take(entry); err != nil {
+					report.Failed = append(report.Failed, Failure{Entry: entry, Error: err.Error()})
+					continue
+				}
+
+				entry.At = s.now()
+				s.done[key(entry)] = entry
+				report.Done = append(report.Done, entry)
+			}
+		}
+
+		if len(report.Done) > done {
+			if err := s.save(); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Done The actions taken, oldest first.
+func (s *Scheduler) Done() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.entries()
+}
+
+// due The actions due on an item.
+func (s *Scheduler) due(collectionID string, item []byte, now time.Time) []Entry {
+	id := gjson.GetBytes(item, "_id").String()
+	expireAt, expires := s.time(item, s.ExpireField)
+	expired := expires && !expireAt.After(now)
+
+	entries := []Entry{}
+
+	if expired {
+		entry := Entry{Action: Expire, CollectionID: collectionID, ItemID: id, Due: expireAt}
+		if !gjson.GetBytes(item, "_archived").Bool() && !s.taken(entry) {
+			entries = append(entries, entry)
+		}
+		return entries
+	}
+
+	publishAt, publishes := s.time(item, s.PublishField)
+	if !publishes || publishAt.After(now) || gjson.GetBytes(item, "_archived").Bool() {
+		return entries
+	}
+
+	// Items published since the publish time, e.g. by hand, are left alone.
+	published, err := time.Parse(time.RFC3339Nano, gjson.GetBytes(item, "published-on").String())
+	if err == nil && !published.Before(publishAt) && !gjson.GetBytes(item, "_draft").Bool() {
+		return entries
+	}
+
+	entry := Entry{Action: Publish, CollectionID: collectionID, ItemID: id, Due: publishAt}
+	if !s.taken(entry) {
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// take Take the action on the item.
+func (s *Scheduler) take(entry Entry) error {
+	switch entry.Action {
+	case Publish:
+		if _, err := webflowAPI.SetDraft(s.api, entry.CollectionID, entry.ItemID, false); err != nil {
+			return fmt.Errorf("unable to un-mark the draft; error: %+v", err)
+		}
+	case Expire:
+		item, err := webflowAPI.ArchiveItem(s.api, entry.CollectionID, entry.ItemID)
+		if err != nil {
+			return fmt.Errorf("unable to archive the item; error: %+v", err)
+		}
+		// Drafts are not on the live site, and cannot be published.
+		if gjson.GetBytes(item, "_draft").Bool() {
+			return nil
+		}
+	}
+
+	res, err := s.api.PublishItems(entry.CollectionID, []string{entry.ItemID})
+	if err != nil {
+		return fmt.Errorf("unable to publish the item; error: %+v", err)
+	}
+	if len(res.Errors) > 0 {
+		return fmt.Errorf("unable to publish the item; error: %s", res.Errors[0])
+	}
+
+	return nil
+}
+
+// taken Whether the action was already taken for the same due time.
+func (s *Scheduler) taken(entry Entry) bool {
+	done, ok := s.done[key(entry)]
+	return ok && done.Due.Equal(entry.Due)
+}
+
+// time The time in the item's date field, and whether it has one.
+func (s *Scheduler) time(item []byte, field string) (time.Time, bool) {
+	if field == "" {
+		return time.Time{}, false
+	}
+
+	value := gjson.GetBytes(item, field).String()
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// save Atomically write the record: write to a temp file, sync it then rename it into place.
+func (s *Scheduler) save() error {
+	data, err := json.MarshalIndent(s.entries(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path))
+	if err != nil {
+		return fmt.Errorf("unable to save the schedule record; error: %+v", err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save the schedule record; error: %+v", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save the schedule record; error: %+v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to save the schedule record; error: %+v", err)
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// entries The recorded entries, oldest first.
+func (s *Scheduler) entries() []Entry {
+	entries := []Entry{}
+	for _, entry := range s.done {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].At.Equal(entries[j].At) {
+			return entries[i].At.Before(entries[j].At)
+		}
+		return key(entries[i]) < key(entries[j])
+	})
+
+	return entries
+}
+
+// key Identifies the action on an item. Only the latest due time is kept, so the record does not grow past twice the
+// number of items.
+func key(entry Entry) string {
+	return entry.Action + "/" + entry.CollectionID + "/" + entry.ItemID
+}
@@ -0,0 +1,124 @@
+package schedule
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/memory"
+	"github.com/tidwall/gjson"
+)
+
+var now = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestScheduler(t *testing.T, store *memory.Store, path string) *Scheduler {
+	s, err := New(store, path)
+	if err != nil {
+		t.Fatalf("Unable to create the scheduler: %+v", err)
+	}
+	s.now = func() time.Time { return now }
+
+	return s
+}
+
+// actions The actions of the entries, as action:item.
+func actions(entries []Entry) []string {
+	got := []string{}
+	for _, entry := range entries {
+		got = append(got, entry.Action+":"+entry.ItemID)
+	}
+
+	return got
+}
+
+func TestRunOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webflow-schedule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "schedule.json")
+
+	store, err := memory.Load("mysiteid", "testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := newTestScheduler(t, store, path).RunOnce([]string{"events1"})
+	if err != nil || len(report.Failed) != 0 {
+		t.Fatalf("Expected a clean pass; got %+v, error %+v", report, err)
+	}
+	expected := []string{"publish:e1", "expire:e3", "expire:e5"}
+	if got := actions(report.Done); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v; got %v", expected, got)
+	}
+
+	live, _ := webflowAPI.ListItems(store, "events1", 0, webflowAPI.Live)
+	slugs := []string{}
+	for _, item := range live {
+		if gjson.GetBytes(item, "published-on").Exists() && gjson.GetBytes(item, "published-on").Type != gjson.Null {
+			slugs = append(slugs, gjson.GetBytes(item, "slug").String())
+		}
+	}
+	if !reflect.DeepEqual(slugs, []string{"due", "live"}) {
+		t.Errorf("Expected due to be published & the expired items archived; got the live items %v", slugs)
+	}
+
+	// A restarted scheduler reads the record rather than acting again, even when the item was changed back by hand.
+	webflowAPI.SetDraft(store, "events1", "e1", true)
+	s := newTestScheduler(t, store, path)
+	// Entries taken at the same time are ordered by action & item.
+	recorded := []string{"expire:e3", "expire:e5", "publish:e1"}
+	if got := actions(s.Done()); !reflect.DeepEqual(got, recorded) {
+		t.Errorf("Expected the record to hold %v; got %v", recorded, got)
+	}
+	if report, _ := s.RunOnce([]string{"events1"}); len(report.Done) != 0 {
+		t.Errorf("Expected nothing left to do; got %v", actions(report.Done))
+	}
+
+	// A new publish time is acted on.
+	store.PatchItem("events1", "e1", map[string]string{"publish-at": "2020-05-20T00:00:00.000Z"}, false)
+	if report, _ := s.RunOnce([]string{"events1"}); !reflect.DeepEqual(actions(report.Done), []string{"publish:e1"}) {
+		t.Errorf("Expected e1 to be published again; got %v", actions(report.Done))
+	}
+
+	if report, _ := s.RunOnce([]string{"missing"}); len(report.Failed) != 1 {
+		t.Errorf("Expected the missing collection to be reported; got %+v", report)
+	}
+}
+
+func TestRun(t *testing.T) {
+	store, err := memory.Load("mysiteid", "testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "webflow-schedule")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := newTestScheduler(t, store, filepath.Join(dir, "schedule.json"))
+	s.Interval = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	passes := 0
+	err = s.Run(ctx, []string{"events1"}, func(report *Report) {
+		passes++
+		if passes == 1 && len(report.Done) != 3 || passes > 1 && len(report.Done) != 0 {
+			t.Errorf("Expected the first pass only to act; pass %d got %v", passes, actions(report.Done))
+		}
+		if passes == 3 {
+			cancel()
+		}
+	})
+	if err != context.Canceled || passes != 3 {
+		t.Errorf("Expected Run to stop when cancelled; got %d passes, error %+v", passes, err)
+	}
+}
@@ -0,0 +1,245 @@
+package schema
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+const (
+	// Kinds of Change.
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+
+	// Formats of Comparison.Render.
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Change A collection or field added or removed, or a property of one changed.
+type Change struct {
+	Kind       string `json:"kind"`
+	Collection string `json:"collection"`
+	// Field Slug of the field; empty for changes to the collection itself.
+	Field string `json:"field,omitempty"`
+	// Property What changed, e.g. type, required or validations.maxLength; empty when added or removed.
+	Property string      `json:"property,omitempty"`
+	Before   interface{} `json:"before,omitempty"`
+	After    interface{} `json:"after,omitempty"`
+}
+
+// Comparison The differences between two schemas.
+type Comparison struct {
+	Changes []Change `json:"changes"`
+}
+
+// Compare Report the collections & fields added, removed or changed from before to after. Collections & fields are
+// matched by slug, and references & options by the referenced collection's slug & the option's name, so the schemas of
+// two sites may be compared despite their different IDs.
+func Compare(before, after []webflowAPI.Collection) *Comparison {
+	cmp := &Comparison{Changes: []Change{}}
+
+	beforeSlugs, afterSlugs := collectionSlugs(before), collectionSlugs(after)
+	beforeBySlug := map[string]*webflowAPI.Collection{}
+	for i := range before {
+		beforeBySlug[before[i].Slug] = &before[i]
+	}
+	afterBySlug := map[string]*webflowAPI.Collection{}
+	for i := range after {
+		afterBySlug[after[i].Slug] = &after[i]
+	}
+
+	for _, collection := range before {
+		if afterBySlug[collection.Slug] == nil {
+			cmp.Changes = append(cmp.Changes, Change{Kind: Removed, Collection: collection.Slug})
+		}
+	}
+
+	for _, collection := range after {
+		old := beforeBySlug[collection.Slug]
+		if old == nil {
+			cmp.Changes = append(cmp.Changes, Change{Kind: Added, Collection: collection.Slug})
+			continue
+		}
+
+		if old.Name != collection.Name {
+			cmp.Changes = append(cmp.Changes, Change{Kind: Changed, Collection: collection.Slug, Property: "name", Before: old.Name, After: collection.Name})
+		}
+
+		oldFields := map[string]webflowAPI.CollectionField{}
+		for _, field := range old.Fields {
+			oldFields[field.Slug] = field
+		}
+		newFields := map[string]bool{}
+		for _, field := range collection.Fields {
+			newFields[field.Slug] = true
+		}
+
+		for _, field := range old.Fields {
+			if !newFields[field.Slug] {
+				cmp.Changes = append(cmp.Changes, Change{Kind: Removed, Collection: collection.Slug, Field: field.Slug, Before: field.Type})
+			}
+		}
+
+		for _, field := range collection.Fields {
+			oldField, ok := oldFields[field.Slug]
+			if !ok {
+				cmp.Changes = append(cmp.Changes, Change{Kind: Added, Collection: collection.Slug, Field: field.Slug, After: field.Type})
+				continue
+			}
+
+			for _, prop := range fieldChanges(oldField, field, beforeSlugs, afterSlugs) {
+				prop.Kind, prop.Collection, prop.Field = Changed, collection.Slug, field.Slug
+				cmp.Changes = append(cmp.Changes, prop)
+			}
+		}
+	}
+
+	return cmp
+}
+
+// Empty Whether the schemas are the same.
+func (cmp *Comparison) Empty() bool {
+	return len(cmp.Changes) == 0
+}
+
+// Count The number of changes of the given kind.
+func (cmp *Comparison) Count(kind string) int {
+	n := 0
+	for _, change := range cmp.Changes {
+		if change.Kind == kind {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Render Write the comparison in the given format: text, + for additions, - for removals & ~ for changes, or json.
+func (cmp *Comparison) Render(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(cmp, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	case FormatText, "":
+	default:
+		return fmt.Errorf("unknown schema diff format '%s'", format)
+	}
+
+	bw := bufio.NewWriter(w)
+	marks := map[string]string{Added: "+", Removed: "-", Changed: "~"}
+
+	for _, change := range cmp.Changes {
+		switch {
+		case change.Field == "" && change.Property == "":
+			fmt.Fprintf(bw, "%s collection %s\n", marks[change.Kind], change.Collection)
+		case change.Field == "":
+			fmt.Fprintf(bw, "~ collection %s: %s %s -> %s\n", change.Collection, change.Property, render(change.Before), render(change.After))
+		case change.Kind == Added:
+			fmt.Fprintf(bw, "+ field %s.%s (%v)\n", change.Collection, change.Field, change.After)
+		case change.Kind == Removed:
+			fmt.Fprintf(bw, "- field %s.%s (%v)\n", change.Collection, change.Field, change.Before)
+		default:
+			fmt.Fprintf(bw, "~ field %s.%s: %s %s -> %s\n", change.Collection, change.Field, change.Property, render(change.Before), render(change.After))
+		}
+	}
+
+	fmt.Fprintf(bw, "Schema: %d added, %d removed, %d changed.\n", cmp.Count(Added), cmp.Count(Removed), cmp.Count(Changed))
+
+	return bw.Flush()
+}
+
+// fieldChanges The properties that differ between two versions of a field.
+func fieldChanges(before, after webflowAPI.CollectionField, beforeSlugs, afterSlugs map[string]string) []Change {
+	changes := []Change{}
+	add := func(prop string, a, b interface{}) {
+		if !reflect.DeepEqual(a, b) {
+			changes = append(changes, Change{Property: prop, Before: a, After: b})
+		}
+	}
+
+	add("name", before.Name, after.Name)
+	add("type", before.Type, after.Type)
+	add("required", before.Required, after.Required)
+
+	a, b := rules(before.Validations, beforeSlugs), rules(after.Validations, afterSlugs)
+	for _, key := range unionKeys(a, b) {
+		add("validations."+key, a[key], b[key])
+	}
+
+	return changes
+}
+
+// rules The validation rules of a field, with the referenced collection by slug & options by name.
+func rules(validations *webflowAPI.FieldValidations, slugs map[string]string) map[string]interface{} {
+	m := map[string]interface{}{}
+	if validations == nil {
+		return m
+	}
+
+	data, _ := json.Marshal(validations)
+	json.Unmarshal(data, &m)
+
+	if validations.CollectionID != "" {
+		delete(m, "collectionId")
+		m["collection"] = validations.CollectionID
+		if slug := slugs[validations.CollectionID]; slug != "" {
+			m["collection"] = slug
+		}
+	}
+	if len(validations.Options) > 0 {
+		names := []interface{}{}
+		for _, option := range validations.Options {
+			names = append(names, option.Name)
+		}
+		m["options"] = names
+	}
+
+	return m
+}
+
+// collectionSlugs Collection slugs by ID.
+func collectionSlugs(collections []webflowAPI.Collection) map[string]string {
+	slugs := map[string]string{}
+	for _, collection := range collections {
+		slugs[collection.ID] = collection.Slug
+	}
+
+	return slugs
+}
+
+// unionKeys The keys of both maps in order.
+func unionKeys(a, b map[string]interface{}) []string {
+	keys := []string{}
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// render A value as JSON, or (none) when missing.
+func render(val interface{}) string {
+	if val == nil {
+		return "(none)"
+	}
+	data, _ := json.Marshal(val)
+
+	return string(data)
+}
@@ -0,0 +1,68 @@
+package schema
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+func TestCompare(t *testing.T) {
+	before, _ := Fetch(newTestStore(t))
+	after, _ := Fetch(newTestStore(t))
+
+	if cmp := Compare(before, after); !cmp.Empty() {
+		t.Errorf("Compare() is expected to find no changes between equal schemas! Got %+v.", cmp.Changes)
+	}
+
+	// Another site has other IDs.
+	for i := range after {
+		after[i].ID += "-prod"
+		for j := range after[i].Fields {
+			after[i].Fields[j].ID += "-prod"
+			if v := after[i].Fields[j].Validations; v != nil && v.CollectionID != "" {
+				v.CollectionID += "-prod"
+			}
+		}
+	}
+	if cmp := Compare(before, after); !cmp.Empty() {
+		t.Errorf("Compare() is expected to match collections, fields & references by slug! Got %+v.", cmp.Changes)
+	}
+
+	posts := &after[1]
+	posts.Name = "Articles"
+	posts.Fields[1].Required = false
+	maxLength := 40
+	posts.Fields[0].Validations.MaxLength = &maxLength
+	posts.Fields[4].Validations.Options[1].Name = "Resolved"
+	posts.Fields = append(posts.Fields[:7], webflowAPI.CollectionField{Slug: "tags", Type: webflowAPI.FieldTypeItemRefSet})
+	after = append(after, webflowAPI.Collection{ID: "tags1", Slug: "tags", Name: "Tags"})
+
+	cmp := Compare(before, after)
+	out := &bytes.Buffer{}
+	if err := cmp.Render(out, FormatText); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		`~ collection posts: name "Posts" -> "Articles"`,
+		`~ field posts.name: validations.maxLength 20 -> 40`,
+		`~ field posts.slug: required true -> false`,
+		`~ field posts.status: validations.options ["Open","Closed"] -> ["Open","Resolved"]`,
+		`- field posts.date (Date)`,
+		`+ field posts.tags (ItemRefSet)`,
+		`+ collection tags`,
+		`Schema: 2 added, 1 removed, 4 changed.`,
+	} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Render() is expected to print %q! Got:\n%s", line, out)
+		}
+	}
+
+	out.Reset()
+	cmp.Render(out, FormatJSON)
+	if !strings.Contains(out.String(), `"property": "required"`) || !strings.Contains(out.String(), `"before": true`) {
+		t.Errorf("Render() is expected to print JSON changes! Got %s.", out)
+	}
+}
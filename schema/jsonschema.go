@@ -0,0 +1,255 @@
+package schema
+
+import (
+	"math"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+// JSONSchemaDraft The JSON Schema dialect of the documents.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// Document A JSON Schema, or subschema.
+type Document struct {
+	Schema      string `json:"$schema,omitempty"`
+	ID          string `json:"$id,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Type A type name, or a list of them.
+	Type                 interface{}          `json:"type,omitempty"`
+	Format               string               `json:"format,omitempty"`
+	ContentMediaType     string               `json:"contentMediaType,omitempty"`
+	Pattern              string               `json:"pattern,omitempty"`
+	MinLength            *int                 `json:"minLength,omitempty"`
+	MaxLength            *int                 `json:"maxLength,omitempty"`
+	Minimum              *float64             `json:"minimum,omitempty"`
+	Maximum              *float64             `json:"maximum,omitempty"`
+	MultipleOf           *float64             `json:"multipleOf,omitempty"`
+	Enum                 []interface{}        `json:"enum,omitempty"`
+	Items                *Document            `json:"items,omitempty"`
+	Properties           map[string]*Document `json:"properties,omitempty"`
+	Required             []string             `json:"required,omitempty"`
+	AdditionalProperties *bool                `json:"additionalProperties,omitempty"`
+	AnyOf                []*Document          `json:"anyOf,omitempty"`
+	ReadOnly             bool                 `json:"readOnly,omitempty"`
+	Defs                 map[string]*Document `json:"$defs,omitempty"`
+}
+
+// JSONSchema A JSON Schema (draft 2020-12) of the collection's items, with the $id <slug>.json. Reference fields $ref
+// the itemId definition of the referenced collection's schema, which is named by ID; see JSONSchemas to name them by
+// slug.
+func JSONSchema(collection *webflowAPI.Collection) *Document {
+	return jsonSchema(collection, map[string]string{collection.ID: collection.Slug})
+}
+
+// JSONSchemas A compound JSON Schema holding the schema of each collection in $defs, by slug, so references between
+// the collections resolve within the document.
+func JSONSchemas(collections []webflowAPI.Collection) *Document {
+	slugs := map[string]string{}
+	for _, collection := range collections {
+		slugs[collection.ID] = collection.Slug
+	}
+
+	doc := &Document{Schema: JSONSchemaDraft, Defs: map[string]*Document{}}
+	for i := range collections {
+		doc.Defs[collections[i].Slug] = jsonSchema(&collections[i], slugs)
+	}
+
+	return doc
+}
+
+// jsonSchema The schema of the collection, naming the schemas of the collections by slug, when known, else by ID.
+func jsonSchema(collection *webflowAPI.Collection, slugs map[string]string) *Document {
+	no := false
+
+	doc := &Document{
+		Schema:               JSONSchemaDraft,
+		ID:                   schemaID(collection.ID, slugs),
+		Title:                collection.Name,
+		Type:                 "object",
+		Properties:           map[string]*Document{},
+		Required:             []string{"name", "slug"},
+		AdditionalProperties: &no,
+		Defs: map[string]*Document{
+			"itemId": {Description: "ID of an item of the collection.", Type: "string", MinLength: intPtr(1)},
+			"image": {
+				Description: "An image, by URL or uploaded file.",
+				AnyOf: []*Document{
+					{Type: "string", Format: "uri"},
+					fileObject(&Document{Type: "string"}),
+				},
+			},
+			"file": {
+				Description: "A file, by URL or uploaded file.",
+				AnyOf:       []*Document{{Type: "string", Format: "uri"}, fileObject(nil)},
+			},
+		},
+	}
+
+	// Metadata may be given with the fields; that maintained by Webflow is read only.
+	doc.Properties["_id"] = &Document{Ref: "#/$defs/itemId", ReadOnly: true}
+	doc.Properties["_cid"] = &Document{Type: "string", ReadOnly: true}
+	doc.Properties["_archived"] = &Document{Type: "boolean"}
+	doc.Properties["_draft"] = &Document{Type: "boolean"}
+	for _, slug := range []string{"created-on", "updated-on", "published-on"} {
+		doc.Properties[slug] = &Document{Type: []string{"string", "null"}, Format: "date-time", ReadOnly: true}
+	}
+	for _, slug := range []string{"created-by", "updated-by", "published-by"} {
+		doc.Properties[slug] = &Document{Type: []string{"string", "null"}, ReadOnly: true}
+	}
+
+	doc.Properties["name"] = &Document{Type: "string", MinLength: intPtr(1)}
+	doc.Properties["slug"] = &Document{Type: "string", Pattern: slugPattern.String()}
+
+	for _, field := range collection.Fields {
+		if writableMetadata[field.Slug] || readOnlyMetadata[field.Slug] {
+			continue
+		}
+		prop := fieldSchema(field, slugs)
+		if field.Slug == "slug" {
+			prop.Pattern = slugPattern.String()
+		}
+
+		if field.Required {
+			if field.Slug != "name" && field.Slug != "slug" {
+				doc.Required = append(doc.Required, field.Slug)
+			}
+		} else {
+			prop = nullable(prop)
+		}
+		doc.Properties[field.Slug] = prop
+	}
+
+	return doc
+}
+
+// fieldSchema The schema of a field's value.
+func fieldSchema(field webflowAPI.CollectionField, slugs map[string]string) *Document {
+	rules := field.Validations
+	if rules == nil {
+		rules = &webflowAPI.FieldValidations{}
+	}
+	doc := &Document{Title: field.Name, Description: field.HelpText}
+
+	switch field.Type {
+	case webflowAPI.FieldTypePlainText:
+		doc.Type = "string"
+		doc.MinLength, doc.MaxLength = rules.MinLength, rules.MaxLength
+		if rules.SingleLine != nil && *rules.SingleLine {
+			doc.Pattern = `^[^\r\n]*$`
+		}
+	case webflowAPI.FieldTypeRichText:
+		doc.Type = "string"
+		doc.ContentMediaType = "text/html"
+		doc.MinLength, doc.MaxLength = rules.MinLength, rules.MaxLength
+	case webflowAPI.FieldTypeLink:
+		doc.Type, doc.Format = "string", "uri"
+	case webflowAPI.FieldTypeEmail:
+		doc.Type, doc.Format = "string", "email"
+	case webflowAPI.FieldTypeDate:
+		doc.Type, doc.Format = "string", "date-time"
+	case webflowAPI.FieldTypeBool:
+		doc.Type = "boolean"
+	case webflowAPI.FieldTypeNumber:
+		doc.Type = "number"
+		doc.Minimum, doc.Maximum = rules.Min, rules.Max
+		if rules.AllowNegative != nil && !*rules.AllowNegative && (doc.Minimum == nil || *doc.Minimum < 0) {
+			doc.Minimum = float64Ptr(0)
+		}
+		if rules.DecimalPlaces != nil {
+			if *rules.DecimalPlaces == 0 {
+				doc.Type = "integer"
+			} else {
+				doc.MultipleOf = float64Ptr(math.Pow(10, -float64(*rules.DecimalPlaces)))
+			}
+		}
+	case webflowAPI.FieldTypeOption:
+		doc.Type = "string"
+		// Without options in the schema, an empty enum would allow no value, so any string is allowed.
+		if len(rules.Options) > 0 {
+			doc.Enum = []interface{}{}
+			for _, option := range rules.Options {
+				doc.Enum = append(doc.Enum, option.ID)
+			}
+		}
+	case webflowAPI.FieldTypeItemRef:
+		doc.Ref = itemIDRef(rules.CollectionID, slugs)
+	case webflowAPI.FieldTypeItemRefSet:
+		doc.Type = "array"
+		doc.Items = &Document{Ref: itemIDRef(rules.CollectionID, slugs)}
+	case webflowAPI.FieldTypeImageRef:
+		doc.Ref = "#/$defs/image"
+	case webflowAPI.FieldTypeExtFileRef:
+		doc.Ref = "#/$defs/file"
+	case webflowAPI.FieldTypeSet:
+		doc.Type = "array"
+		doc.Items = &Document{Ref: "#/$defs/image"}
+	default:
+		// Color, Phone, Video & User, along with types added later, are strings.
+		doc.Type = "string"
+	}
+
+	return doc
+}
+
+// nullable Allow null as well as the value.
+func nullable(doc *Document) *Document {
+	if name, ok := doc.Type.(string); ok {
+		doc.Type = []string{name, "null"}
+		if doc.Enum != nil {
+			doc.Enum = append(doc.Enum, nil)
+		}
+		return doc
+	}
+
+	// References are wrapped, keeping the title & description outside.
+	wrapped := &Document{Title: doc.Title, Description: doc.Description}
+	doc.Title, doc.Description = "", ""
+	wrapped.AnyOf = []*Document{doc, {Type: "null"}}
+
+	return wrapped
+}
+
+// fileObject An uploaded file's object, with extra properties.
+func fileObject(alt *Document) *Document {
+	doc := &Document{
+		Type: "object",
+		Properties: map[string]*Document{
+			"fileId": {Type: "string"},
+			"url":    {Type: "string", Format: "uri"},
+		},
+		AnyOf: []*Document{{Required: []string{"url"}}, {Required: []string{"fileId"}}},
+	}
+	if alt != nil {
+		doc.Properties["alt"] = alt
+	}
+
+	return doc
+}
+
+// schemaID The $id of a collection's schema.
+func schemaID(collectionID string, slugs map[string]string) string {
+	if slug := slugs[collectionID]; slug != "" {
+		return slug + ".json"
+	}
+
+	return collectionID + ".json"
+}
+
+// itemIDRef The $ref of the item IDs of a collection.
+func itemIDRef(collectionID string, slugs map[string]string) string {
+	if collectionID == "" {
+		return "#/$defs/itemId"
+	}
+
+	return schemaID(collectionID, slugs) + "#/$defs/itemId"
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func float64Ptr(n float64) *float64 {
+	return &n
+}
@@ -0,0 +1,59 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/tidwall/gjson"
+)
+
+func TestJSONSchema(t *testing.T) {
+	store := newTestStore(t)
+	posts, _ := store.GetCollectionByID("posts1")
+
+	data, err := json.Marshal(JSONSchema(posts))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := gjson.ParseBytes(data)
+
+	for path, want := range map[string]string{
+		"$schema":                                   JSONSchemaDraft,
+		"$id":                                       "posts.json",
+		"required":                                  `["name","slug","summary"]`,
+		"additionalProperties":                      "false",
+		"properties.name.maxLength":                 "20",
+		"properties.name.pattern":                   `^[^\r\n]*$`,
+		"properties.slug.pattern":                   slugPattern.String(),
+		"properties.rating.type":                    `["number","null"]`,
+		"properties.rating.maximum":                 "5",
+		"properties.rating.multipleOf":              "0.1",
+		"properties.status.enum":                    `["o1","o2",null]`,
+		"properties.date.format":                    "date-time",
+		"properties.author.anyOf.0.$ref":            "authors1.json#/$defs/itemId",
+		"properties.author.title":                   "Author",
+		"properties._id.readOnly":                   "true",
+		"$defs.image.anyOf.1.properties.url.format": "uri",
+	} {
+		if got := doc.Get(path).String(); got != want {
+			t.Errorf("JSONSchema() is expected to have %s = %s! Got %s.", path, want, got)
+		}
+	}
+
+	// Option fields without options in the schema allow any string rather than no value.
+	options := &webflowAPI.Collection{Slug: "options", Fields: []webflowAPI.CollectionField{{Slug: "status", Type: webflowAPI.FieldTypeOption}}}
+	data, _ = json.Marshal(JSONSchema(options))
+	if status := gjson.GetBytes(data, "properties.status"); status.Get("enum").Exists() || status.Get("type").String() != `["string","null"]` {
+		t.Errorf("JSONSchema() is expected to leave out the enum of a field without options! Got %s.", status)
+	}
+
+	collections, _ := Fetch(store)
+	data, _ = json.Marshal(JSONSchemas(collections))
+	if got := gjson.GetBytes(data, "$defs.posts.properties.author.anyOf.0.$ref").String(); got != "authors.json#/$defs/itemId" {
+		t.Errorf("JSONSchemas() is expected to name the referenced schemas by slug! Got %s.", got)
+	}
+	if got := gjson.GetBytes(data, "$defs.authors.$id").String(); got != "authors.json" {
+		t.Errorf("JSONSchemas() is expected to hold each collection's schema! Got %s.", got)
+	}
+}
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/redeemed2011/webflowAPI"
+)
+
+// Fetch Read every collection of the site with its fields.
+func Fetch(api webflowAPI.Interface) ([]webflowAPI.Collection, error) {
+	list, err := api.GetAllCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []webflowAPI.Collection{}
+	if list == nil {
+		return collections, nil
+	}
+
+	for _, entry := range *list {
+		collection, err := api.GetCollectionByID(entry.ID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read collection '%s'; error: %+v", entry.Slug, err)
+		}
+		if collection != nil {
+			collections = append(collections, *collection)
+		}
+	}
+
+	return collections, nil
+}
+
+// Load Read a saved schema file: a JSON list of collections, as written by Write, or a single collection, such as a
+// backup's collections/<ID>.json.
+func Load(path string) ([]webflowAPI.Collection, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	collections := []webflowAPI.Collection{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		collection := webflowAPI.Collection{}
+		err = json.Unmarshal(data, &collection)
+		collections = append(collections, collection)
+	} else {
+		err = json.Unmarshal(data, &collections)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the schema file %s; error: %+v", path, err)
+	}
+
+	return collections, nil
+}
+
+// Write Save the collections as an indented JSON list, to be read by Load.
+func Write(w io.Writer, collections []webflowAPI.Collection) error {
+	data, err := json.MarshalIndent(collections, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
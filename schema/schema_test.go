@@ -0,0 +1,39 @@
+package schema
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFetchLoad(t *testing.T) {
+	collections, err := Fetch(newTestStore(t))
+	if err != nil || len(collections) != 2 || len(collections[1].Fields) == 0 {
+		t.Fatalf("Fetch() is expected to read the collections with their fields! Got %+v, %+v.", collections, err)
+	}
+
+	dir, err := ioutil.TempDir("", "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	buf := &bytes.Buffer{}
+	if err := Write(buf, collections); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "schema.json")
+	ioutil.WriteFile(path, buf.Bytes(), 0644)
+
+	loaded, err := Load(path)
+	if err != nil || len(loaded) != 2 || loaded[1].Fields[2].Slug != "summary" {
+		t.Errorf("Load() is expected to read the saved schema! Got %+v, %+v.", loaded, err)
+	}
+
+	ioutil.WriteFile(path, []byte(`{"_id":"posts1","slug":"posts"}`), 0644)
+	if loaded, err := Load(path); err != nil || len(loaded) != 1 || loaded[0].ID != "posts1" {
+		t.Errorf("Load() is expected to read a single collection! Got %+v, %+v.", loaded, err)
+	}
+}
@@ -0,0 +1,531 @@
+// Package schema Work with collection schemas: read them from a site or a saved schema file, and validate item fields
+// against a collection's field definitions before they are written.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/internal/object"
+)
+
+const (
+	// Codes of FieldError.
+	CodeRequired  = "required"
+	CodeUnknown   = "unknown"
+	CodeType      = "type"
+	CodeMinLength = "minLength"
+	CodeMaxLength = "maxLength"
+	CodeSingle    = "singleLine"
+	CodeMin       = "min"
+	CodeMax       = "max"
+	CodeDecimals  = "decimalPlaces"
+	CodeNegative  = "negative"
+	CodeOption    = "option"
+	CodeReference = "reference"
+	CodeFormat    = "format"
+
+	// DefaultMaxPages Number of additional pages of items requested when checking references.
+	DefaultMaxPages = 100
+)
+
+var (
+	// Slugs are lowercase words of letters & digits joined by hyphens.
+	slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+	// Item metadata that may be written along with the fields.
+	writableMetadata = map[string]bool{"_archived": true, "_draft": true}
+	// Item metadata maintained by Webflow, which is ignored when given.
+	readOnlyMetadata = map[string]bool{
+		"_id": true, "_cid": true,
+		"created-on": true, "created-by": true,
+		"updated-on": true, "updated-by": true,
+		"published-on": true, "published-by": true,
+	}
+)
+
+// FieldError A problem with one field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// FieldErrors The problems with an item's fields. As an error it lists them all.
+type FieldErrors []FieldError
+
+func (errs FieldErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+
+	return "ValidationError: " + strings.Join(msgs, "; ")
+}
+
+// Validate Check item fields against the collection's field definitions without checking that references exist.
+// When patch is set, only the given fields are checked; otherwise required fields must also be present.
+func Validate(collection *webflowAPI.Collection, fields interface{}, patch bool) (FieldErrors, error) {
+	return validate(collection, fields, patch, nil)
+}
+
+// Validator Validates item fields, reading collection schemas & checking references through the API. Schemas & the
+// IDs of referenced collections are requested once. Safe for concurrent use.
+type Validator struct {
+	// MaxPages Number of additional pages of items to request. See webflowAPI.Interface.GetAllItemsInCollectionByID.
+	MaxPages int
+
+	api     webflowAPI.Interface
+	mu      sync.Mutex
+	schemas map[string]*webflowAPI.Collection
+	ids     map[string]map[string]bool
+}
+
+// NewValidator Create a validator reading schemas & items through the given API.
+func NewValidator(api webflowAPI.Interface) *Validator {
+	return &Validator{
+		MaxPages: DefaultMaxPages,
+		api:      api,
+		schemas:  map[string]*webflowAPI.Collection{},
+		ids:      map[string]map[string]bool{},
+	}
+}
+
+// Validate Check item fields against the collection's field definitions, including that referenced items exist.
+// See Validate.
+func (v *Validator) Validate(collectionID string, fields interface{}, patch bool) (FieldErrors, error) {
+	collection, err := v.Schema(collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return validate(collection, fields, patch, v.exists)
+}
+
+// Schema The collection with its fields, requested on first use.
+func (v *Validator) Schema(collectionID string) (*webflowAPI.Collection, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if collection, ok := v.schemas[collectionID]; ok {
+		return collection, nil
+	}
+
+	collection, err := v.api.GetCollectionByID(collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if collection == nil {
+		return nil, fmt.Errorf("collection '%s' not found", collectionID)
+	}
+	v.schemas[collectionID] = collection
+
+	return collection, nil
+}
+
+// exists Whether the collection has an item with the ID, requesting the collection's items on first use.
+func (v *Validator) exists(collectionID, itemID string) (bool, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ids, ok := v.ids[collectionID]
+	if !ok {
+		items, err := v.api.GetAllItemsInCollectionByID(collectionID, v.MaxPages)
+		if err != nil {
+			return false, err
+		}
+
+		ids = map[string]bool{}
+		for _, raw := range items {
+			item := struct {
+				ID string `json:"_id"`
+			}{}
+			if err := json.Unmarshal(raw, &item); err == nil {
+				ids[item.ID] = true
+			}
+		}
+		v.ids[collectionID] = ids
+	}
+
+	return ids[itemID], nil
+}
+
+// record Note that an item was created or deleted, once the collection's item IDs have been requested.
+func (v *Validator) record(collectionID, itemID string, exists bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ids, ok := v.ids[collectionID]
+	if !ok {
+		return
+	}
+	if exists {
+		ids[itemID] = true
+	} else {
+		delete(ids, itemID)
+	}
+}
+
+// Client Validates item fields before they are written, returning FieldErrors rather than making the request.
+type Client struct {
+	webflowAPI.Interface
+
+	validator *Validator
+}
+
+// Ensure the client can stand in for the real one.
+var _ webflowAPI.Interface = &Client{}
+
+// NewClient Wrap a client so creates, updates & patches are validated first.
+func NewClient(api webflowAPI.Interface) *Client {
+	return &Client{Interface: api, validator: NewValidator(api)}
+}
+
+// CreateItem Validate the fields, then create the item. Later writes through the client may reference the item.
+func (c *Client) CreateItem(collectionID string, fields interface{}, live bool) ([]byte, error) {
+	if err := c.check(collectionID, fields, false); err != nil {
+		return nil, err
+	}
+
+	item, err := c.Interface.CreateItem(collectionID, fields, live)
+	if err != nil {
+		return nil, err
+	}
+	c.validator.record(collectionID, object.ID(item), true)
+
+	return item, nil
+}
+
+// UpdateItem Validate the fields, then replace the item's fields.
+func (c *Client) UpdateItem(collectionID, itemID string, fields interface{}, live bool) ([]byte, error) {
+	if err := c.check(collectionID, fields, false); err != nil {
+		return nil, err
+	}
+
+	return c.Interface.UpdateItem(collectionID, itemID, fields, live)
+}
+
+// PatchItem Validate the given fields, then update them.
+func (c *Client) PatchItem(collectionID, itemID string, fields interface{}, live bool) ([]byte, error) {
+	if err := c.check(collectionID, fields, true); err != nil {
+		return nil, err
+	}
+
+	return c.Interface.PatchItem(collectionID, itemID, fields, live)
+}
+
+// DeleteItem Delete the item. Later writes through the client may no longer reference it.
+func (c *Client) DeleteItem(collectionID, itemID string) error {
+	if err := c.Interface.DeleteItem(collectionID, itemID); err != nil {
+		return err
+	}
+	c.validator.record(collectionID, itemID, false)
+
+	return nil
+}
+
+// check Validate, returning the field errors as the error.
+func (c *Client) check(collectionID string, fields interface{}, patch bool) error {
+	errs, err := c.validator.Validate(collectionID, fields, patch)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validate Check the fields, using exists, when not nil, to check references.
+func validate(collection *webflowAPI.Collection, fields interface{}, patch bool, exists func(collectionID, itemID string) (bool, error)) (FieldErrors, error) {
+	item, err := toObject(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := map[string]*webflowAPI.CollectionField{}
+	for i := range collection.Fields {
+		defs[collection.Fields[i].Slug] = &collection.Fields[i]
+	}
+
+	errs := FieldErrors{}
+
+	if !patch {
+		required := []string{"name", "slug"}
+		for _, field := range collection.Fields {
+			if field.Required && field.Slug != "name" && field.Slug != "slug" {
+				required = append(required, field.Slug)
+			}
+		}
+		for _, slug := range required {
+			if isEmpty(item[slug]) {
+				errs = append(errs, FieldError{slug, CodeRequired, "is required"})
+			}
+		}
+	}
+
+	for _, slug := range object.SortedKeys(item) {
+		val := item[slug]
+		field, ok := defs[slug]
+
+		switch {
+		case readOnlyMetadata[slug]:
+			continue
+		case writableMetadata[slug] && !ok:
+			if _, isBool := val.(bool); !isBool {
+				errs = append(errs, FieldError{slug, CodeType, "must be true or false"})
+			}
+			continue
+		case !ok:
+			// Name & slug are part of every collection, even when the fields are not listed.
+			if slug != "name" && slug != "slug" {
+				errs = append(errs, FieldError{slug, CodeUnknown, "is not a field of the collection"})
+				continue
+			}
+			field = &webflowAPI.CollectionField{Slug: slug, Type: webflowAPI.FieldTypePlainText}
+		}
+
+		if val == nil {
+			continue
+		}
+
+		fieldErrs, err := checkField(field, val, exists)
+		if err != nil {
+			return nil, err
+		}
+		errs = append(errs, fieldErrs...)
+	}
+
+	if slug, ok := item["slug"].(string); ok && slug != "" && !slugPattern.MatchString(slug) {
+		errs = append(errs, FieldError{"slug", CodeFormat, "must be lowercase letters, digits & single hyphens"})
+	}
+
+	return errs, nil
+}
+
+// checkField Check a non-null value against its field definition.
+func checkField(field *webflowAPI.CollectionField, val interface{}, exists func(collectionID, itemID string) (bool, error)) (FieldErrors, error) {
+	errs := FieldErrors{}
+	add := func(code, format string, args ...interface{}) {
+		errs = append(errs, FieldError{field.Slug, code, fmt.Sprintf(format, args...)})
+	}
+	rules := field.Validations
+	if rules == nil {
+		rules = &webflowAPI.FieldValidations{}
+	}
+
+	switch field.Type {
+	case webflowAPI.FieldTypePlainText, webflowAPI.FieldTypeRichText, webflowAPI.FieldTypeColor, webflowAPI.FieldTypePhone:
+		s, ok := val.(string)
+		if !ok {
+			add(CodeType, "must be text")
+			break
+		}
+		n := utf8.RuneCountInString(s)
+		if rules.MinLength != nil && n < *rules.MinLength {
+			add(CodeMinLength, "must be at least %d characters; got %d", *rules.MinLength, n)
+		}
+		if rules.MaxLength != nil && n > *rules.MaxLength {
+			add(CodeMaxLength, "must be at most %d characters; got %d", *rules.MaxLength, n)
+		}
+		if rules.SingleLine != nil && *rules.SingleLine && strings.ContainsAny(s, "\r\n") {
+			add(CodeSingle, "must be a single line")
+		}
+
+	case webflowAPI.FieldTypeEmail:
+		if s, ok := val.(string); !ok {
+			add(CodeType, "must be text")
+		} else if _, err := mail.ParseAddress(s); err != nil && s != "" {
+			add(CodeFormat, "'%s' is not an email address", s)
+		}
+
+	case webflowAPI.FieldTypeLink:
+		if s, ok := val.(string); !ok {
+			add(CodeType, "must be text")
+		} else if u, err := url.Parse(s); s != "" && (err != nil || u.Scheme == "" || u.Host == "") {
+			add(CodeFormat, "'%s' is not an absolute URL", s)
+		}
+
+	case webflowAPI.FieldTypeNumber:
+		n, ok := toFloat(val)
+		if !ok {
+			add(CodeType, "must be a number")
+			break
+		}
+		if rules.Min != nil && n < *rules.Min {
+			add(CodeMin, "must be at least %v; got %v", *rules.Min, n)
+		}
+		if rules.Max != nil && n > *rules.Max {
+			add(CodeMax, "must be at most %v; got %v", *rules.Max, n)
+		}
+		if rules.AllowNegative != nil && !*rules.AllowNegative && n < 0 {
+			add(CodeNegative, "must not be negative; got %v", n)
+		}
+		if rules.DecimalPlaces != nil {
+			scale := math.Pow(10, float64(*rules.DecimalPlaces))
+			if math.Abs(n*scale-math.Round(n*scale)) > 1e-9 {
+				add(CodeDecimals, "must have at most %d decimal places; got %v", *rules.DecimalPlaces, n)
+			}
+		}
+
+	case webflowAPI.FieldTypeBool:
+		if _, ok := val.(bool); !ok {
+			add(CodeType, "must be true or false")
+		}
+
+	case webflowAPI.FieldTypeDate:
+		if s, ok := val.(string); !ok {
+			add(CodeType, "must be a date")
+		} else if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			add(CodeFormat, "'%s' is not an ISO 8601 date", s)
+		}
+
+	case webflowAPI.FieldTypeOption:
+		s, ok := val.(string)
+		if !ok {
+			add(CodeType, "must be an option ID")
+			break
+		}
+		// Without options in the schema, any option ID is accepted.
+		found := len(rules.Options) == 0
+		for _, option := range rules.Options {
+			found = found || option.ID == s
+		}
+		if !found {
+			add(CodeOption, "'%s' is not an option of the field", s)
+		}
+
+	case webflowAPI.FieldTypeItemRef, webflowAPI.FieldTypeItemRefSet:
+		ids := []string{}
+		switch v := val.(type) {
+		case string:
+			if field.Type == webflowAPI.FieldTypeItemRefSet {
+				add(CodeType, "must be a list of item IDs")
+				return errs, nil
+			}
+			ids = append(ids, v)
+		case []interface{}:
+			if field.Type == webflowAPI.FieldTypeItemRef {
+				add(CodeType, "must be an item ID")
+				return errs, nil
+			}
+			for _, id := range v {
+				s, ok := id.(string)
+				if !ok {
+					add(CodeType, "must be a list of item IDs")
+					return errs, nil
+				}
+				ids = append(ids, s)
+			}
+		default:
+			add(CodeType, "must be an item ID")
+			return errs, nil
+		}
+
+		if exists == nil || rules.CollectionID == "" {
+			break
+		}
+		for _, id := range ids {
+			ok, err := exists(rules.CollectionID, id)
+			if err != nil {
+				return nil, fmt.Errorf("unable to check the items referenced by field '%s'; error: %+v", field.Slug, err)
+			}
+			if !ok {
+				add(CodeReference, "no item '%s' in the referenced collection", id)
+			}
+		}
+
+	case webflowAPI.FieldTypeImageRef, webflowAPI.FieldTypeExtFileRef:
+		if !isFile(val) {
+			add(CodeType, "must be a URL or an object with a url or fileId")
+		}
+
+	case webflowAPI.FieldTypeVideo, webflowAPI.FieldTypeUser:
+		if _, ok := val.(string); !ok {
+			add(CodeType, "must be text")
+		}
+
+	case webflowAPI.FieldTypeSet:
+		files, ok := val.([]interface{})
+		if !ok {
+			add(CodeType, "must be a list of files")
+			break
+		}
+		for _, file := range files {
+			if !isFile(file) {
+				add(CodeType, "must be a list of URLs or objects with a url or fileId")
+				break
+			}
+		}
+	}
+
+	return errs, nil
+}
+
+// isFile Whether the value can refer to an image or file: a URL or an object with a url or fileId.
+func isFile(val interface{}) bool {
+	switch v := val.(type) {
+	case string:
+		return v != ""
+	case map[string]interface{}:
+		return v["url"] != nil || v["fileId"] != nil
+	}
+
+	return false
+}
+
+// isEmpty Whether a value is missing for the purposes of a required field.
+func isEmpty(val interface{}) bool {
+	switch v := val.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	}
+
+	return false
+}
+
+// toFloat The value of a JSON number.
+func toFloat(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case json.Number:
+		n, err := v.Float64()
+		return n, err == nil
+	case float64:
+		return v, true
+	}
+
+	return 0, false
+}
+
+// toObject Encode fields as JSON and decode them as an object, keeping numbers exactly as they were given.
+func toObject(fields interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode the item fields; error: %+v", err)
+	}
+
+	obj, err := object.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("item fields must be a JSON object; error: %+v", err)
+	}
+
+	return obj, nil
+}
@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/memory"
+	"github.com/tidwall/gjson"
+)
+
+func newTestStore(t *testing.T) *memory.Store {
+	store, err := memory.Load("mysiteid", "testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+// codes The field & code of each error, e.g. "rating:max".
+func codes(errs FieldErrors) string {
+	list := []string{}
+	for _, err := range errs {
+		list = append(list, err.Field+":"+err.Code)
+	}
+
+	return strings.Join(list, ",")
+}
+
+func TestValidate(t *testing.T) {
+	store := newTestStore(t)
+	posts, _ := store.GetCollectionByID("posts1")
+
+	tests := []struct {
+		name   string
+		fields map[string]interface{}
+		patch  bool
+		want   string
+	}{
+		{"valid", map[string]interface{}{"name": "Hello", "slug": "hello-world", "summary": "Hi", "rating": 4.5, "status": "o1", "featured": true, "date": "2020-01-02T03:04:05.000Z", "_draft": false}, false, ""},
+		{"required", map[string]interface{}{"name": "Hello", "summary": " "}, false, "slug:required,summary:required"},
+		{"patch", map[string]interface{}{"rating": 3}, true, ""},
+		{"unknown", map[string]interface{}{"colour": "red"}, true, "colour:unknown"},
+		{"metadata", map[string]interface{}{"_id": "x", "_archived": "no"}, true, "_archived:type"},
+		{"length", map[string]interface{}{"name": "A title that is far too long\nfor the field"}, true, "name:maxLength,name:singleLine"},
+		{"number", map[string]interface{}{"rating": 5.25}, true, "rating:max,rating:decimalPlaces"},
+		{"type", map[string]interface{}{"rating": "4", "featured": "yes", "date": "tomorrow"}, true, "date:format,featured:type,rating:type"},
+		{"option", map[string]interface{}{"status": "Open"}, true, "status:option"},
+		{"slug", map[string]interface{}{"slug": "Hello World"}, true, "slug:format"},
+	}
+
+	for _, test := range tests {
+		errs, err := Validate(posts, test.fields, test.patch)
+		if err != nil {
+			t.Fatalf("Validate(%s) is expected to check the fields: %+v", test.name, err)
+		}
+		if got := codes(errs); got != test.want {
+			t.Errorf("Validate(%s) is expected to return errors %q! Got %q.", test.name, test.want, got)
+		}
+	}
+	// Option fields without options in the schema cannot be checked.
+	options := &webflowAPI.Collection{Fields: []webflowAPI.CollectionField{{Slug: "status", Type: webflowAPI.FieldTypeOption}}}
+	if errs, err := Validate(options, map[string]interface{}{"status": "o9"}, true); err != nil || len(errs) > 0 {
+		t.Errorf("Validate() is expected to accept any option when the field has none! Got %v; error %+v.", errs, err)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	v := NewValidator(newTestStore(t))
+
+	errs, err := v.Validate("posts1", map[string]interface{}{"author": "a1"}, true)
+	if err != nil || len(errs) > 0 {
+		t.Errorf("Validate() is expected to accept references to existing items! Got %v, %+v.", errs, err)
+	}
+
+	errs, _ = v.Validate("posts1", map[string]interface{}{"author": "gone"}, true)
+	if codes(errs) != "author:reference" {
+		t.Errorf("Validate() is expected to reject references to missing items! Got %v.", errs)
+	}
+
+	if _, err := v.Validate("nope", map[string]interface{}{}, true); err == nil {
+		t.Error("Validate() is expected to fail for an unknown collection!")
+	}
+}
+
+func TestClient(t *testing.T) {
+	store := newTestStore(t)
+	client := NewClient(store)
+
+	_, err := client.CreateItem("posts1", map[string]interface{}{"name": "Hello", "slug": "hello", "rating": 9}, false)
+	errs, ok := err.(FieldErrors)
+	if !ok || codes(errs) != "summary:required,rating:max" {
+		t.Fatalf("CreateItem() is expected to return the field errors! Got %+v.", err)
+	}
+	if items, _ := store.GetAllItemsInCollectionByID("posts1", 0); len(items) != 0 {
+		t.Error("CreateItem() is expected not to write invalid items!")
+	}
+
+	if _, err := client.CreateItem("posts1", map[string]interface{}{"name": "Hello", "slug": "hello", "summary": "Hi", "_archived": false, "_draft": false}, false); err != nil {
+		t.Errorf("CreateItem() is expected to write valid items: %+v", err)
+	}
+
+	// Items created & deleted through the client are known to later writes, even once the IDs have been requested.
+	client.validator.Validate("posts1", map[string]interface{}{"author": "a1"}, true)
+	author, err := client.CreateItem("authors1", map[string]interface{}{"name": "Grace", "slug": "grace"}, false)
+	if err != nil {
+		t.Fatalf("CreateItem() is expected to write the author: %+v", err)
+	}
+	authorID := gjson.GetBytes(author, "_id").String()
+	post := map[string]interface{}{"name": "By Grace", "slug": "by-grace", "summary": "Hi", "author": authorID}
+	if _, err := client.CreateItem("posts1", post, false); err != nil {
+		t.Errorf("CreateItem() is expected to accept a reference to an item it created: %+v", err)
+	}
+	if err := client.DeleteItem("authors1", authorID); err != nil {
+		t.Fatal(err)
+	}
+	post["slug"] = "by-grace-again"
+	_, err = client.CreateItem("posts1", post, false)
+	if errs, ok := err.(FieldErrors); !ok || codes(errs) != "author:reference" {
+		t.Errorf("CreateItem() is expected to reject a reference to an item it deleted! Got %+v.", err)
+	}
+
+	// Helpers built on the client's writes validate too.
+	_, _, err = webflowAPI.UpsertItem(client, "posts1", "", map[string]interface{}{"slug": "hello", "rating": 9}, false)
+	if errs, ok := err.(FieldErrors); !ok || codes(errs) != "rating:max" {
+		t.Errorf("UpsertItem() is expected to validate through the client! Got %+v.", err)
+	}
+}
@@ -0,0 +1,79 @@
+package webflowAPI
+
+import (
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// Choices of whether ListItems returns the items in a state, e.g. drafts.
+	// IncludeItems List the items whether or not they are in the state.
+	IncludeItems = ""
+	// ExcludeItems List only the items not in the state.
+	ExcludeItems = "exclude"
+	// OnlyItems List only the items in the state.
+	OnlyItems = "only"
+)
+
+// ListOptions Which of a collection's items ListItems returns, by their _draft & _archived flags: IncludeItems,
+// ExcludeItems or OnlyItems. The zero value lists every item.
+type ListOptions struct {
+	Drafts   string
+	Archived string
+}
+
+// Live Options listing the items that are on the live site once published: neither drafts nor archived.
+var Live = ListOptions{Drafts: ExcludeItems, Archived: ExcludeItems}
+
+// Match Whether the raw JSON item is listed.
+func (options ListOptions) Match(item []byte) bool {
+	return matchState(options.Drafts, gjson.GetBytes(item, "_draft").Bool()) &&
+		matchState(options.Archived, gjson.GetBytes(item, "_archived").Bool())
+}
+
+// ListItems The raw JSON of the collection's items chosen by their draft & archived flags, e.g. Live for those that are
+// on the live site once published. See Interface.GetAllItemsInCollectionByID for maxPages.
+func ListItems(api Interface, collectionID string, maxPages int, options ListOptions) ([][]byte, error) {
+	all, err := api.GetAllItemsInCollectionByID(collectionID, maxPages)
+	if err != nil {
+		return nil, err
+	}
+
+	items := [][]byte{}
+	for _, item := range all {
+		if options.Match(item) {
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// ArchiveItem Archive an item in the staged site; publish it to take it off the live site. Returns the raw JSON of the
+// updated item.
+func ArchiveItem(api Interface, collectionID, itemID string) ([]byte, error) {
+	return api.PatchItem(collectionID, itemID, map[string]bool{"_archived": true}, false)
+}
+
+// UnarchiveItem Restore an archived item in the staged site; publish it to put it back on the live site. Returns the
+// raw JSON of the updated item.
+func UnarchiveItem(api Interface, collectionID, itemID string) ([]byte, error) {
+	return api.PatchItem(collectionID, itemID, map[string]bool{"_archived": false}, false)
+}
+
+// SetDraft Mark an item as a draft, which is left out when publishing, or as ready to publish. Returns the raw JSON of
+// the updated item.
+func SetDraft(api Interface, collectionID, itemID string, draft bool) ([]byte, error) {
+	return api.PatchItem(collectionID, itemID, map[string]bool{"_draft": draft}, false)
+}
+
+// matchState Whether an item that is, or is not, in a state is listed by the choice.
+func matchState(choice string, in bool) bool {
+	switch choice {
+	case ExcludeItems:
+		return !in
+	case OnlyItems:
+		return in
+	}
+
+	return true
+}
@@ -0,0 +1,124 @@
+package webflowAPI
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// Actions taken by an upsert.
+	UpsertCreated = "created"
+	UpsertUpdated = "updated"
+
+	// DefaultUpsertKey Field identifying items when upserting without a key.
+	DefaultUpsertKey = "slug"
+)
+
+// UpsertItem Update the item whose key field, e.g. slug or an external ID, matches that of the fields, or create the
+// item when there is none, so jobs can be rerun without duplicating items. The key defaults to the slug. Returns
+// UpsertCreated or UpsertUpdated & the raw JSON of the item. To upsert many items, index the collection once with
+// NewItemIndex.
+func UpsertItem(api Interface, collectionID, key string, fields interface{}, live bool) (string, []byte, error) {
+	index, err := NewItemIndex(api, collectionID, key, changesMaxPages)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return index.Upsert(fields, live)
+}
+
+// ItemIndex A collection's item IDs by the value of a unique field, e.g. slug or an external ID, so items can be upserted
+// without requesting every item again. Created items are added to the index.
+type ItemIndex struct {
+	// Key The field identifying the items.
+	Key string
+
+	api          Interface
+	collectionID string
+	ids          map[string][]string
+}
+
+// NewItemIndex Index the collection's items by the key field; the slug when empty. maxPages is the number of
+// additional pages of items to request; see Interface.GetAllItemsInCollectionByID.
+func NewItemIndex(api Interface, collectionID, key string, maxPages int) (*ItemIndex, error) {
+	if key == "" {
+		key = DefaultUpsertKey
+	}
+
+	items, err := api.GetAllItemsInCollectionByID(collectionID, maxPages)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the items to index; error: %+v", err)
+	}
+
+	index := &ItemIndex{
+		Key:          key,
+		api:          api,
+		collectionID: collectionID,
+		ids:          map[string][]string{},
+	}
+	for _, item := range items {
+		index.add(gjson.GetBytes(item, key), gjson.GetBytes(item, "_id").String())
+	}
+
+	return index, nil
+}
+
+// Lookup The ID of the item whose key field has the value. Values are compared as strings, so e.g. 42 & "42" match.
+func (index *ItemIndex) Lookup(value interface{}) (string, bool) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", false
+	}
+
+	ids := index.ids[gjson.ParseBytes(data).String()]
+	if len(ids) != 1 {
+		return "", false
+	}
+
+	return ids[0], true
+}
+
+// Upsert Update the item whose key field matches that of the fields, patching only the given fields, or create the item
+// when there is none. Returns which was done & the raw JSON of the item. Fails, rather than guessing, when several
+// items match.
+func (index *ItemIndex) Upsert(fields interface{}, live bool) (string, []byte, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to encode the fields; error: %+v", err)
+	}
+
+	value := gjson.GetBytes(data, index.Key)
+	if !value.Exists() || value.Type == gjson.Null || value.String() == "" {
+		return "", nil, fmt.Errorf("the fields have no '%s' to upsert by", index.Key)
+	}
+
+	ids := index.ids[value.String()]
+	switch len(ids) {
+	case 0:
+		item, err := index.api.CreateItem(index.collectionID, fields, live)
+		if err != nil {
+			return "", nil, err
+		}
+		index.add(value, gjson.GetBytes(item, "_id").String())
+		return UpsertCreated, item, nil
+	case 1:
+		item, err := index.api.PatchItem(index.collectionID, ids[0], fields, live)
+		if err != nil {
+			return "", nil, err
+		}
+		return UpsertUpdated, item, nil
+	}
+
+	return "", nil, fmt.Errorf("%d items have the %s '%s'; unable to choose one to update", len(ids), index.Key, value.String())
+}
+
+// add Record the ID of an item with the key value.
+func (index *ItemIndex) add(value gjson.Result, id string) {
+	if !value.Exists() || value.Type == gjson.Null || id == "" {
+		return
+	}
+
+	index.ids[value.String()] = append(index.ids[value.String()], id)
+}
@@ -0,0 +1,242 @@
+// Package watch Poll a collection for changes when webhooks cannot be received. Each poll is compared to the previous
+// one and the differences are sent on a channel as created, updated, deleted & published events.
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/sethgrid/pester"
+)
+
+const (
+	// Types of ChangeEvent.
+	Created   = "created"
+	Updated   = "updated"
+	Deleted   = "deleted"
+	Published = "published"
+	// Error A poll failed; the watcher backs off then polls again.
+	Error = "error"
+
+	// DefaultMaxPages Number of additional pages of items requested per poll.
+	DefaultMaxPages = 100
+	// DefaultLowRateLimit Remaining requests in the rate limit window below which polling backs off.
+	DefaultLowRateLimit = 10
+	// DefaultMaxBackoff Longest extra delay between polls, so a recovered API is noticed within minutes.
+	DefaultMaxBackoff = 5 * time.Minute
+)
+
+// Metadata that changes when an item is published, so does not on its own make the item updated.
+var publishFields = map[string]bool{"updated-on": true, "updated-by": true, "published-on": true, "published-by": true}
+
+// ChangeEvent A change to an item seen between two polls.
+type ChangeEvent struct {
+	Type         string    `json:"type"`
+	CollectionID string    `json:"collectionId"`
+	ItemID       string    `json:"itemId,omitempty"`
+	Time         time.Time `json:"time"`
+	// Item The item as of this poll; nil when deleted.
+	Item json.RawMessage `json:"item,omitempty"`
+	// Previous The item as of the previous poll; nil when created.
+	Previous json.RawMessage `json:"previous,omitempty"`
+	// Err Why the poll failed, for Error events.
+	Err error `json:"-"`
+}
+
+// rateLimited Implemented by clients that know how many requests remain in the rate limit window, such as the one
+// created by webflowAPI.New.
+type rateLimited interface {
+	RateLimitRemaining() int
+}
+
+// Watcher Polls collections.
+type Watcher struct {
+	// MaxPages Number of additional pages of items to request. See webflowAPI.Interface.GetAllItemsInCollectionByID.
+	MaxPages int
+	// LowRateLimit Remaining requests in the rate limit window below which polling backs off. Only applies when the
+	// client reports the rate limit.
+	LowRateLimit int
+	// Backoff Extra delay before the given retry after a failed poll, or a poll leaving the rate limit low.
+	Backoff func(retry int) time.Duration
+	// MaxBackoff Longest extra delay taken from Backoff; zero for no limit.
+	MaxBackoff time.Duration
+
+	api webflowAPI.Interface
+	// Override for the clock. Use only for internal testing of the pkg.
+	now func() time.Time
+}
+
+// New Create a watcher polling through the given API.
+func New(api webflowAPI.Interface) *Watcher {
+	return &Watcher{
+		MaxPages:     DefaultMaxPages,
+		LowRateLimit: DefaultLowRateLimit,
+		Backoff:      pester.ExponentialBackoff,
+		MaxBackoff:   DefaultMaxBackoff,
+		api:          api,
+		now:          time.Now,
+	}
+}
+
+// Watch Poll the collection every interval until the context is done, when the channel is closed. The first poll is
+// the baseline; events are sent for the changes seen by each later poll.
+func (w *Watcher) Watch(ctx context.Context, collectionID string, interval time.Duration) <-chan ChangeEvent {
+	events := make(chan ChangeEvent)
+
+	go func() {
+		defer close(events)
+
+		var previous *snapshot
+		retry := 0
+
+		for {
+			wait := interval
+
+			items, err := w.api.GetAllItemsInCollectionByID(collectionID, w.MaxPages)
+			if err != nil {
+				wait += w.backoff(&retry)
+				if !send(ctx, events, ChangeEvent{Type: Error, CollectionID: collectionID, Time: w.now(), Err: err}) {
+					return
+				}
+			} else {
+				current := newSnapshot(items)
+				if previous != nil {
+					for _, event := range previous.changes(current) {
+						event.CollectionID = collectionID
+						event.Time = w.now()
+						if !send(ctx, events, event) {
+							return
+						}
+					}
+				}
+				previous = current
+
+				// Back off further while the rate limit stays low; start over once it has recovered.
+				if w.rateLimitLow() {
+					wait += w.backoff(&retry)
+				} else {
+					retry = 0
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+		}
+	}()
+
+	return events
+}
+
+// backoff Count another retry and return the extra delay before it, at most MaxBackoff. The count stops growing once the
+// delay reaches MaxBackoff, so the delay stays there rather than overflowing.
+func (w *Watcher) backoff(retry *int) time.Duration {
+	*retry++
+	delay := w.Backoff(*retry)
+	if w.MaxBackoff > 0 && (delay >= w.MaxBackoff || delay < 0) {
+		*retry--
+		return w.MaxBackoff
+	}
+
+	return delay
+}
+
+// rateLimitLow Whether the client reports few requests left in the rate limit window.
+func (w *Watcher) rateLimitLow() bool {
+	limited, ok := w.api.(rateLimited)
+	if !ok {
+		return false
+	}
+
+	remaining := limited.RateLimitRemaining()
+	return remaining >= 0 && remaining < w.LowRateLimit
+}
+
+// send Send the event unless the context is done first.
+func send(ctx context.Context, events chan<- ChangeEvent, event ChangeEvent) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case events <- event:
+		return true
+	}
+}
+
+// snapshot The items of one poll.
+type snapshot struct {
+	order []string
+	raw   map[string]json.RawMessage
+	items map[string]map[string]interface{}
+}
+
+// newSnapshot Index the items by ID.
+func newSnapshot(items [][]byte) *snapshot {
+	s := &snapshot{raw: map[string]json.RawMessage{}, items: map[string]map[string]interface{}{}}
+
+	for _, raw := range items {
+		item := map[string]interface{}{}
+		if err := json.Unmarshal(raw, &item); err != nil {
+			continue
+		}
+		id, _ := item["_id"].(string)
+		if _, ok := s.items[id]; ok || id == "" {
+			continue
+		}
+		s.order = append(s.order, id)
+		s.raw[id] = raw
+		s.items[id] = item
+	}
+
+	return s
+}
+
+// changes The events turning this snapshot into the next: created, updated & published in the next's order, then
+// deleted in this one's.
+func (s *snapshot) changes(next *snapshot) []ChangeEvent {
+	events := []ChangeEvent{}
+
+	for _, id := range next.order {
+		before, ok := s.items[id]
+		if !ok {
+			events = append(events, ChangeEvent{Type: Created, ItemID: id, Item: next.raw[id]})
+			continue
+		}
+
+		after := next.items[id]
+		if contentChanged(before, after) {
+			events = append(events, ChangeEvent{Type: Updated, ItemID: id, Item: next.raw[id], Previous: s.raw[id]})
+		}
+		if published, _ := after["published-on"].(string); published != "" && published != before["published-on"] {
+			events = append(events, ChangeEvent{Type: Published, ItemID: id, Item: next.raw[id], Previous: s.raw[id]})
+		}
+	}
+
+	for _, id := range s.order {
+		if _, ok := next.items[id]; !ok {
+			events = append(events, ChangeEvent{Type: Deleted, ItemID: id, Previous: s.raw[id]})
+		}
+	}
+
+	return events
+}
+
+// contentChanged Whether any field but the publishing metadata differs.
+func contentChanged(before, after map[string]interface{}) bool {
+	for key, val := range after {
+		if !publishFields[key] && !reflect.DeepEqual(val, before[key]) {
+			return true
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok && !publishFields[key] {
+			return true
+		}
+	}
+
+	return false
+}
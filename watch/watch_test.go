@@ -0,0 +1,131 @@
+package watch
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI/memory"
+)
+
+// pollingStore Signals each poll, and reports a low rate limit, so tests can change items between polls.
+type pollingStore struct {
+	*memory.Store
+	ctx   context.Context
+	polls chan struct{}
+}
+
+func (s *pollingStore) GetAllItemsInCollectionByID(ID string, maxPages int) ([][]byte, error) {
+	items, err := s.Store.GetAllItemsInCollectionByID(ID, maxPages)
+	select {
+	case s.polls <- struct{}{}:
+	case <-s.ctx.Done():
+	}
+	return items, err
+}
+
+func (s *pollingStore) RateLimitRemaining() int {
+	return 1
+}
+
+func newTestWatcher(t *testing.T) (*Watcher, *pollingStore, context.CancelFunc) {
+	store, err := memory.Load("mysiteid", "../memory/testdata/site.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ps := &pollingStore{Store: store, ctx: ctx, polls: make(chan struct{})}
+
+	w := New(ps)
+	w.Backoff = func(retry int) time.Duration { return 0 }
+
+	return w, ps, cancel
+}
+
+func TestWatch(t *testing.T) {
+	w, store, cancel := newTestWatcher(t)
+	defer cancel()
+
+	var retries int32
+	w.Backoff = func(retry int) time.Duration {
+		atomic.StoreInt32(&retries, int32(retry))
+		return 0
+	}
+
+	events := w.Watch(store.ctx, "1", time.Millisecond)
+	<-store.polls
+
+	item, _ := store.CreateItem("1", map[string]string{"name": "brown", "slug": "brown", "color": "brown"}, false)
+	store.PatchItem("1", "d1", map[string]string{"name": "navy"}, true)
+	store.PatchItem("1", "d2", map[string]string{}, true)
+	store.DeleteItem("1", "d3")
+
+	<-store.polls
+
+	expected := []struct{ kind, id string }{
+		{Updated, "d1"}, {Published, "d1"}, {Published, "d2"}, {Created, ""}, {Deleted, "d3"},
+	}
+	for _, e := range expected {
+		select {
+		case event := <-events:
+			if event.Type != e.kind || e.id != "" && event.ItemID != e.id || event.CollectionID != "1" {
+				t.Errorf("Watch() is expected to send a %s event for %s! Got %+v.", e.kind, e.id, event)
+			}
+			if event.Type == Created && string(event.Item) != string(item) {
+				t.Errorf("Watch() is expected to send the created item! Got %s.", event.Item)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Watch() did not send the %s event for %s.", e.kind, e.id)
+		}
+	}
+
+	// The backoff of the second poll is taken before the third.
+	<-store.polls
+	if retry := atomic.LoadInt32(&retries); retry < 2 {
+		t.Errorf("Watch() is expected to back off further while the rate limit stays low! Got retry %d.", retry)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	w := New(nil)
+
+	retry, wait := 0, time.Duration(0)
+	for i := 0; i < 100; i++ {
+		next := w.backoff(&retry)
+		if next < wait || next > w.MaxBackoff {
+			t.Fatalf("backoff() is expected to grow up to MaxBackoff! Got %s after %s.", next, wait)
+		}
+		wait = next
+	}
+	if wait != w.MaxBackoff || w.Backoff(retry) >= w.MaxBackoff {
+		t.Errorf("backoff() is expected to stop growing at MaxBackoff! Got %s at retry %d.", wait, retry)
+	}
+}
+
+func TestWatchError(t *testing.T) {
+	w, store, cancel := newTestWatcher(t)
+	defer cancel()
+
+	events := w.Watch(store.ctx, "nope", time.Millisecond)
+	<-store.polls
+
+	select {
+	case event := <-events:
+		if event.Type != Error || event.Err == nil {
+			t.Errorf("Watch() is expected to send an error event when polling fails! Got %+v.", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Watch() did not send the error event.")
+	}
+
+	cancel()
+	if _, ok := <-events; ok {
+		t.Error("Watch() is expected to close the channel once the context is done.")
+	}
+}
@@ -4,21 +4,34 @@ package webflowAPI
 //go:generate moq -pkg mock -out mock/webflowAPI_moq.go . Interface
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/sethgrid/pester"
-	"github.com/tidwall/gjson"
 )
 
 const (
 	defaultURL     = "https://api.webflow.com"
 	defaultVersion = "1.0.0"
 
+	// Safety limit on the number of pages read by ItemsChangedSince, DeletedItemIDs & UpsertItem.
+	changesMaxPages = 1000
+
+	// List Sites.
+	// http://developers.webflow.com/?shell#list-sites
+	listSitesURL = "/sites"
+
+	// Publish Site.
+	// http://developers.webflow.com/?shell#publish-site
+	publishSiteURL = "/sites/%s/publish"
+
 	// List Collections.
 	// http://developers.webflow.com/?shell#list-collections
 	listCollectionsURL = "/sites/%s/collections"
@@ -26,33 +39,79 @@ const (
 	// Get All Items For a Collection.
 	// http://developers.webflow.com/?shell#get-all-items-for-a-collection
 	listCollectionItemsURL = "/collections/%s/items"
+
+	// Get Collection with Full Schema.
+	// http://developers.webflow.com/?shell#get-collection-with-full-schema
+	collectionURL = "/collections/%s"
+
+	// Create, update, patch & remove a collection item.
+	// http://developers.webflow.com/?shell#create-new-collection-item
+	itemURL = "/collections/%s/items/%s"
+
+	// Publish items of a collection to the live site.
+	// https://developers.webflow.com/#publish-items
+	publishItemsURL = "/collections/%s/items/publish"
+
+	// Create fields, and update or remove a field, of a collection. Requires an API version with collection management.
+	fieldsURL = "/collections/%s/fields"
+	fieldURL  = "/collections/%s/fields/%s"
 )
 
 // Interface Interface for this package's method. Created primarily for testing your code that depends on this package.
 type Interface interface {
 	MethodGet(uri string, queryParams map[string]string, decodedResponse interface{}) error
+	GetAllSites() (*Sites, error)
+	PublishSite(domains []string) error
 	GetAllCollections() (*Collections, error)
+	GetCollectionByID(ID string) (*Collection, error)
 	GetCollectionByName(name string) (*Collection, error)
 	GetCollectionBySlug(slug string) (*Collection, error)
 	GetAllItemsInCollectionByID(ID string, maxPages int) ([][]byte, error)
 	GetAllItemsInCollectionByName(name string, maxPages int) ([][]byte, error)
 	GetAllItemsInCollectionBySlug(slug string, maxPages int) ([][]byte, error)
 	GetItem(cName, cSlug, cID, iName, iID string) ([]byte, error)
+	CreateItem(collectionID string, fields interface{}, live bool) ([]byte, error)
+	UpdateItem(collectionID, itemID string, fields interface{}, live bool) ([]byte, error)
+	PatchItem(collectionID, itemID string, fields interface{}, live bool) ([]byte, error)
+	DeleteItem(collectionID, itemID string) error
+	PublishItems(collectionID string, itemIDs []string) (*PublishedItems, error)
+	CreateCollection(definition CollectionDefinition) (*Collection, error)
+	DeleteCollection(collectionID string) error
+	CreateField(collectionID string, definition FieldDefinition) (*CollectionField, error)
+	UpdateField(collectionID, fieldID string, definition FieldDefinition) (*CollectionField, error)
+	DeleteField(collectionID, fieldID string) error
 }
 
 // apiConfig Represents a configuration struct for Webflow apiConfig object.
 type apiConfig struct {
+	// Requests left in the current rate limit window as last reported by Webflow; -1 until known. Kept first so it is
+	// 64-bit aligned for atomic access on 32-bit platforms.
+	rateLimitRemaining              int64
 	Client                          *pester.Client
 	Token, Version, BaseURL, SiteID string
 	// The following methods are overrides for the public methods. Use only for internal testing of the pkg.
 	methodGet                     func(uri string, queryParams map[string]string, decodedResponse interface{}) error
+	getAllSites                   func() (*Sites, error)
+	publishSite                   func(domains []string) error
 	getAllCollections             func() (*Collections, error)
+	getCollectionByID             func(ID string) (*Collection, error)
 	getCollectionByName           func(name string) (*Collection, error)
 	getCollectionBySlug           func(slug string) (*Collection, error)
 	getAllItemsInCollectionByID   func(ID string, maxPages int) ([][]byte, error)
 	getAllItemsInCollectionByName func(name string, maxPages int) ([][]byte, error)
 	getAllItemsInCollectionBySlug func(slug string, maxPages int) ([][]byte, error)
 	getItem                       func(cName, cSlug, cID, iName, iID string) ([]byte, error)
+	createItem                    func(collectionID string, fields interface{}, live bool) ([]byte, error)
+	updateItem                    func(collectionID, itemID string, fields interface{}, live bool) ([]byte, error)
+	patchItem                     func(collectionID, itemID string, fields interface{}, live bool) ([]byte, error)
+	deleteItem                    func(collectionID, itemID string) error
+	publishItems                  func(collectionID string, itemIDs []string) (*PublishedItems, error)
+	createCollection              func(definition CollectionDefinition) (*Collection, error)
+	deleteCollection              func(collectionID string) error
+	createField                   func(collectionID string, definition FieldDefinition) (*CollectionField, error)
+	updateField                   func(collectionID, fieldID string, definition FieldDefinition) (*CollectionField, error)
+	deleteField                   func(collectionID, fieldID string) error
+	methodRequest                 func(method, uri string, queryParams map[string]string, body, decodedResponse interface{}) error
 }
 
 // New Create a new configuration struct for the Webflow API object.
@@ -78,9 +137,17 @@ func New(token, siteID string, hc *http.Client) *apiConfig {
 		Version: defaultVersion,
 		BaseURL: defaultURL,
 		SiteID:  siteID,
+
+		rateLimitRemaining: -1,
 	}
 }
 
+// RateLimitRemaining The number of requests left in the current rate limit window, as reported by the last response,
+// or -1 when not yet known.
+func (api *apiConfig) RateLimitRemaining() int {
+	return int(atomic.LoadInt64(&api.rateLimitRemaining))
+}
+
 // MethodGet Execute a HTTP GET on the specified URI.
 func (api *apiConfig) MethodGet(uri string, queryParams map[string]string, decodedResponse interface{}) error {
 	// If an override was configured, use it instead.
@@ -88,15 +155,42 @@ func (api *apiConfig) MethodGet(uri string, queryParams map[string]string, decod
 		return api.methodGet(uri, queryParams, decodedResponse)
 	}
 
+	return api.doRequest(http.MethodGet, uri, queryParams, nil, decodedResponse)
+}
+
+// request Execute a HTTP request with the given method on the specified URI. The body, when not nil, is sent as JSON.
+func (api *apiConfig) request(method, uri string, queryParams map[string]string, body, decodedResponse interface{}) error {
+	// If an override was configured, use it instead.
+	if api.methodRequest != nil {
+		return api.methodRequest(method, uri, queryParams, body, decodedResponse)
+	}
+
+	return api.doRequest(method, uri, queryParams, body, decodedResponse)
+}
+
+// doRequest Make the HTTP request to Webflow and decode the response.
+func (api *apiConfig) doRequest(method, uri string, queryParams map[string]string, body, decodedResponse interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("unable to encode the request body; error: %+v", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
 	// Form the request to make to WebFlow.
-	req, err := http.NewRequest("GET", api.BaseURL+uri, nil)
+	req, err := http.NewRequest(method, api.BaseURL+uri, reqBody)
 	if err != nil {
 		return errors.New(fmt.Sprint("Unable to create a new http request", err))
 	}
 
 	// Webflow needs to know the auth token and the version of their API to use.
 	req.Header.Set("Authorization", "Bearer "+api.Token)
-	req.Header.Set("Accept-Version", defaultVersion)
+	req.Header.Set("Accept-Version", api.Version)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
 	// Set query parameters.
 	if len(queryParams) > 0 {
@@ -115,6 +209,10 @@ func (api *apiConfig) MethodGet(uri string, queryParams map[string]string, decod
 	// TODO: read docs for ReaderCloser.Close() to determine what to do when it errors.
 	defer res.Body.Close()
 
+	if remaining, err := strconv.Atoi(res.Header.Get("X-RateLimit-Remaining")); err == nil {
+		atomic.StoreInt64(&api.rateLimitRemaining, int64(remaining))
+	}
+
 	// Status codes of 200 to 299 are healthy; the rest are an error, redirect, etc.
 	if res.StatusCode >= 300 || res.StatusCode < 200 {
 		errResp := &GeneralError{}
@@ -131,6 +229,47 @@ func (api *apiConfig) MethodGet(uri string, queryParams map[string]string, decod
 	return nil
 }
 
+// GetAllSites Ask the Webflow API for all the sites the token has access to.
+func (api *apiConfig) GetAllSites() (*Sites, error) {
+	// If an override was configured, use it instead.
+	if api.getAllSites != nil {
+		return api.getAllSites()
+	}
+
+	sites := &Sites{}
+	err := api.MethodGet(listSitesURL, nil, sites)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return sites, nil
+}
+
+// PublishSite Ask Webflow to publish the site to the given domains.
+func (api *apiConfig) PublishSite(domains []string) error {
+	// If an override was configured, use it instead.
+	if api.publishSite != nil {
+		return api.publishSite(domains)
+	}
+
+	if domains == nil {
+		domains = []string{}
+	}
+
+	res := &struct {
+		Queued bool `json:"queued"`
+	}{}
+
+	return api.request(
+		http.MethodPost,
+		fmt.Sprintf(publishSiteURL, api.SiteID),
+		nil,
+		map[string][]string{"domains": domains},
+		res,
+	)
+}
+
 // GetAllCollections Ask the Webflow API for all the collections on a given site.
 func (api *apiConfig) GetAllCollections() (*Collections, error) {
 	// If an override was configured, use it instead.
@@ -148,6 +287,23 @@ func (api *apiConfig) GetAllCollections() (*Collections, error) {
 	return collections, nil
 }
 
+// GetCollectionByID Ask the Webflow API for a collection, including its fields, by the collection's ID.
+func (api *apiConfig) GetCollectionByID(id string) (*Collection, error) {
+	// If an override was configured, use it instead.
+	if api.getCollectionByID != nil {
+		return api.getCollectionByID(id)
+	}
+
+	collection := &Collection{}
+	err := api.MethodGet(fmt.Sprintf(collectionURL, id), nil, collection)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
 // GetCollectionByName Query Webflow for all the collections then search them for the requested name, case insensitive.
 func (api *apiConfig) GetCollectionByName(name string) (*Collection, error) {
 	// If an override was configured, use it instead.
@@ -203,42 +359,21 @@ func (api *apiConfig) GetAllItemsInCollectionByID(id string, maxPages int) ([][]
 		return api.getAllItemsInCollectionByID(id, maxPages)
 	}
 
-	offset := 0
 	items := [][]byte{}
+	paginator := NewPaginator(api, NewCursor(id))
 
-	for {
-		queryParams := map[string]string{
-			"offset": strconv.Itoa(offset),
-			"limit":  "100",
+	// Webflow API reports when the last set of items has been requested. At least one page is requested.
+	for page := 0; !paginator.Done(); page++ {
+		// Safety feature to keep the code from asking the API for far too many items.
+		if page > 0 && page > maxPages {
+			break
 		}
 
-		collectionItems := &CollectionItems{}
-		err := api.MethodGet(fmt.Sprintf(listCollectionItemsURL, id), queryParams, collectionItems)
+		pageItems, err := paginator.Next()
 		if err != nil {
 			return nil, err
 		}
-
-		// Iteratae over all the collection's items.
-		jsonItems := gjson.Parse(string(collectionItems.Items))
-		jsonItems.ForEach(func(key, value gjson.Result) bool {
-			// Add each json item to the slice.
-			items = append(items, []byte(value.Raw))
-			// Keep iterating.
-			return true
-		})
-
-		offset = collectionItems.Offset + collectionItems.Count
-
-		// Webflow API should report when the last set of items has been requested. Once this has happened, this loop should
-		// be broken.
-		if offset >= collectionItems.Total {
-			break
-		}
-
-		// Safety feature to keep the code from infinite looping or asking the API for far too many items.
-		if maxPages--; maxPages < 0 {
-			break
-		}
+		items = append(items, pageItems...)
 	}
 
 	return items, nil
@@ -352,3 +487,197 @@ func (api *apiConfig) GetItem(cName, cSlug, cID, iName, iID string) ([]byte, err
 
 	return nil, nil
 }
+
+// CreateItem Create an item in a collection. The fields are sent as the item's `fields` and may be a struct or a map.
+// Setting live publishes the item immediately. Returns the raw JSON of the created item.
+func (api *apiConfig) CreateItem(collectionID string, fields interface{}, live bool) ([]byte, error) {
+	// If an override was configured, use it instead.
+	if api.createItem != nil {
+		return api.createItem(collectionID, fields, live)
+	}
+
+	item := json.RawMessage{}
+	err := api.request(
+		http.MethodPost,
+		fmt.Sprintf(listCollectionItemsURL, collectionID),
+		liveParams(live),
+		map[string]interface{}{"fields": fields},
+		&item,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// UpdateItem Replace all the fields of an item. Returns the raw JSON of the updated item.
+func (api *apiConfig) UpdateItem(collectionID, itemID string, fields interface{}, live bool) ([]byte, error) {
+	// If an override was configured, use it instead.
+	if api.updateItem != nil {
+		return api.updateItem(collectionID, itemID, fields, live)
+	}
+
+	item := json.RawMessage{}
+	err := api.request(
+		http.MethodPut,
+		fmt.Sprintf(itemURL, collectionID, itemID),
+		liveParams(live),
+		map[string]interface{}{"fields": fields},
+		&item,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// PatchItem Update only the given fields of an item. Returns the raw JSON of the updated item.
+func (api *apiConfig) PatchItem(collectionID, itemID string, fields interface{}, live bool) ([]byte, error) {
+	// If an override was configured, use it instead.
+	if api.patchItem != nil {
+		return api.patchItem(collectionID, itemID, fields, live)
+	}
+
+	item := json.RawMessage{}
+	err := api.request(
+		http.MethodPatch,
+		fmt.Sprintf(itemURL, collectionID, itemID),
+		liveParams(live),
+		map[string]interface{}{"fields": fields},
+		&item,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// DeleteItem Remove an item from a collection.
+func (api *apiConfig) DeleteItem(collectionID, itemID string) error {
+	// If an override was configured, use it instead.
+	if api.deleteItem != nil {
+		return api.deleteItem(collectionID, itemID)
+	}
+
+	res := &struct {
+		Deleted int `json:"deleted"`
+	}{}
+
+	return api.request(http.MethodDelete, fmt.Sprintf(itemURL, collectionID, itemID), nil, nil, res)
+}
+
+// PublishItems Publish the staged versions of the collection's items to the live site without publishing the whole
+// site. Items that could not be published, e.g. drafts, are reported in the result's errors.
+func (api *apiConfig) PublishItems(collectionID string, itemIDs []string) (*PublishedItems, error) {
+	// If an override was configured, use it instead.
+	if api.publishItems != nil {
+		return api.publishItems(collectionID, itemIDs)
+	}
+
+	if itemIDs == nil {
+		itemIDs = []string{}
+	}
+
+	res := &PublishedItems{}
+	err := api.request(
+		http.MethodPut,
+		fmt.Sprintf(publishItemsURL, collectionID),
+		nil,
+		map[string][]string{"itemIds": itemIDs},
+		res,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// CreateCollection Create a collection on the site, with the name & slug fields along with the given ones. Returns the
+// collection with all its fields.
+func (api *apiConfig) CreateCollection(definition CollectionDefinition) (*Collection, error) {
+	// If an override was configured, use it instead.
+	if api.createCollection != nil {
+		return api.createCollection(definition)
+	}
+
+	collection := &Collection{}
+	err := api.request(http.MethodPost, fmt.Sprintf(listCollectionsURL, api.SiteID), nil, definition, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+// DeleteCollection Remove a collection, along with its items.
+func (api *apiConfig) DeleteCollection(collectionID string) error {
+	// If an override was configured, use it instead.
+	if api.deleteCollection != nil {
+		return api.deleteCollection(collectionID)
+	}
+
+	res := &struct {
+		Deleted int `json:"deleted"`
+	}{}
+
+	return api.request(http.MethodDelete, fmt.Sprintf(collectionURL, collectionID), nil, nil, res)
+}
+
+// CreateField Add a field to a collection. Returns the created field.
+func (api *apiConfig) CreateField(collectionID string, definition FieldDefinition) (*CollectionField, error) {
+	// If an override was configured, use it instead.
+	if api.createField != nil {
+		return api.createField(collectionID, definition)
+	}
+
+	field := &CollectionField{}
+	err := api.request(http.MethodPost, fmt.Sprintf(fieldsURL, collectionID), nil, definition, field)
+	if err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// UpdateField Change a field's name, slug, help text, required flag or validations. Returns the updated field.
+func (api *apiConfig) UpdateField(collectionID, fieldID string, definition FieldDefinition) (*CollectionField, error) {
+	// If an override was configured, use it instead.
+	if api.updateField != nil {
+		return api.updateField(collectionID, fieldID, definition)
+	}
+
+	field := &CollectionField{}
+	err := api.request(http.MethodPatch, fmt.Sprintf(fieldURL, collectionID, fieldID), nil, definition, field)
+	if err != nil {
+		return nil, err
+	}
+
+	return field, nil
+}
+
+// DeleteField Remove a field from a collection, along with its values in every item.
+func (api *apiConfig) DeleteField(collectionID, fieldID string) error {
+	// If an override was configured, use it instead.
+	if api.deleteField != nil {
+		return api.deleteField(collectionID, fieldID)
+	}
+
+	res := &struct {
+		Deleted int `json:"deleted"`
+	}{}
+
+	return api.request(http.MethodDelete, fmt.Sprintf(fieldURL, collectionID, fieldID), nil, nil, res)
+}
+
+// liveParams Query params asking Webflow to publish the change immediately.
+func liveParams(live bool) map[string]string {
+	if !live {
+		return nil
+	}
+
+	return map[string]string{"live": "true"}
+}
@@ -2,13 +2,20 @@ package webflowAPI
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/tidwall/gjson"
 )
 
 const (
@@ -700,3 +707,520 @@ func TestGetItem(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateItem(t *testing.T) {
+	// Start a special, local HTTP server.
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		expectedURI := fmt.Sprintf(listCollectionItemsURL, exampleDogCollection.ID) + "?live=true"
+		if req.Method != http.MethodPost || req.URL.String() != expectedURI {
+			t.Errorf("CreateItem() did not make the proper request! Got '%s %s'; expected 'POST %s'.", req.Method, req.URL, expectedURI)
+		}
+
+		body := &struct {
+			Fields mockItem `json:"fields"`
+		}{}
+		if err := json.NewDecoder(req.Body).Decode(body); err != nil || body.Fields.Name != exampleItemDog1.Name {
+			t.Errorf("CreateItem() is expected to send the item as `fields`! Got %+v; error %+v.", body, err)
+		}
+
+		rw.Write(exampleItemDog1JSON)
+	}))
+	defer server.Close()
+
+	api := New("mytoken", siteID, nil)
+	api.BaseURL = server.URL
+	item, err := api.CreateItem(exampleDogCollection.ID, exampleItemDog1, true)
+
+	if err != nil {
+		t.Errorf("CreateItem() is expected to return no error when receiving a properly formatted response. got: %+v", err)
+	}
+
+	if string(item) != string(exampleItemDog1JSON) {
+		t.Errorf("CreateItem() is expected to return the raw JSON of the created item! Got %s.", item)
+	}
+}
+
+func TestUpdateItem(t *testing.T) {
+	method := ""
+	// Start a special, local HTTP server.
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		method = req.Method
+		expectedURI := fmt.Sprintf(itemURL, exampleDogCollection.ID, exampleItemDog2.ID)
+		if req.URL.String() != expectedURI {
+			t.Errorf("UpdateItem() did not request the proper URI! requested '%s'; expected '%s'.", req.URL, expectedURI)
+		}
+
+		rw.Write(exampleItemDog2JSON)
+	}))
+	defer server.Close()
+
+	api := New("mytoken", siteID, nil)
+	api.BaseURL = server.URL
+
+	{
+		item, err := api.UpdateItem(exampleDogCollection.ID, exampleItemDog2.ID, exampleItemDog2, false)
+
+		if err != nil || method != http.MethodPut {
+			t.Errorf("UpdateItem() is expected to PUT the item without error! Used %s; error %+v.", method, err)
+		}
+
+		if string(item) != string(exampleItemDog2JSON) {
+			t.Errorf("UpdateItem() is expected to return the raw JSON of the updated item! Got %s.", item)
+		}
+	}
+	{
+		_, err := api.PatchItem(exampleDogCollection.ID, exampleItemDog2.ID, map[string]string{"name": "green"}, false)
+
+		if err != nil || method != http.MethodPatch {
+			t.Errorf("PatchItem() is expected to PATCH the item without error! Used %s; error %+v.", method, err)
+		}
+	}
+}
+
+func TestDeleteItem(t *testing.T) {
+	{
+		// Start a special, local HTTP server.
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if req.Method != http.MethodDelete {
+				t.Errorf("DeleteItem() is expected to use the DELETE method! Used %s.", req.Method)
+			}
+			rw.Write([]byte(`{"deleted":1}`))
+		}))
+		defer server.Close()
+
+		api := New("mytoken", siteID, nil)
+		api.BaseURL = server.URL
+
+		if err := api.DeleteItem(exampleDogCollection.ID, exampleItemDog1.ID); err != nil {
+			t.Errorf("DeleteItem() is expected to return no error when the item is deleted: %+v", err)
+		}
+	}
+	{
+		errMsg := "item not found!"
+		// Start a special, local HTTP server.
+		server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			data, _ := json.Marshal(&GeneralError{Code: http.StatusNotFound, Err: errMsg})
+			rw.WriteHeader(http.StatusNotFound)
+			rw.Write(data)
+		}))
+		defer server.Close()
+
+		api := New("mytoken", siteID, nil)
+		api.BaseURL = server.URL
+
+		if err := api.DeleteItem(exampleDogCollection.ID, "nope"); err == nil || err.Error() != errMsg {
+			t.Errorf("DeleteItem() is expected to return the API's error message! Got %+v.", err)
+		}
+	}
+}
+
+func TestGetAllSites(t *testing.T) {
+	api := New("mytoken", siteID, nil)
+	api.methodGet = func(uri string, queryParams map[string]string, decodedResponse interface{}) error {
+		if uri != listSitesURL {
+			t.Errorf("GetAllSites() did not request the proper URI! requested '%s'; expected '%s'.", uri, listSitesURL)
+		}
+		return json.Unmarshal([]byte(`[{"_id":"mysiteid","name":"My Site"}]`), decodedResponse)
+	}
+
+	sites, err := api.GetAllSites()
+
+	if err != nil {
+		t.Errorf("GetAllSites() is expected to return no error when receiving a properly formatted response. got: %+v", err)
+	}
+
+	if sites == nil || len(*sites) != 1 || (*sites)[0].ID != siteID {
+		t.Errorf("GetAllSites() is expected to return the site! Got %+v.", sites)
+	}
+}
+
+func TestPublishSite(t *testing.T) {
+	// Start a special, local HTTP server.
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		expectedURI := fmt.Sprintf(publishSiteURL, siteID)
+		if req.Method != http.MethodPost || req.URL.String() != expectedURI {
+			t.Errorf("PublishSite() did not make the proper request! Got '%s %s'; expected 'POST %s'.", req.Method, req.URL, expectedURI)
+		}
+
+		body := &struct {
+			Domains []string `json:"domains"`
+		}{}
+		if err := json.NewDecoder(req.Body).Decode(body); err != nil || len(body.Domains) != 1 {
+			t.Errorf("PublishSite() is expected to send the domains! Got %+v; error %+v.", body, err)
+		}
+
+		rw.Write([]byte(`{"queued":true}`))
+	}))
+	defer server.Close()
+
+	api := New("mytoken", siteID, nil)
+	api.BaseURL = server.URL
+
+	if err := api.PublishSite([]string{"example.com"}); err != nil {
+		t.Errorf("PublishSite() is expected to return no error when the publish is queued: %+v", err)
+	}
+}
+
+func TestGetCollectionByID(t *testing.T) {
+	api := New("mytoken", siteID, nil)
+	api.methodGet = func(uri string, queryParams map[string]string, decodedResponse interface{}) error {
+		expectedURI := fmt.Sprintf(collectionURL, exampleDogCollection.ID)
+		if uri != expectedURI {
+			t.Errorf("GetCollectionByID() did not request the proper URI! requested '%s'; expected '%s'.", uri, expectedURI)
+		}
+		return json.Unmarshal(
+			[]byte(`{"_id":"1","name":"dogs","slug":"dogs1","fields":[{"id":"f1","slug":"name","type":"PlainText"}]}`),
+			decodedResponse,
+		)
+	}
+
+	collection, err := api.GetCollectionByID(exampleDogCollection.ID)
+
+	if err != nil {
+		t.Errorf("GetCollectionByID() is expected to return no error when receiving a properly formatted response. got: %+v", err)
+	}
+
+	if collection == nil || len(collection.Fields) != 1 || collection.Fields[0].Type != FieldTypePlainText {
+		t.Errorf("GetCollectionByID() is expected to return the collection's fields! Got %+v.", collection)
+	}
+}
+
+func TestItemsChangedSince(t *testing.T) {
+	since := time.Date(2020, 1, 10, 0, 0, 0, 0, time.UTC)
+	// Two pages of unsorted items, with changed items on both.
+	pages := []string{
+		`{"items":[
+			{"_id":"2","updated-on":"2020-01-09T00:00:00.000Z","created-on":"2020-01-09T00:00:00.000Z"},
+			{"_id":"3","updated-on":"2020-01-12T00:00:00.000Z","created-on":"2020-01-01T00:00:00.000Z"}
+		],"count":2,"offset":0,"limit":2,"total":4}`,
+		`{"items":[
+			{"_id":"1","updated-on":"2020-01-08T00:00:00.000Z","created-on":"2020-01-08T00:00:00.000Z"},
+			{"_id":"0","updated-on":"2020-01-11T00:00:00.000Z","created-on":"2020-01-11T00:00:00.000Z"}
+		],"count":2,"offset":2,"limit":2,"total":4}`,
+	}
+
+	requests := 0
+	api := New("mytoken", siteID, nil)
+	api.methodGet = func(uri string, queryParams map[string]string, decodedResponse interface{}) error {
+		requests++
+		page, _ := strconv.Atoi(queryParams["offset"])
+		return json.Unmarshal([]byte(pages[page/2]), decodedResponse)
+	}
+
+	items, err := ItemsChangedSince(api, exampleDogCollection.ID, since, false)
+	if err != nil || requests != 2 {
+		t.Fatalf("ItemsChangedSince() is expected to read every page! Got %d requests; error %+v.", requests, err)
+	}
+
+	ids := []string{}
+	for _, item := range items {
+		ids = append(ids, gjson.GetBytes(item, "_id").String())
+	}
+	if !reflect.DeepEqual(ids, []string{"3", "0"}) {
+		t.Errorf("ItemsChangedSince() is expected to return the items changed since the time on every page! Got %+v.", ids)
+	}
+
+	// Asked for sorted items, pages that come back unsorted are still all read.
+	requests = 0
+	if items, err := ItemsChangedSince(api, exampleDogCollection.ID, since, true); err != nil || requests != 2 || len(items) != 2 {
+		t.Errorf("ItemsChangedSince() is expected to read every unsorted page! Got %d items in %d requests; error %+v.", len(items), requests, err)
+	}
+
+	// Sorted pages are read up to the first page holding an older item.
+	pages = []string{
+		`{"items":[
+			{"_id":"3","updated-on":"2020-01-12T00:00:00.000Z","created-on":"2020-01-01T00:00:00.000Z"},
+			{"_id":"0","updated-on":"2020-01-11T00:00:00.000Z","created-on":"2020-01-11T00:00:00.000Z"}
+		],"count":2,"offset":0,"limit":2,"total":6}`,
+		`{"items":[
+			{"_id":"2","updated-on":"2020-01-10T12:00:00.000Z","created-on":"2020-01-09T00:00:00.000Z"},
+			{"_id":"1","updated-on":"2020-01-08T00:00:00.000Z","created-on":"2020-01-08T00:00:00.000Z"}
+		],"count":2,"offset":2,"limit":2,"total":6}`,
+		`{"items":[
+			{"_id":"5","updated-on":"2020-01-07T00:00:00.000Z","created-on":"2020-01-07T00:00:00.000Z"},
+			{"_id":"4","updated-on":"2020-01-06T00:00:00.000Z","created-on":"2020-01-06T00:00:00.000Z"}
+		],"count":2,"offset":4,"limit":2,"total":6}`,
+	}
+	requests = 0
+	sorts := []string{}
+	api.methodGet = func(uri string, queryParams map[string]string, decodedResponse interface{}) error {
+		requests++
+		sorts = append(sorts, queryParams["sort"])
+		page, _ := strconv.Atoi(queryParams["offset"])
+		return json.Unmarshal([]byte(pages[page/2]), decodedResponse)
+	}
+	items, err = ItemsChangedSince(api, exampleDogCollection.ID, since, true)
+	if err != nil || requests != 2 || len(items) != 3 || sorts[0] != "-updated-on" {
+		t.Errorf("ItemsChangedSince() is expected to stop at the first page holding an older item! Got %d items in %d requests sorted by %v; error %+v.", len(items), requests, sorts, err)
+	}
+}
+
+func TestDeletedItemIDs(t *testing.T) {
+	api := New("mytoken", siteID, nil)
+	api.getAllItemsInCollectionByID = func(ID string, maxPages int) ([][]byte, error) {
+		return [][]byte{[]byte(`{"_id":"1"}`), []byte(`{"_id":"3"}`)}, nil
+	}
+
+	deleted, err := DeletedItemIDs(api, exampleDogCollection.ID, []string{"1", "2", "3", "4"})
+	if err != nil || !reflect.DeepEqual(deleted, []string{"2", "4"}) {
+		t.Errorf("DeletedItemIDs() is expected to return the known IDs no longer in the collection! Got %+v; error %+v.", deleted, err)
+	}
+}
+
+func TestUpsertItem(t *testing.T) {
+	created, patched := []interface{}{}, []string{}
+	api := New("mytoken", siteID, nil)
+	api.getAllItemsInCollectionByID = func(ID string, maxPages int) ([][]byte, error) {
+		return [][]byte{
+			[]byte(`{"_id":"1","slug":"blue","sku":42}`),
+			[]byte(`{"_id":"2","slug":"green","sku":7}`),
+			[]byte(`{"_id":"3","slug":"red","sku":7}`),
+		}, nil
+	}
+	api.createItem = func(collectionID string, fields interface{}, live bool) ([]byte, error) {
+		created = append(created, fields)
+		return []byte(`{"_id":"4","slug":"brown"}`), nil
+	}
+	api.patchItem = func(collectionID, itemID string, fields interface{}, live bool) ([]byte, error) {
+		patched = append(patched, itemID)
+		return []byte(`{"_id":"` + itemID + `"}`), nil
+	}
+
+	action, item, err := UpsertItem(api, exampleDogCollection.ID, "", map[string]string{"name": "Blue", "slug": "blue"}, false)
+	if err != nil || action != UpsertUpdated || string(item) != `{"_id":"1"}` || !reflect.DeepEqual(patched, []string{"1"}) {
+		t.Errorf("UpsertItem() is expected to update the item with the slug! Got %s %s; error %+v.", action, item, err)
+	}
+
+	action, _, err = UpsertItem(api, exampleDogCollection.ID, "slug", map[string]string{"slug": "brown"}, false)
+	if err != nil || action != UpsertCreated || len(created) != 1 {
+		t.Errorf("UpsertItem() is expected to create an item when none has the slug! Got %s; error %+v.", action, err)
+	}
+
+	// Keys are compared as strings, so numbers match.
+	action, _, err = UpsertItem(api, exampleDogCollection.ID, "sku", map[string]interface{}{"sku": "42"}, false)
+	if err != nil || action != UpsertUpdated || patched[len(patched)-1] != "1" {
+		t.Errorf("UpsertItem() is expected to update the item with the key! Got %s; error %+v.", action, err)
+	}
+
+	if _, _, err := UpsertItem(api, exampleDogCollection.ID, "sku", map[string]interface{}{"sku": 7}, false); err == nil {
+		t.Error("UpsertItem() is expected to fail when several items have the key!")
+	}
+	if _, _, err := UpsertItem(api, exampleDogCollection.ID, "sku", map[string]interface{}{"name": "Grey"}, false); err == nil {
+		t.Error("UpsertItem() is expected to fail when the fields have no key!")
+	}
+
+	// An index records the items it creates, so reruns update them.
+	index, err := NewItemIndex(api, exampleDogCollection.ID, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index.Upsert(map[string]string{"slug": "brown"}, false)
+	if id, ok := index.Lookup("brown"); !ok || id != "4" {
+		t.Errorf("Upsert() is expected to add created items to the index! Got %s.", id)
+	}
+	if action, _, _ := index.Upsert(map[string]string{"slug": "brown"}, false); action != UpsertUpdated {
+		t.Errorf("Upsert() is expected to update an item it created! Got %s.", action)
+	}
+}
+
+func TestItemStates(t *testing.T) {
+	api := New("mytoken", siteID, nil)
+	api.getAllItemsInCollectionByID = func(ID string, maxPages int) ([][]byte, error) {
+		return [][]byte{
+			[]byte(`{"_id":"1","_draft":false,"_archived":false}`),
+			[]byte(`{"_id":"2","_draft":true,"_archived":false}`),
+			[]byte(`{"_id":"3","_draft":false,"_archived":true}`),
+			[]byte(`{"_id":"4"}`),
+		}, nil
+	}
+
+	for _, test := range []struct {
+		options  ListOptions
+		expected []string
+	}{
+		{ListOptions{}, []string{"1", "2", "3", "4"}},
+		{Live, []string{"1", "4"}},
+		{ListOptions{Drafts: OnlyItems}, []string{"2"}},
+		{ListOptions{Drafts: ExcludeItems, Archived: OnlyItems}, []string{"3"}},
+	} {
+		items, err := ListItems(api, exampleDogCollection.ID, 0, test.options)
+		ids := []string{}
+		for _, item := range items {
+			ids = append(ids, gjson.GetBytes(item, "_id").String())
+		}
+		if err != nil || !reflect.DeepEqual(ids, test.expected) {
+			t.Errorf("ListItems(%+v) is expected to return %v! Got %v; error %+v.", test.options, test.expected, ids, err)
+		}
+	}
+
+	patches := []string{}
+	api.patchItem = func(collectionID, itemID string, fields interface{}, live bool) ([]byte, error) {
+		data, _ := json.Marshal(fields)
+		patches = append(patches, fmt.Sprintf("%s %s %v", itemID, data, live))
+		return data, nil
+	}
+	ArchiveItem(api, exampleDogCollection.ID, "1")
+	UnarchiveItem(api, exampleDogCollection.ID, "3")
+	SetDraft(api, exampleDogCollection.ID, "2", false)
+	expected := []string{`1 {"_archived":true} false`, `3 {"_archived":false} false`, `2 {"_draft":false} false`}
+	if !reflect.DeepEqual(patches, expected) {
+		t.Errorf("ArchiveItem(), UnarchiveItem() & SetDraft() are expected to patch the flags! Got %v.", patches)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		payload := map[string][]string{}
+		json.NewDecoder(req.Body).Decode(&payload)
+		if req.Method != http.MethodPut || req.URL.Path != "/collections/"+exampleDogCollection.ID+"/items/publish" || len(payload["itemIds"]) != 2 {
+			t.Errorf("PublishItems() is expected to PUT the item IDs! Got %s %s %v.", req.Method, req.URL.Path, payload)
+		}
+		rw.Write([]byte(`{"publishedItemIds":["1"],"errors":["item 2 is a draft"]}`))
+	}))
+	defer server.Close()
+	api.BaseURL = server.URL
+
+	res, err := api.PublishItems(exampleDogCollection.ID, []string{"1", "2"})
+	if err != nil || !reflect.DeepEqual(res.PublishedItemIDs, []string{"1"}) || len(res.Errors) != 1 {
+		t.Errorf("PublishItems() is expected to return the published IDs & errors! Got %+v; error %+v.", res, err)
+	}
+}
+
+func TestPaginator(t *testing.T) {
+	pages := []string{
+		`{"items":[{"_id":"1"},{"_id":"2"}],"count":2,"offset":0,"limit":2,"total":3}`,
+		`{"items":[{"_id":"3"}],"count":1,"offset":2,"limit":2,"total":3}`,
+	}
+	fail := true
+
+	api := New("mytoken", siteID, nil)
+	api.methodGet = func(uri string, queryParams map[string]string, decodedResponse interface{}) error {
+		if queryParams["limit"] != "2" {
+			t.Errorf("Next() is expected to request the limit! Got %+v.", queryParams)
+		}
+		offset, _ := strconv.Atoi(queryParams["offset"])
+		if offset == 2 && fail {
+			fail = false
+			return errors.New("Service Unavailable")
+		}
+		return json.Unmarshal([]byte(pages[offset/2]), decodedResponse)
+	}
+
+	p := NewPaginator(api, NewCursor(exampleDogCollection.ID))
+	p.Limit = 2
+	if items, err := p.Next(); err != nil || len(items) != 2 || p.Done() {
+		t.Fatalf("Next() is expected to read the first page! Got %d items; error %+v.", len(items), err)
+	}
+	if _, err := p.Next(); err == nil || p.Cursor().Offset != 2 {
+		t.Fatalf("Next() is expected to leave the cursor when failing! Got %+v; error %+v.", p.Cursor(), err)
+	}
+
+	dir, err := ioutil.TempDir("", "webflow-cursor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cursor.json")
+
+	if err := p.Cursor().Save(path); err != nil {
+		t.Fatal(err)
+	}
+	cursor, err := LoadCursor(path)
+	if err != nil || *cursor != (Cursor{CollectionID: exampleDogCollection.ID, Offset: 2, Total: 3}) {
+		t.Fatalf("LoadCursor() is expected to read the saved cursor! Got %+v; error %+v.", cursor, err)
+	}
+
+	p = NewPaginator(api, *cursor)
+	p.Limit = 2
+	items, err := p.Next()
+	if err != nil || len(items) != 1 || string(items[0]) != `{"_id":"3"}` || !p.Done() {
+		t.Errorf("Next() is expected to resume from the cursor! Got %s; error %+v.", items, err)
+	}
+	if items, err := p.Next(); err != nil || len(items) != 0 {
+		t.Errorf("Next() is expected to read nothing once done! Got %s; error %+v.", items, err)
+	}
+}
+
+func TestRateLimitRemaining(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-RateLimit-Remaining", "42")
+		rw.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	api := New("mytoken", siteID, nil)
+	api.BaseURL = server.URL
+
+	if remaining := api.RateLimitRemaining(); remaining != -1 {
+		t.Errorf("RateLimitRemaining() is expected to be unknown before any request! Got %d.", remaining)
+	}
+
+	api.GetAllCollections()
+
+	if remaining := api.RateLimitRemaining(); remaining != 42 {
+		t.Errorf("RateLimitRemaining() is expected to report the last response's header! Got %d.", remaining)
+	}
+}
+
+func TestCollectionManagement(t *testing.T) {
+	requests := []string{}
+	// Start a special, local HTTP server.
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+
+		switch {
+		case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/collections"):
+			definition := &CollectionDefinition{}
+			json.NewDecoder(req.Body).Decode(definition)
+			if definition.Name != "Cats" || len(definition.Fields) != 1 || definition.Fields[0].Type != FieldTypeNumber {
+				t.Errorf("CreateCollection() is expected to send the definition! Got %+v.", definition)
+			}
+			rw.Write([]byte(`{"_id":"c1","name":"Cats","slug":"cats","fields":[{"id":"f1","slug":"name"},{"id":"f2","slug":"lives"}]}`))
+		case req.Method == http.MethodPost:
+			rw.Write([]byte(`{"id":"f3","name":"Color","slug":"color","type":"Color"}`))
+		case req.Method == http.MethodPatch:
+			rw.Write([]byte(`{"id":"f3","name":"Colour","slug":"color","type":"Color"}`))
+		default:
+			rw.Write([]byte(`{"deleted":1}`))
+		}
+	}))
+	defer server.Close()
+
+	api := New("mytoken", siteID, nil)
+	api.BaseURL = server.URL
+
+	collection, err := api.CreateCollection(CollectionDefinition{
+		Name:         "Cats",
+		SingularName: "Cat",
+		Fields:       []FieldDefinition{{Name: "Lives", Type: FieldTypeNumber}},
+	})
+	if err != nil || collection.ID != "c1" || len(collection.Fields) != 2 {
+		t.Errorf("CreateCollection() is expected to return the created collection! Got %+v; error %+v.", collection, err)
+	}
+
+	field, err := api.CreateField("c1", FieldDefinition{Name: "Color", Type: FieldTypeColor})
+	if err != nil || field.ID != "f3" {
+		t.Errorf("CreateField() is expected to return the created field! Got %+v; error %+v.", field, err)
+	}
+
+	field, err = api.UpdateField("c1", "f3", FieldDefinition{Name: "Colour"})
+	if err != nil || field.Name != "Colour" {
+		t.Errorf("UpdateField() is expected to return the updated field! Got %+v; error %+v.", field, err)
+	}
+
+	if err := api.DeleteField("c1", "f3"); err != nil {
+		t.Errorf("DeleteField() is expected to return no error: %+v", err)
+	}
+	if err := api.DeleteCollection("c1"); err != nil {
+		t.Errorf("DeleteCollection() is expected to return no error: %+v", err)
+	}
+
+	expected := []string{
+		"POST " + fmt.Sprintf(listCollectionsURL, siteID),
+		"POST " + fmt.Sprintf(fieldsURL, "c1"),
+		"PATCH " + fmt.Sprintf(fieldURL, "c1", "f3"),
+		"DELETE " + fmt.Sprintf(fieldURL, "c1", "f3"),
+		"DELETE " + fmt.Sprintf(collectionURL, "c1"),
+	}
+	if strings.Join(requests, ", ") != strings.Join(expected, ", ") {
+		t.Errorf("Collection management is expected to make the proper requests! Got %v; expected %v.", requests, expected)
+	}
+}
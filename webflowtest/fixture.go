@@ -0,0 +1,19 @@
+package webflowtest
+
+import (
+	"github.com/redeemed2011/webflowAPI/internal/fake"
+)
+
+// Fixture Initial content of a fake Webflow server.
+type Fixture = fake.Fixture
+
+// SiteFixture A site along with its collections.
+type SiteFixture = fake.SiteFixture
+
+// CollectionFixture A collection, including its fields, along with its items.
+type CollectionFixture = fake.CollectionFixture
+
+// LoadFixture Read a fixture from a JSON file.
+func LoadFixture(path string) (*Fixture, error) {
+	return fake.LoadFixture(path)
+}
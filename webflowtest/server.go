@@ -0,0 +1,716 @@
+// Package webflowtest In-process fake Webflow API server for tests. It emulates sites, collections, items (with real
+// offset/limit/total pagination), item writes, collection & field management, publishing & webhooks, and can inject
+// auth failures, rate limiting and server errors so integration tests exercise the real HTTP path of this pkg.
+//
+//	server := webflowtest.NewServer(fixture)
+//	defer server.Close()
+//
+//	api := webflowAPI.New("any token", siteID, server.Client())
+//	api.BaseURL = server.URL
+package webflowtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/internal/fake"
+	"github.com/redeemed2011/webflowAPI/internal/object"
+	"github.com/redeemed2011/webflowAPI/webhook"
+)
+
+const (
+	// Webflow never returns more than this many items per page.
+	maxLimit = 100
+	// Format Webflow uses for item timestamps.
+	timeFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// Server Fake Webflow API server.
+type Server struct {
+	*httptest.Server
+
+	// Token When set, requests must carry this bearer token. Otherwise any bearer token is accepted.
+	Token string
+	// WebhookSecret When set, registered webhooks receive deliveries signed with this secret.
+	WebhookSecret string
+	// RateLimit When set, the server reports rate limit headers and responds with 429 once this many requests have been
+	// made. Call ResetRateLimit to start a new window.
+	RateLimit int
+
+	mu          sync.Mutex
+	sites       []*site
+	collections map[string]*collection
+	faults      []int
+	requests    []string
+	used        int
+	nextID      int
+	// Override for the clock. Use only for internal testing of the pkg.
+	now func() time.Time
+}
+
+// site Server side state of a site.
+type site struct {
+	info        webflowAPI.Site
+	collections []*collection
+	webhooks    webflowAPI.Webhooks
+	published   int
+}
+
+// collection Server side state of a collection.
+type collection struct {
+	info  webflowAPI.Collection
+	site  *site
+	items []map[string]interface{}
+}
+
+// delivery A webhook delivery to make once the server's lock has been released.
+type delivery struct {
+	url  string
+	body []byte
+}
+
+// NewServer Start a fake Webflow server seeded with the fixture. A nil fixture starts an empty server.
+func NewServer(fixture *Fixture) *Server {
+	s := &Server{
+		collections: map[string]*collection{},
+		nextID:      1,
+		now:         time.Now,
+	}
+
+	if fixture != nil {
+		for _, sf := range fixture.Sites {
+			st := &site{info: sf.Site, webhooks: append(webflowAPI.Webhooks{}, sf.Webhooks...)}
+			for _, cf := range sf.Collections {
+				c := &collection{info: cf.Collection, site: st}
+				for _, raw := range cf.Items {
+					item, err := object.Decode(raw)
+					if err != nil {
+						panic(fmt.Sprintf("webflowtest: fixture item in collection %s is not an object: %+v", cf.ID, err))
+					}
+					c.items = append(c.items, item)
+				}
+				st.collections = append(st.collections, c)
+				s.collections[c.info.ID] = c
+			}
+			s.sites = append(s.sites, st)
+		}
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// FailNext Respond to the next n requests with the given status code, e.g. 429 or 500.
+func (s *Server) FailNext(status, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := 0; i < n; i++ {
+		s.faults = append(s.faults, status)
+	}
+}
+
+// ResetRateLimit Start a new rate limit window.
+func (s *Server) ResetRateLimit() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.used = 0
+}
+
+// Requests List every request made to the server as "METHOD /path?query".
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string{}, s.requests...)
+}
+
+// Items Current raw JSON of every item in a collection.
+func (s *Server) Items(collectionID string) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.collections[collectionID]
+	if c == nil {
+		return nil
+	}
+
+	items := [][]byte{}
+	for _, item := range c.items {
+		data, _ := json.Marshal(item)
+		items = append(items, data)
+	}
+
+	return items
+}
+
+// Published Number of times a site has been published.
+func (s *Server) Published(siteID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st := s.findSite(siteID); st != nil {
+		return st.published
+	}
+
+	return 0
+}
+
+// serveHTTP Check auth, faults & rate limits then route the request.
+func (s *Server) serveHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+
+	s.requests = append(s.requests, req.Method+" "+req.URL.RequestURI())
+
+	if len(s.faults) > 0 {
+		status := s.faults[0]
+		s.faults = s.faults[1:]
+		s.mu.Unlock()
+		writeError(rw, req, status, http.StatusText(status))
+		return
+	}
+
+	if s.RateLimit > 0 {
+		s.used++
+		remaining := s.RateLimit - s.used
+		if remaining < 0 {
+			remaining = 0
+		}
+		rw.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.RateLimit))
+		rw.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if s.used > s.RateLimit {
+			s.mu.Unlock()
+			writeError(rw, req, http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+	}
+
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") || auth == "Bearer " || (s.Token != "" && auth != "Bearer "+s.Token) {
+		s.mu.Unlock()
+		writeError(rw, req, http.StatusUnauthorized, "Not Authorized")
+		return
+	}
+
+	status, body, deliveries := s.route(req)
+	// Encode the body while the lock is held, as it may be a stored item or collection that other requests change.
+	var data []byte
+	if status < 300 {
+		data, _ = json.Marshal(body)
+	}
+	s.mu.Unlock()
+
+	// Deliver webhooks outside the lock so receivers may call back into the server.
+	for _, d := range deliveries {
+		s.deliver(d)
+	}
+
+	if status >= 300 {
+		writeError(rw, req, status, body.(string))
+		return
+	}
+
+	writeJSON(rw, status, json.RawMessage(data))
+}
+
+// route Dispatch the request by its path. Must be called with the lock held. Returns the status and the response body,
+// or the error message for error statuses.
+func (s *Server) route(req *http.Request) (int, interface{}, []delivery) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	method := req.Method
+
+	switch {
+	case len(parts) == 1 && parts[0] == "sites" && method == http.MethodGet:
+		sites := webflowAPI.Sites{}
+		for _, st := range s.sites {
+			sites = append(sites, st.info)
+		}
+		return http.StatusOK, sites, nil
+
+	case len(parts) >= 2 && parts[0] == "sites":
+		st := s.findSite(parts[1])
+		if st == nil {
+			return http.StatusNotFound, "Site not found", nil
+		}
+		return s.routeSite(req, st, parts[2:])
+
+	case len(parts) >= 2 && parts[0] == "collections":
+		c := s.collections[parts[1]]
+		if c == nil {
+			return http.StatusNotFound, "Collection not found", nil
+		}
+		return s.routeCollection(req, c, parts[2:])
+	}
+
+	return http.StatusNotFound, "Route not found", nil
+}
+
+// routeSite Handle the /sites/:site_id routes.
+func (s *Server) routeSite(req *http.Request, st *site, parts []string) (int, interface{}, []delivery) {
+	method := req.Method
+
+	switch {
+	case len(parts) == 0 && method == http.MethodGet:
+		return http.StatusOK, st.info, nil
+
+	case len(parts) == 1 && parts[0] == "publish" && method == http.MethodPost:
+		payload := struct {
+			Domains []string `json:"domains"`
+		}{}
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			return http.StatusBadRequest, "Invalid request body", nil
+		}
+		st.published++
+		st.info.LastPublished = s.now().UTC()
+		event := map[string]interface{}{
+			"site":        st.info.ID,
+			"publishTime": st.info.LastPublished.UnixNano() / int64(time.Millisecond),
+			"domains":     payload.Domains,
+		}
+		return http.StatusOK, map[string]bool{"queued": true}, s.deliveries(st, webhook.TriggerSitePublish, event)
+
+	case len(parts) == 1 && parts[0] == "collections" && method == http.MethodGet:
+		collections := webflowAPI.Collections{}
+		for _, c := range st.collections {
+			info := c.info
+			// Fields are only reported when requesting a single collection.
+			info.Fields = nil
+			collections = append(collections, info)
+		}
+		return http.StatusOK, collections, nil
+
+	case len(parts) == 1 && parts[0] == "collections" && method == http.MethodPost:
+		definition := webflowAPI.CollectionDefinition{}
+		if err := json.NewDecoder(req.Body).Decode(&definition); err != nil {
+			return http.StatusBadRequest, "Invalid request body", nil
+		}
+		if definition.Name == "" {
+			return http.StatusBadRequest, "ValidationError: name is required", nil
+		}
+		if definition.Slug == "" {
+			definition.Slug = fake.Slugify(definition.Name)
+		}
+		for _, other := range st.collections {
+			if other.info.Slug == definition.Slug {
+				return http.StatusBadRequest, fmt.Sprintf("ValidationError: slug '%s' is already in use", definition.Slug), nil
+			}
+		}
+		now := s.now().UTC()
+		c := &collection{site: st, info: webflowAPI.Collection{
+			ID:           s.newID(),
+			CreatedOn:    now,
+			LastUpdated:  now,
+			Name:         definition.Name,
+			Slug:         definition.Slug,
+			SingularName: definition.SingularName,
+		}}
+		for _, def := range append(fake.BuiltinFields(), definition.Fields...) {
+			field, msg := s.newField(c, def)
+			if msg != "" {
+				return http.StatusBadRequest, msg, nil
+			}
+			c.info.Fields = append(c.info.Fields, field)
+		}
+		st.collections = append(st.collections, c)
+		s.collections[c.info.ID] = c
+		return http.StatusOK, c.info, nil
+
+	case len(parts) == 1 && parts[0] == "webhooks" && method == http.MethodGet:
+		return http.StatusOK, append(webflowAPI.Webhooks{}, st.webhooks...), nil
+
+	case len(parts) == 1 && parts[0] == "webhooks" && method == http.MethodPost:
+		hook := webflowAPI.Webhook{}
+		if err := json.NewDecoder(req.Body).Decode(&hook); err != nil || hook.TriggerType == "" || hook.URL == "" {
+			return http.StatusBadRequest, "ValidationError: triggerType and url are required", nil
+		}
+		hook.ID = s.newID()
+		hook.Site = st.info.ID
+		hook.TriggerID = st.info.ID
+		hook.CreatedOn = s.now().UTC()
+		st.webhooks = append(st.webhooks, hook)
+		return http.StatusOK, hook, nil
+
+	case len(parts) == 2 && parts[0] == "webhooks":
+		for i, hook := range st.webhooks {
+			if hook.ID != parts[1] {
+				continue
+			}
+			switch method {
+			case http.MethodGet:
+				return http.StatusOK, hook, nil
+			case http.MethodDelete:
+				st.webhooks = append(st.webhooks[:i], st.webhooks[i+1:]...)
+				return http.StatusOK, map[string]int{"deleted": 1}, nil
+			}
+		}
+		return http.StatusNotFound, "Webhook not found", nil
+	}
+
+	return http.StatusNotFound, "Route not found", nil
+}
+
+// routeCollection Handle the /collections/:collection_id routes.
+func (s *Server) routeCollection(req *http.Request, c *collection, parts []string) (int, interface{}, []delivery) {
+	method := req.Method
+
+	switch {
+	case len(parts) == 0 && method == http.MethodGet:
+		return http.StatusOK, c.info, nil
+
+	case len(parts) == 0 && method == http.MethodDelete:
+		delete(s.collections, c.info.ID)
+		for i, other := range c.site.collections {
+			if other == c {
+				c.site.collections = append(c.site.collections[:i], c.site.collections[i+1:]...)
+				break
+			}
+		}
+		return http.StatusOK, map[string]int{"deleted": 1}, nil
+
+	case len(parts) == 1 && parts[0] == "fields" && method == http.MethodPost:
+		definition := webflowAPI.FieldDefinition{}
+		if err := json.NewDecoder(req.Body).Decode(&definition); err != nil {
+			return http.StatusBadRequest, "Invalid request body", nil
+		}
+		field, msg := s.newField(c, definition)
+		if msg != "" {
+			return http.StatusBadRequest, msg, nil
+		}
+		c.info.Fields = append(c.info.Fields, field)
+		c.info.LastUpdated = s.now().UTC()
+		return http.StatusOK, field, nil
+
+	case len(parts) == 2 && parts[0] == "fields":
+		i := fake.FindField(&c.info, parts[1])
+		if i < 0 {
+			return http.StatusNotFound, "Field not found", nil
+		}
+		return s.routeField(req, c, i)
+
+	case len(parts) == 1 && parts[0] == "items" && method == http.MethodGet:
+		return http.StatusOK, s.listItems(req, c), nil
+
+	case len(parts) == 1 && parts[0] == "items" && method == http.MethodPost:
+		fields, err := decodeFields(req)
+		if err != nil {
+			return http.StatusBadRequest, err.Error(), nil
+		}
+		item := map[string]interface{}{}
+		for key, val := range fields {
+			item[key] = val
+		}
+		if err := fake.Validate(&c.info, c.items, item, ""); err != nil {
+			return http.StatusBadRequest, err.Error(), nil
+		}
+		now := s.now().UTC().Format(timeFormat)
+		item["_id"] = s.newID()
+		item["_cid"] = c.info.ID
+		item["created-on"] = now
+		item["updated-on"] = now
+		item["published-on"] = nil
+		object.SetDefault(item, "_archived", false)
+		object.SetDefault(item, "_draft", false)
+		if req.URL.Query().Get("live") == "true" {
+			item["published-on"] = now
+		}
+		c.items = append(c.items, item)
+		return http.StatusOK, item, s.deliveries(c.site, webhook.TriggerItemCreated, item)
+
+	case len(parts) == 2 && parts[0] == "items" && parts[1] == "publish" && method == http.MethodPut:
+		payload := struct {
+			ItemIDs []string `json:"itemIds"`
+		}{}
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			return http.StatusBadRequest, "Invalid request body", nil
+		}
+		published := []string{}
+		errs := []string{}
+		for _, id := range payload.ItemIDs {
+			_, item := c.findItem(id)
+			if item == nil {
+				errs = append(errs, fmt.Sprintf("item %s not found", id))
+				continue
+			}
+			if item["_draft"] == true {
+				errs = append(errs, fmt.Sprintf("item %s is a draft", id))
+				continue
+			}
+			item["published-on"] = s.now().UTC().Format(timeFormat)
+			published = append(published, id)
+		}
+		return http.StatusOK, map[string][]string{"publishedItemIds": published, "errors": errs}, nil
+
+	case len(parts) == 2 && parts[0] == "items":
+		i, item := c.findItem(parts[1])
+		if item == nil {
+			return http.StatusNotFound, "Item not found", nil
+		}
+		return s.routeItem(req, c, i, item)
+	}
+
+	return http.StatusNotFound, "Route not found", nil
+}
+
+// routeItem Handle the /collections/:collection_id/items/:item_id routes.
+func (s *Server) routeItem(req *http.Request, c *collection, i int, item map[string]interface{}) (
+	int,
+	interface{},
+	[]delivery,
+) {
+	switch req.Method {
+	case http.MethodGet:
+		return http.StatusOK, map[string]interface{}{
+			"items":  []interface{}{item},
+			"count":  1,
+			"limit":  1,
+			"offset": 0,
+			"total":  1,
+		}, nil
+
+	case http.MethodPut, http.MethodPatch:
+		fields, err := decodeFields(req)
+		if err != nil {
+			return http.StatusBadRequest, err.Error(), nil
+		}
+		updated := map[string]interface{}{}
+		// PATCH only changes the given fields; PUT replaces every field but the item's metadata.
+		for key, val := range item {
+			if req.Method == http.MethodPatch || object.IsMetadata(key) {
+				updated[key] = val
+			}
+		}
+		for key, val := range fields {
+			if key != "_id" && key != "_cid" {
+				updated[key] = val
+			}
+		}
+		if err := fake.Validate(&c.info, c.items, updated, item["_id"].(string)); err != nil {
+			return http.StatusBadRequest, err.Error(), nil
+		}
+		updated["updated-on"] = s.now().UTC().Format(timeFormat)
+		if req.URL.Query().Get("live") == "true" {
+			updated["published-on"] = updated["updated-on"]
+		}
+		c.items[i] = updated
+		return http.StatusOK, updated, s.deliveries(c.site, webhook.TriggerItemChanged, updated)
+
+	case http.MethodDelete:
+		c.items = append(c.items[:i], c.items[i+1:]...)
+		event := map[string]interface{}{"deleted": 1, "itemId": item["_id"]}
+		return http.StatusOK, map[string]int{"deleted": 1}, s.deliveries(c.site, webhook.TriggerItemDeleted, event)
+	}
+
+	return http.StatusNotFound, "Route not found", nil
+}
+
+// routeField Handle the /collections/:collection_id/fields/:field_id routes.
+func (s *Server) routeField(req *http.Request, c *collection, i int) (int, interface{}, []delivery) {
+	field := c.info.Fields[i]
+	builtin := field.Slug == "name" || field.Slug == "slug"
+
+	switch req.Method {
+	case http.MethodPatch:
+		definition := webflowAPI.FieldDefinition{}
+		if err := json.NewDecoder(req.Body).Decode(&definition); err != nil {
+			return http.StatusBadRequest, "Invalid request body", nil
+		}
+		if definition.Type != "" && definition.Type != field.Type {
+			return http.StatusBadRequest, "ValidationError: a field's type cannot be changed", nil
+		}
+		if definition.Slug != "" && definition.Slug != field.Slug {
+			if builtin {
+				return http.StatusBadRequest, fmt.Sprintf("ValidationError: the slug of the %s field cannot be changed", field.Slug), nil
+			}
+			if fake.FindField(&c.info, definition.Slug) >= 0 {
+				return http.StatusBadRequest, fmt.Sprintf("ValidationError: field slug '%s' is already in use", definition.Slug), nil
+			}
+			field.Slug = definition.Slug
+		}
+		if definition.Name != "" {
+			field.Name = definition.Name
+		}
+		field.Required = definition.Required
+		field.HelpText = definition.HelpText
+		field.Validations = definition.Validations
+		c.info.Fields[i] = field
+		c.info.LastUpdated = s.now().UTC()
+		return http.StatusOK, field, nil
+
+	case http.MethodDelete:
+		if builtin {
+			return http.StatusBadRequest, fmt.Sprintf("ValidationError: the %s field cannot be removed", field.Slug), nil
+		}
+		c.info.Fields = append(c.info.Fields[:i], c.info.Fields[i+1:]...)
+		for _, item := range c.items {
+			delete(item, field.Slug)
+		}
+		c.info.LastUpdated = s.now().UTC()
+		return http.StatusOK, map[string]int{"deleted": 1}, nil
+	}
+
+	return http.StatusNotFound, "Route not found", nil
+}
+
+// listItems Respond with a page of items, honoring the offset & limit query params.
+func (s *Server) listItems(req *http.Request, c *collection) *webflowAPI.CollectionItems {
+	offset, _ := strconv.Atoi(req.URL.Query().Get("offset"))
+	limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > maxLimit {
+		limit = maxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	page := []map[string]interface{}{}
+	for i := offset; i < len(c.items) && i < offset+limit; i++ {
+		page = append(page, c.items[i])
+	}
+
+	data, _ := json.Marshal(page)
+
+	return &webflowAPI.CollectionItems{
+		Items:  data,
+		Count:  len(page),
+		Limit:  limit,
+		Offset: offset,
+		Total:  len(c.items),
+	}
+}
+
+// deliveries Prepare a webhook delivery for each of the site's webhooks registered for the trigger type.
+func (s *Server) deliveries(st *site, triggerType string, payload interface{}) []delivery {
+	if s.WebhookSecret == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"triggerType": triggerType,
+		"payload":     payload,
+	})
+	if err != nil {
+		return nil
+	}
+
+	deliveries := []delivery{}
+	for _, hook := range st.webhooks {
+		if hook.TriggerType == triggerType {
+			deliveries = append(deliveries, delivery{url: hook.URL, body: body})
+		}
+	}
+
+	return deliveries
+}
+
+// deliver POST a signed webhook delivery. Failures are ignored, as Webflow would eventually give up too.
+func (s *Server) deliver(d delivery) {
+	timestamp := strconv.FormatInt(s.now().UnixNano()/int64(time.Millisecond), 10)
+
+	req, err := http.NewRequest(http.MethodPost, d.url, bytes.NewReader(d.body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.TimestampHeader, timestamp)
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(s.WebhookSecret, timestamp, d.body))
+
+	client := &http.Client{Timeout: 5 * time.Second}
+	if res, err := client.Do(req); err == nil {
+		res.Body.Close()
+	}
+}
+
+// findSite Find a site by ID. Must be called with the lock held.
+func (s *Server) findSite(id string) *site {
+	for _, st := range s.sites {
+		if st.info.ID == id {
+			return st
+		}
+	}
+
+	return nil
+}
+
+// newID Generate an ID shaped like the ones Webflow uses. Must be called with the lock held.
+func (s *Server) newID() string {
+	id := fake.NewID(s.nextID)
+	s.nextID++
+	return id
+}
+
+// newField Create a field of the collection from its definition. Returns the error message, if any. Must be called with
+// the lock held.
+func (s *Server) newField(c *collection, definition webflowAPI.FieldDefinition) (webflowAPI.CollectionField, string) {
+	if definition.Name == "" || definition.Type == "" {
+		return webflowAPI.CollectionField{}, "ValidationError: a field's name & type are required"
+	}
+	if definition.Slug == "" {
+		definition.Slug = fake.Slugify(definition.Name)
+	}
+	if fake.FindField(&c.info, definition.Slug) >= 0 {
+		return webflowAPI.CollectionField{}, fmt.Sprintf("ValidationError: field slug '%s' is already in use", definition.Slug)
+	}
+
+	return webflowAPI.CollectionField{
+		ID:          s.newID(),
+		Name:        definition.Name,
+		Slug:        definition.Slug,
+		Type:        definition.Type,
+		Required:    definition.Required,
+		Editable:    true,
+		HelpText:    definition.HelpText,
+		Validations: definition.Validations,
+	}, ""
+}
+
+// findItem Find an item, and its index, by ID.
+func (c *collection) findItem(id string) (int, map[string]interface{}) {
+	for i, item := range c.items {
+		if item["_id"] == id {
+			return i, item
+		}
+	}
+
+	return -1, nil
+}
+
+// decodeFields Decode the `fields` of an item write request.
+func decodeFields(req *http.Request) (map[string]interface{}, error) {
+	body := struct {
+		Fields json.RawMessage `json:"fields"`
+	}{}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil || len(body.Fields) == 0 {
+		return nil, fmt.Errorf("ValidationError: request body must contain `fields`")
+	}
+
+	return object.Decode(body.Fields)
+}
+
+// writeJSON Respond with the body encoded as JSON.
+func writeJSON(rw http.ResponseWriter, status int, body interface{}) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(body)
+}
+
+// writeError Respond with an error shaped like Webflow's.
+func writeError(rw http.ResponseWriter, req *http.Request, status int, msg string) {
+	name := strings.Replace(http.StatusText(status), " ", "", -1)
+	writeJSON(rw, status, &webflowAPI.GeneralError{
+		Msg:  msg,
+		Code: status,
+		Name: name,
+		Path: req.URL.Path,
+		Err:  name + ": " + msg,
+	})
+}
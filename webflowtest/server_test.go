@@ -0,0 +1,319 @@
+package webflowtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI"
+	"github.com/redeemed2011/webflowAPI/webhook"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	siteID = "mysiteid"
+)
+
+// newTestServer Start a server seeded from the test fixture.
+func newTestServer(t *testing.T) *Server {
+	fixture, err := LoadFixture("testdata/fixture.json")
+	if err != nil {
+		t.Fatalf("LoadFixture() is expected to read the test fixture: %+v", err)
+	}
+
+	return NewServer(fixture)
+}
+
+// doJSON Make a raw request to the server, decoding the response into res.
+func doJSON(t *testing.T, server *Server, method, uri string, body, res interface{}) int {
+	data, _ := json.Marshal(body)
+	req, _ := http.NewRequest(method, server.URL+uri, bytes.NewReader(data))
+	req.Header.Set("Authorization", "Bearer mytoken")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request to %s failed: %+v", uri, err)
+	}
+	defer resp.Body.Close()
+
+	if res != nil {
+		json.NewDecoder(resp.Body).Decode(res)
+	}
+
+	return resp.StatusCode
+}
+
+func TestCollections(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	api := webflowAPI.New("mytoken", siteID, server.Client())
+	api.BaseURL = server.URL
+
+	collection, err := api.GetCollectionBySlug("posts")
+	if err != nil || collection == nil || collection.ID != "posts1" {
+		t.Fatalf("GetCollectionBySlug() is expected to find the fixture collection! Got %+v; error %+v.", collection, err)
+	}
+
+	if len(collection.Fields) != 0 {
+		t.Errorf("The collections list is not expected to include fields! Got %+v.", collection.Fields)
+	}
+
+	single := &webflowAPI.Collection{}
+	if status := doJSON(t, server, http.MethodGet, "/collections/posts1", nil, single); status != http.StatusOK {
+		t.Errorf("GET /collections/:id is expected to succeed! Got status %d.", status)
+	}
+
+	if len(single.Fields) != 5 {
+		t.Errorf("GET /collections/:id is expected to include the fields! Got %+v.", single.Fields)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	fixture := &Fixture{Sites: []SiteFixture{{Site: webflowAPI.Site{ID: siteID}}}}
+	collection := CollectionFixture{Collection: webflowAPI.Collection{ID: "many", Name: "Many", Slug: "many"}}
+	for i := 0; i < 250; i++ {
+		collection.Items = append(collection.Items, json.RawMessage(fmt.Sprintf(`{"_id":"%d","name":"%d"}`, i, i)))
+	}
+	fixture.Sites[0].Collections = append(fixture.Sites[0].Collections, collection)
+
+	server := NewServer(fixture)
+	defer server.Close()
+
+	api := webflowAPI.New("mytoken", siteID, server.Client())
+	api.BaseURL = server.URL
+
+	{
+		items, err := api.GetAllItemsInCollectionByID("many", 10)
+		if err != nil || len(items) != 250 {
+			t.Errorf("GetAllItemsInCollectionByID() is expected to page through all 250 items! Got %d; error %+v.", len(items), err)
+		}
+
+		requests := server.Requests()
+		if len(requests) != 3 || !strings.Contains(requests[2], "offset=200") {
+			t.Errorf("GetAllItemsInCollectionByID() is expected to request three pages! Got %+v.", requests)
+		}
+	}
+	{
+		items, _ := api.GetAllItemsInCollectionByID("many", 0)
+		if len(items) != 100 {
+			t.Errorf("GetAllItemsInCollectionByID() is expected to stop after maxPages! Got %d items.", len(items))
+		}
+	}
+}
+
+func TestFailures(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	{
+		server.Token = "right"
+		api := webflowAPI.New("wrong", siteID, server.Client())
+		api.BaseURL = server.URL
+
+		_, err := api.GetAllCollections()
+		if err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+			t.Errorf("The server is expected to reject the wrong token! Got error %+v.", err)
+		}
+		server.Token = ""
+	}
+	{
+		api := webflowAPI.New("mytoken", siteID, server.Client())
+		api.BaseURL = server.URL
+		api.Client.Backoff = func(retry int) time.Duration {
+			return time.Millisecond
+		}
+
+		server.FailNext(http.StatusTooManyRequests, 2)
+		server.FailNext(http.StatusInternalServerError, 1)
+		collections, err := api.GetAllCollections()
+		if err != nil || len(*collections) != 2 {
+			t.Errorf("The client is expected to retry past injected failures! Got %+v; error %+v.", collections, err)
+		}
+	}
+	{
+		server.RateLimit = 1
+		api := webflowAPI.New("mytoken", siteID, nil)
+		api.BaseURL = server.URL
+		api.Client.MaxRetries = 1
+
+		if _, err := api.GetAllCollections(); err != nil {
+			t.Errorf("The first request is expected to be within the rate limit: %+v", err)
+		}
+
+		if _, err := api.GetAllCollections(); err == nil {
+			t.Error("The second request is expected to exceed the rate limit.")
+		}
+
+		server.ResetRateLimit()
+		if _, err := api.GetAllCollections(); err != nil {
+			t.Errorf("ResetRateLimit() is expected to start a new window: %+v", err)
+		}
+	}
+}
+
+func TestWritesAndWebhooks(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	server.WebhookSecret = "mysecret"
+
+	created := []webhook.ItemEvent{}
+	receiver := webhook.New("mysecret")
+	receiver.OnItemCreated(func(ev webhook.ItemEvent) {
+		created = append(created, ev)
+	})
+	hookServer := httptest.NewServer(receiver)
+	defer hookServer.Close()
+
+	hook := &webflowAPI.Webhook{}
+	doJSON(t, server, http.MethodPost, "/sites/mysiteid/webhooks", map[string]string{
+		"triggerType": webhook.TriggerItemCreated,
+		"url":         hookServer.URL,
+	}, hook)
+
+	{
+		status := doJSON(t, server, http.MethodPost, "/collections/posts1/items", map[string]interface{}{
+			"fields": map[string]interface{}{"name": "No author", "slug": "no-author"},
+		}, nil)
+		if status != http.StatusBadRequest {
+			t.Errorf("Creating an item without a required field is expected to fail! Got status %d.", status)
+		}
+	}
+	{
+		item := map[string]interface{}{}
+		status := doJSON(t, server, http.MethodPost, "/collections/posts1/items?live=true", map[string]interface{}{
+			"fields": map[string]interface{}{"name": "New", "slug": "new", "author": "a2"},
+		}, &item)
+		if status != http.StatusOK || item["_id"] == "" || item["published-on"] == nil {
+			t.Errorf("Creating an item is expected to succeed! Got status %d; item %+v.", status, item)
+		}
+
+		if len(created) != 1 || created[0].Slug != "new" {
+			t.Errorf("Creating an item is expected to deliver a signed webhook! Got %+v.", created)
+		}
+
+		patched := map[string]interface{}{}
+		doJSON(t, server, http.MethodPatch, fmt.Sprintf("/collections/posts1/items/%s", item["_id"]), map[string]interface{}{
+			"fields": map[string]interface{}{"post-summary": "Patched"},
+		}, &patched)
+		if patched["post-summary"] != "Patched" || patched["author"] != "a2" {
+			t.Errorf("Patching an item is expected to merge the fields! Got %+v.", patched)
+		}
+
+		deleted := map[string]int{}
+		doJSON(t, server, http.MethodDelete, fmt.Sprintf("/collections/posts1/items/%s", item["_id"]), nil, &deleted)
+		if deleted["deleted"] != 1 || len(server.Items("posts1")) != 3 {
+			t.Errorf("Deleting an item is expected to remove it! Got %+v.", deleted)
+		}
+	}
+	{
+		res := webflowAPI.PublishedItems{}
+		doJSON(t, server, http.MethodPut, "/collections/posts1/items/publish", map[string][]string{"itemIds": {"p1", "p2"}}, &res)
+		if len(res.PublishedItemIDs) != 1 || res.PublishedItemIDs[0] != "p1" || len(res.Errors) != 1 {
+			t.Errorf("Publishing items is expected to publish all but drafts! Got %+v.", res)
+		}
+	}
+	{
+		doJSON(t, server, http.MethodPost, "/sites/mysiteid/publish", map[string][]string{"domains": {"example.com"}}, nil)
+		if server.Published(siteID) != 1 {
+			t.Errorf("Publishing the site is expected to be recorded! Got %d.", server.Published(siteID))
+		}
+	}
+}
+
+func TestConcurrentWrites(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	api := webflowAPI.New("mytoken", siteID, server.Client())
+	api.BaseURL = server.URL
+
+	// Items are created & published from several clients at once; run with -race to check responses are not encoded
+	// while other requests change the items.
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				slug := fmt.Sprintf("post-%d-%d", i, j)
+				item, err := api.CreateItem("posts1", map[string]interface{}{"name": slug, "slug": slug, "author": "a2", "_draft": false}, false)
+				if err != nil {
+					t.Errorf("CreateItem() is expected to succeed! Got %+v.", err)
+					return
+				}
+				// Publish this item & p1 while other clients read them.
+				id := gjson.GetBytes(item, "_id").String()
+				if _, err := api.PublishItems("posts1", []string{id, "p1"}); err != nil {
+					t.Errorf("PublishItems() is expected to succeed! Got %+v.", err)
+					return
+				}
+				if status := doJSON(t, server, http.MethodGet, "/collections/posts1/items/p1", nil, nil); status != http.StatusOK {
+					t.Errorf("GET /collections/:id/items/:id is expected to succeed! Got status %d.", status)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if items := server.Items("posts1"); len(items) != 83 {
+		t.Errorf("Every concurrent write is expected to be kept! Got %d items.", len(items))
+	}
+}
+
+func TestCollectionManagement(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	api := webflowAPI.New("mytoken", siteID, server.Client())
+	api.BaseURL = server.URL
+
+	collection, err := api.CreateCollection(webflowAPI.CollectionDefinition{
+		Name:         "Blog Posts",
+		SingularName: "Blog Post",
+		Fields:       []webflowAPI.FieldDefinition{{Name: "Views", Type: webflowAPI.FieldTypeNumber}},
+	})
+	if err != nil || collection.Slug != "blog-posts" || len(collection.Fields) != 3 {
+		t.Fatalf("CreateCollection() is expected to create the collection with its fields! Got %+v; error %+v.", collection, err)
+	}
+	if found, _ := api.GetCollectionBySlug("blog-posts"); found == nil || found.ID != collection.ID {
+		t.Errorf("The created collection is expected to be listed! Got %+v.", found)
+	}
+
+	field, err := api.CreateField(collection.ID, webflowAPI.FieldDefinition{Name: "Views", Type: webflowAPI.FieldTypeNumber})
+	if err == nil || !strings.Contains(err.Error(), "already in use") {
+		t.Errorf("CreateField() is expected to reject a slug in use! Got %+v; error %+v.", field, err)
+	}
+	field, err = api.CreateField(collection.ID, webflowAPI.FieldDefinition{Name: "Summary", Type: webflowAPI.FieldTypePlainText})
+	if err != nil || field.Slug != "summary" {
+		t.Fatalf("CreateField() is expected to add the field! Got %+v; error %+v.", field, err)
+	}
+
+	field, err = api.UpdateField(collection.ID, field.ID, webflowAPI.FieldDefinition{Name: "Excerpt", Slug: "excerpt", Required: true})
+	if err != nil || field.Slug != "excerpt" || !field.Required {
+		t.Errorf("UpdateField() is expected to update the field! Got %+v; error %+v.", field, err)
+	}
+	if _, err := api.UpdateField(collection.ID, field.ID, webflowAPI.FieldDefinition{Type: webflowAPI.FieldTypeBool}); err == nil {
+		t.Error("UpdateField() is expected to reject a change of type!")
+	}
+
+	if err := api.DeleteField(collection.ID, field.ID); err != nil {
+		t.Errorf("DeleteField() is expected to remove the field: %+v", err)
+	}
+	if err := api.DeleteField(collection.ID, collection.Fields[1].ID); err == nil {
+		t.Error("DeleteField() is expected to keep the slug field!")
+	}
+
+	if err := api.DeleteCollection(collection.ID); err != nil {
+		t.Errorf("DeleteCollection() is expected to remove the collection: %+v", err)
+	}
+	if _, err := api.GetCollectionByID(collection.ID); err == nil {
+		t.Error("The deleted collection is expected to be gone!")
+	}
+}
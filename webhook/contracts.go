@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"encoding/json"
+)
+
+// Event Webhook delivery envelope as sent by Webflow, before the payload is decoded.
+type Event struct {
+	TriggerType string          `json:"triggerType"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// ItemEvent Payload for the collection item triggers (created, changed, deleted & unpublished).
+type ItemEvent struct {
+	TriggerType  string `json:"-"`
+	ID           string `json:"_id"`
+	CollectionID string `json:"_cid"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	Archived     bool   `json:"_archived"`
+	Draft        bool   `json:"_draft"`
+	// Item The raw JSON of the item so the caller may decode it however is desired. Deletions only carry the ID.
+	Item json.RawMessage `json:"-"`
+}
+
+// FormSubmissionEvent Payload for the form submission trigger.
+type FormSubmissionEvent struct {
+	ID          string                 `json:"_id"`
+	Name        string                 `json:"name"`
+	Site        string                 `json:"site"`
+	Data        map[string]interface{} `json:"data"`
+	SubmittedOn string                 `json:"d"`
+}
+
+// SitePublishEvent Payload for the site publish trigger.
+type SitePublishEvent struct {
+	Site        string   `json:"site"`
+	PublishTime int64    `json:"publishTime"`
+	Domains     []string `json:"domains"`
+	PublishedBy struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"publishedBy"`
+}
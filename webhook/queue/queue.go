@@ -0,0 +1,314 @@
+// Package queue File-backed queue for received webhook events. Events are written to disk before the delivery is
+// acknowledged to Webflow and are only removed once a consumer has processed them, so every event is processed at least
+// once even when the process crashes mid-processing. Events that keep failing are moved to dead-letter storage, from
+// which they may be replayed. Files in the pending queue that cannot be decoded are moved to dead-letter storage with an
+// .invalid suffix, for inspection, so they do not stop the queue.
+//
+// The queue expects a single consumer; run one Queue.Run per directory. Dead-lettered events may be listed & replayed
+// with the webflow command: webflow queue dead <dir> & webflow queue replay <dir> [event ID]...
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI/webhook"
+	"github.com/sethgrid/pester"
+)
+
+const (
+	pendingDir = "pending"
+	deadDir    = "dead"
+	tmpDir     = "tmp"
+
+	// DefaultMaxAttempts Number of times an event is attempted before it is moved to dead-letter storage.
+	DefaultMaxAttempts = 10
+	// DefaultPollInterval How often Run looks for events that are due.
+	DefaultPollInterval = 1 * time.Second
+)
+
+// Record An event along with its delivery bookkeeping.
+type Record struct {
+	ID          string        `json:"id"`
+	Event       webhook.Event `json:"event"`
+	ReceivedAt  time.Time     `json:"receivedAt"`
+	Attempts    int           `json:"attempts"`
+	NextAttempt time.Time     `json:"nextAttempt"`
+	LastError   string        `json:"lastError,omitempty"`
+}
+
+// Queue Durable queue of webhook events stored in a directory.
+type Queue struct {
+	// MaxAttempts Number of failed attempts before an event is dead-lettered.
+	MaxAttempts int
+	// Backoff Delay before the given retry of an event.
+	Backoff func(retry int) time.Duration
+	// PollInterval How often Run looks for events that are due.
+	PollInterval time.Duration
+
+	dir string
+	// mu Guards the files. It is not held while events are handled, so Enqueue is not kept waiting by slow handlers.
+	mu sync.Mutex
+	// processing Serializes passes of Process.
+	processing sync.Mutex
+	// Override for the clock. Use only for internal testing of the pkg.
+	now func() time.Time
+}
+
+// New Create a queue stored in the given directory, creating the directory when necessary.
+func New(dir string) (*Queue, error) {
+	for _, sub := range []string{pendingDir, deadDir, tmpDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return nil, fmt.Errorf("unable to create the queue directory; error: %+v", err)
+		}
+	}
+
+	return &Queue{
+		MaxAttempts:  DefaultMaxAttempts,
+		Backoff:      pester.ExponentialBackoff,
+		PollInterval: DefaultPollInterval,
+		dir:          dir,
+		now:          time.Now,
+	}, nil
+}
+
+// Enqueue Durably store an event. Suitable for use as webhook.Handler.Receive.
+func (q *Queue) Enqueue(event webhook.Event) error {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+
+	now := q.now()
+	record := &Record{
+		// Prefixing with the time keeps the directory listing in the order events were received.
+		ID:          fmt.Sprintf("%020d-%s", now.UnixNano(), hex.EncodeToString(suffix)),
+		Event:       event,
+		ReceivedAt:  now,
+		NextAttempt: now,
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.write(pendingDir, record)
+}
+
+// Process Make one pass over the pending events that are due, handing each to the handle func. Events are removed once
+// handle returns nil; otherwise they are scheduled for a retry or, after MaxAttempts, moved to dead-letter storage.
+// Returns the number of events successfully processed.
+func (q *Queue) Process(handle func(webhook.Event) error) (int, error) {
+	q.processing.Lock()
+	defer q.processing.Unlock()
+
+	q.mu.Lock()
+	records, err := q.list(pendingDir)
+	q.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, record := range records {
+		if record.NextAttempt.After(q.now()) {
+			continue
+		}
+
+		handleErr := handle(record.Event)
+
+		q.mu.Lock()
+		err := q.settle(record, handleErr)
+		q.mu.Unlock()
+		if err != nil {
+			return processed, err
+		}
+		if handleErr == nil {
+			processed++
+		}
+	}
+
+	return processed, nil
+}
+
+// Run Process events until the context is done.
+func (q *Queue) Run(ctx context.Context, handle func(webhook.Event) error) error {
+	ticker := time.NewTicker(q.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := q.Process(handle); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Pending List the events waiting to be processed, oldest first.
+func (q *Queue) Pending() ([]*Record, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.list(pendingDir)
+}
+
+// Dead List the events in dead-letter storage, oldest first.
+func (q *Queue) Dead() ([]*Record, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.list(deadDir)
+}
+
+// Replay Move dead-lettered events back to the pending queue with their attempts reset. Replays every dead-lettered
+// event when no IDs are given. Returns the number of events replayed.
+func (q *Queue) Replay(ids ...string) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	records, err := q.list(deadDir)
+	if err != nil {
+		return 0, err
+	}
+
+	wanted := map[string]bool{}
+	for _, id := range ids {
+		wanted[id] = true
+	}
+
+	replayed := 0
+	for _, record := range records {
+		if len(wanted) > 0 && !wanted[record.ID] {
+			continue
+		}
+
+		record.Attempts = 0
+		record.NextAttempt = q.now()
+		if err := q.move(record, deadDir, pendingDir); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+// settle Remove a handled event, or schedule its retry, or move it to dead-letter storage after MaxAttempts.
+func (q *Queue) settle(record *Record, handleErr error) error {
+	if handleErr == nil {
+		// Acknowledge the event.
+		if err := os.Remove(q.path(pendingDir, record.ID)); err != nil {
+			return fmt.Errorf("unable to acknowledge event %s; error: %+v", record.ID, err)
+		}
+		return nil
+	}
+
+	record.LastError = handleErr.Error()
+	record.Attempts++
+	if record.Attempts >= q.MaxAttempts {
+		return q.move(record, pendingDir, deadDir)
+	}
+
+	record.NextAttempt = q.now().Add(q.Backoff(record.Attempts))
+	return q.write(pendingDir, record)
+}
+
+// path Location of a record's file.
+func (q *Queue) path(sub, id string) string {
+	return filepath.Join(q.dir, sub, id+".json")
+}
+
+// write Atomically store a record: write to a temp file, sync it then rename it into place.
+func (q *Queue) write(sub string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Join(q.dir, tmpDir), record.ID)
+	if err != nil {
+		return fmt.Errorf("unable to store event %s; error: %+v", record.ID, err)
+	}
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to store event %s; error: %+v", record.ID, err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to store event %s; error: %+v", record.ID, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("unable to store event %s; error: %+v", record.ID, err)
+	}
+
+	return os.Rename(tmp.Name(), q.path(sub, record.ID))
+}
+
+// move Store the record in the destination then remove it from the source.
+func (q *Queue) move(record *Record, from, to string) error {
+	if err := q.write(to, record); err != nil {
+		return err
+	}
+
+	return os.Remove(q.path(from, record.ID))
+}
+
+// list Read every record in the given sub directory, oldest first.
+func (q *Queue) list(sub string) ([]*Record, error) {
+	files, err := ioutil.ReadDir(filepath.Join(q.dir, sub))
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".json") {
+			names = append(names, file.Name())
+		}
+	}
+	sort.Strings(names)
+
+	records := []*Record{}
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join(q.dir, sub, name))
+		if err != nil {
+			return nil, err
+		}
+
+		record := &Record{}
+		if err := json.Unmarshal(data, record); err != nil {
+			if sub != pendingDir {
+				return nil, fmt.Errorf("unable to decode queued event %s; error: %+v", name, err)
+			}
+			// Set the file aside so it does not stop every later pass.
+			if err := os.Rename(filepath.Join(q.dir, sub, name), filepath.Join(q.dir, deadDir, name+".invalid")); err != nil {
+				return nil, fmt.Errorf("unable to set aside undecodable event %s; error: %+v", name, err)
+			}
+			continue
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,220 @@
+package queue
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/redeemed2011/webflowAPI/webhook"
+)
+
+var (
+	exampleEvent1 = webhook.Event{
+		TriggerType: webhook.TriggerItemChanged,
+		Payload:     []byte(`{"_id":"1"}`),
+	}
+	exampleEvent2 = webhook.Event{
+		TriggerType: webhook.TriggerItemCreated,
+		Payload:     []byte(`{"_id":"2"}`),
+	}
+)
+
+// newTestQueue Create a queue in a temp dir with a clock the test controls.
+func newTestQueue(t *testing.T, now *time.Time) (*Queue, func()) {
+	dir, err := ioutil.TempDir("", "webflow-queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := New(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.now = func() time.Time {
+		return *now
+	}
+	q.Backoff = func(retry int) time.Duration {
+		return time.Minute
+	}
+
+	return q, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+func TestProcess(t *testing.T) {
+	now := time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+	q, cleanup := newTestQueue(t, &now)
+	defer cleanup()
+
+	if err := q.Enqueue(exampleEvent1); err != nil {
+		t.Fatalf("Enqueue() is expected to store the event: %+v", err)
+	}
+	now = now.Add(time.Millisecond)
+	if err := q.Enqueue(exampleEvent2); err != nil {
+		t.Fatalf("Enqueue() is expected to store the event: %+v", err)
+	}
+
+	// Fail the first event; succeed the second.
+	seen := []string{}
+	processed, err := q.Process(func(ev webhook.Event) error {
+		seen = append(seen, ev.TriggerType)
+		if ev.TriggerType == webhook.TriggerItemChanged {
+			return errors.New("crashed")
+		}
+		return nil
+	})
+
+	if err != nil || processed != 1 {
+		t.Errorf("Process() is expected to process one event without error! Processed %d; error %+v.", processed, err)
+	}
+
+	if len(seen) != 2 || seen[0] != webhook.TriggerItemChanged {
+		t.Errorf("Process() is expected to handle the events in the order received! Got %+v.", seen)
+	}
+
+	pending, _ := q.Pending()
+	if len(pending) != 1 || pending[0].Attempts != 1 || pending[0].LastError != "crashed" {
+		t.Errorf("Process() is expected to keep the failed event for a retry! Got %+v.", pending)
+	}
+
+	// The retry is not due until the backoff has passed.
+	processed, _ = q.Process(func(ev webhook.Event) error {
+		t.Error("Process() is not expected to retry an event before its backoff has passed.")
+		return nil
+	})
+	if processed != 0 {
+		t.Errorf("Process() is expected to skip events that are not due! Processed %d.", processed)
+	}
+
+	now = now.Add(2 * time.Minute)
+	processed, _ = q.Process(func(ev webhook.Event) error {
+		return nil
+	})
+	if processed != 1 {
+		t.Errorf("Process() is expected to retry the event once the backoff has passed! Processed %d.", processed)
+	}
+
+	pending, _ = q.Pending()
+	if len(pending) != 0 {
+		t.Errorf("Process() is expected to acknowledge processed events! %d remain.", len(pending))
+	}
+}
+
+func TestDeadLetterAndReplay(t *testing.T) {
+	now := time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+	q, cleanup := newTestQueue(t, &now)
+	defer cleanup()
+	q.MaxAttempts = 2
+
+	q.Enqueue(exampleEvent1)
+
+	for i := 0; i < 2; i++ {
+		q.Process(func(ev webhook.Event) error {
+			return errors.New("still broken")
+		})
+		now = now.Add(2 * time.Minute)
+	}
+
+	dead, _ := q.Dead()
+	if len(dead) != 1 || dead[0].Attempts != 2 {
+		t.Errorf("Process() is expected to dead-letter an event after MaxAttempts! Got %+v.", dead)
+	}
+
+	pending, _ := q.Pending()
+	if len(pending) != 0 {
+		t.Errorf("Process() is expected to remove dead-lettered events from the pending queue! Got %+v.", pending)
+	}
+
+	replayed, err := q.Replay()
+	if err != nil || replayed != 1 {
+		t.Errorf("Replay() is expected to replay the dead-lettered event! Replayed %d; error %+v.", replayed, err)
+	}
+
+	processed, _ := q.Process(func(ev webhook.Event) error {
+		return nil
+	})
+	if processed != 1 {
+		t.Errorf("Process() is expected to process replayed events! Processed %d.", processed)
+	}
+
+	dead, _ = q.Dead()
+	if len(dead) != 0 {
+		t.Errorf("Replay() is expected to empty the dead-letter storage! Got %+v.", dead)
+	}
+}
+
+func TestDurability(t *testing.T) {
+	now := time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+	q, cleanup := newTestQueue(t, &now)
+	defer cleanup()
+
+	q.Enqueue(exampleEvent1)
+
+	// A new queue over the same directory, as after a crash, still sees the event.
+	q2, err := New(q.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pending, _ := q2.Pending()
+	if len(pending) != 1 || string(pending[0].Event.Payload) != string(exampleEvent1.Payload) {
+		t.Errorf("New() is expected to pick up events stored by a previous queue! Got %+v.", pending)
+	}
+}
+
+func TestEnqueueWhileProcessing(t *testing.T) {
+	now := time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+	q, cleanup := newTestQueue(t, &now)
+	defer cleanup()
+
+	q.Enqueue(exampleEvent1)
+
+	// Events received while a handler runs are stored without waiting for it.
+	enqueued := make(chan error, 1)
+	processed, err := q.Process(func(event webhook.Event) error {
+		go func() {
+			enqueued <- q.Enqueue(exampleEvent2)
+		}()
+		select {
+		case err := <-enqueued:
+			return err
+		case <-time.After(time.Second):
+			return errors.New("Enqueue() waited for the handler")
+		}
+	})
+	if err != nil || processed != 1 {
+		t.Errorf("Enqueue() is expected not to wait for handlers! Processed %d; error %+v.", processed, err)
+	}
+
+	pending, _ := q.Pending()
+	if len(pending) != 1 || string(pending[0].Event.Payload) != string(exampleEvent2.Payload) {
+		t.Errorf("Enqueue() is expected to store the event received while processing! Got %+v.", pending)
+	}
+}
+
+func TestProcessInvalid(t *testing.T) {
+	now := time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+	q, cleanup := newTestQueue(t, &now)
+	defer cleanup()
+
+	q.Enqueue(exampleEvent1)
+	if err := ioutil.WriteFile(filepath.Join(q.dir, pendingDir, "0-bad.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	processed, err := q.Process(func(event webhook.Event) error { return nil })
+	if err != nil || processed != 1 {
+		t.Errorf("Process() is expected to process the events around an undecodable file! Processed %d; error %+v.", processed, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(q.dir, deadDir, "0-bad.json.invalid")); err != nil {
+		t.Errorf("Process() is expected to move the undecodable file to dead-letter storage! Error %+v.", err)
+	}
+	if dead, err := q.Dead(); err != nil || len(dead) != 0 {
+		t.Errorf("Dead() is expected to leave out undecodable files! Got %+v; error %+v.", dead, err)
+	}
+}
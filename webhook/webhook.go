@@ -0,0 +1,281 @@
+// Package webhook Receive Webflow webhook deliveries. The Handler verifies each delivery's signature & timestamp then
+// decodes the payload by its trigger type and hands it to the registered callbacks.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// SignatureHeader Header containing the hex encoded HMAC-SHA256 of the delivery.
+	SignatureHeader = "x-webflow-signature"
+	// TimestampHeader Header containing the time, in milliseconds since the epoch, the delivery was signed.
+	TimestampHeader = "x-webflow-timestamp"
+
+	// DefaultTolerance How far a delivery's timestamp may drift from the local clock before it is considered a replay.
+	DefaultTolerance = 5 * time.Minute
+	// DefaultMaxBodyBytes Largest request body the handler will read.
+	DefaultMaxBodyBytes = 1 << 20
+
+	// Trigger types Webflow reports in the delivery envelope.
+	TriggerFormSubmission  = "form_submission"
+	TriggerSitePublish     = "site_publish"
+	TriggerItemCreated     = "collection_item_created"
+	TriggerItemChanged     = "collection_item_changed"
+	TriggerItemDeleted     = "collection_item_deleted"
+	TriggerItemUnpublished = "collection_item_unpublished"
+)
+
+var (
+	// ErrMissingSignature The delivery did not carry a signature or timestamp header.
+	ErrMissingSignature = errors.New("webhook signature or timestamp header is missing")
+	// ErrInvalidSignature The delivery's signature does not match its body.
+	ErrInvalidSignature = errors.New("webhook signature does not match")
+	// ErrTimestampOutOfRange The delivery's timestamp is outside the tolerance window; it is likely a replay.
+	ErrTimestampOutOfRange = errors.New("webhook timestamp is outside the tolerance window")
+)
+
+// Handler http.Handler that verifies & dispatches Webflow webhook deliveries.
+type Handler struct {
+	// Tolerance Maximum difference between a delivery's timestamp and the local clock. Zero disables the check.
+	Tolerance time.Duration
+	// MaxBodyBytes Largest request body to read.
+	MaxBodyBytes int64
+	// Receive When set, verified deliveries are handed to this func rather than dispatched directly. Useful for handing
+	// the event to a queue. Returning an error responds with a server error so Webflow retries the delivery.
+	Receive func(Event) error
+
+	secret []byte
+	// Override for the clock. Use only for internal testing of the pkg.
+	now func() time.Time
+
+	mu                sync.RWMutex
+	onEvent           []func(Event)
+	onItemCreated     []func(ItemEvent)
+	onItemChanged     []func(ItemEvent)
+	onItemDeleted     []func(ItemEvent)
+	onItemUnpublished []func(ItemEvent)
+	onFormSubmission  []func(FormSubmissionEvent)
+	onSitePublish     []func(SitePublishEvent)
+}
+
+// New Create a new webhook handler that verifies deliveries with the given secret.
+func New(secret string) *Handler {
+	return &Handler{
+		Tolerance:    DefaultTolerance,
+		MaxBodyBytes: DefaultMaxBodyBytes,
+		secret:       []byte(secret),
+		now:          time.Now,
+	}
+}
+
+// Sign Compute the signature Webflow sends for the given timestamp and body.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + ":"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify Check the signature & timestamp of a delivery.
+func (h *Handler) Verify(body []byte, timestamp, signature string) error {
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	ms, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("webhook timestamp is not a number: %+v", err)
+	}
+
+	expected := Sign(string(h.secret), timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	// Only consider the timestamp once the signature proves it was not tampered with.
+	if h.Tolerance > 0 {
+		drift := h.now().Sub(time.Unix(0, ms*int64(time.Millisecond)))
+		if drift < 0 {
+			drift = -drift
+		}
+		if drift > h.Tolerance {
+			return ErrTimestampOutOfRange
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP Verify the delivery then hand it off to Receive, or dispatch it to the registered callbacks.
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(rw, req.Body, h.MaxBodyBytes))
+	if err != nil {
+		http.Error(rw, "unable to read the request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Verify(body, req.Header.Get(TimestampHeader), req.Header.Get(SignatureHeader)); err != nil {
+		http.Error(rw, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	event := Event{}
+	if err := json.Unmarshal(body, &event); err != nil || event.TriggerType == "" {
+		http.Error(rw, "unrecognized webhook payload", http.StatusBadRequest)
+		return
+	}
+
+	if h.Receive != nil {
+		err = h.Receive(event)
+	} else {
+		err = h.Dispatch(event)
+	}
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.WriteHeader(http.StatusOK)
+}
+
+// Dispatch Decode the event's payload by its trigger type and call the matching callbacks. Unknown trigger types are
+// only passed to the OnEvent callbacks.
+func (h *Handler) Dispatch(event Event) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, fn := range h.onEvent {
+		fn(event)
+	}
+
+	switch event.TriggerType {
+	case TriggerItemCreated:
+		return dispatchItem(event, h.onItemCreated)
+	case TriggerItemChanged:
+		return dispatchItem(event, h.onItemChanged)
+	case TriggerItemDeleted:
+		return dispatchItem(event, h.onItemDeleted)
+	case TriggerItemUnpublished:
+		return dispatchItem(event, h.onItemUnpublished)
+	case TriggerFormSubmission:
+		if len(h.onFormSubmission) == 0 {
+			return nil
+		}
+		payload := FormSubmissionEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unable to decode the %s payload: %+v", event.TriggerType, err)
+		}
+		for _, fn := range h.onFormSubmission {
+			fn(payload)
+		}
+	case TriggerSitePublish:
+		if len(h.onSitePublish) == 0 {
+			return nil
+		}
+		payload := SitePublishEvent{}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("unable to decode the %s payload: %+v", event.TriggerType, err)
+		}
+		for _, fn := range h.onSitePublish {
+			fn(payload)
+		}
+	}
+
+	return nil
+}
+
+// dispatchItem Decode an item payload and call each of the callbacks with it.
+func dispatchItem(event Event, callbacks []func(ItemEvent)) error {
+	if len(callbacks) == 0 {
+		return nil
+	}
+
+	payload := ItemEvent{}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("unable to decode the %s payload: %+v", event.TriggerType, err)
+	}
+
+	// Deletions only report the ID of the item that was removed.
+	if payload.ID == "" {
+		deleted := struct {
+			ItemID string `json:"itemId"`
+		}{}
+		if err := json.Unmarshal(event.Payload, &deleted); err == nil {
+			payload.ID = deleted.ItemID
+		}
+	}
+
+	payload.TriggerType = event.TriggerType
+	payload.Item = event.Payload
+
+	for _, fn := range callbacks {
+		fn(payload)
+	}
+
+	return nil
+}
+
+// OnEvent Register a callback for every verified delivery, regardless of its trigger type.
+func (h *Handler) OnEvent(fn func(Event)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onEvent = append(h.onEvent, fn)
+}
+
+// OnItemCreated Register a callback for the collection_item_created trigger.
+func (h *Handler) OnItemCreated(fn func(ItemEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onItemCreated = append(h.onItemCreated, fn)
+}
+
+// OnItemChanged Register a callback for the collection_item_changed trigger.
+func (h *Handler) OnItemChanged(fn func(ItemEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onItemChanged = append(h.onItemChanged, fn)
+}
+
+// OnItemDeleted Register a callback for the collection_item_deleted trigger.
+func (h *Handler) OnItemDeleted(fn func(ItemEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onItemDeleted = append(h.onItemDeleted, fn)
+}
+
+// OnItemUnpublished Register a callback for the collection_item_unpublished trigger.
+func (h *Handler) OnItemUnpublished(fn func(ItemEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onItemUnpublished = append(h.onItemUnpublished, fn)
+}
+
+// OnFormSubmission Register a callback for the form_submission trigger.
+func (h *Handler) OnFormSubmission(fn func(FormSubmissionEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onFormSubmission = append(h.onFormSubmission, fn)
+}
+
+// OnSitePublish Register a callback for the site_publish trigger.
+func (h *Handler) OnSitePublish(fn func(SitePublishEvent)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.onSitePublish = append(h.onSitePublish, fn)
+}
@@ -0,0 +1,159 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	secret = "mysecret"
+)
+
+var (
+	exampleNow         = time.Date(2019, 4, 1, 12, 0, 0, 0, time.UTC)
+	exampleItemChanged = `{"triggerType":"collection_item_changed","payload":{"_id":"1","_cid":"10","name":"blue","slug":"blue","_archived":false,"_draft":true}}`
+	exampleItemDeleted = `{"triggerType":"collection_item_deleted","payload":{"deleted":1,"itemId":"2"}}`
+	exampleForm        = `{"triggerType":"form_submission","payload":{"_id":"3","name":"Contact","site":"mysiteid","data":{"email":"a@b.c"},"d":"2019-04-01T12:00:00.000Z"}}`
+)
+
+// newTestHandler Create a handler whose clock is frozen at exampleNow.
+func newTestHandler() *Handler {
+	h := New(secret)
+	h.now = func() time.Time {
+		return exampleNow
+	}
+	return h
+}
+
+// newSignedRequest Create a delivery signed at the given time.
+func newSignedRequest(body string, signedAt time.Time) *http.Request {
+	timestamp := strconv.FormatInt(signedAt.UnixNano()/int64(time.Millisecond), 10)
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(secret, timestamp, []byte(body)))
+	return req
+}
+
+func TestServeHTTPVerification(t *testing.T) {
+	{
+		h := newTestHandler()
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(exampleItemChanged))
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		if rw.Code != http.StatusUnauthorized {
+			t.Errorf("ServeHTTP() is expected to reject unsigned deliveries! Got status %d.", rw.Code)
+		}
+	}
+	{
+		h := newTestHandler()
+		req := newSignedRequest(exampleItemChanged, exampleNow)
+		req.Header.Set(SignatureHeader, Sign("not the secret", req.Header.Get(TimestampHeader), []byte(exampleItemChanged)))
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, req)
+
+		if rw.Code != http.StatusUnauthorized {
+			t.Errorf("ServeHTTP() is expected to reject deliveries with a bad signature! Got status %d.", rw.Code)
+		}
+	}
+	{
+		h := newTestHandler()
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, newSignedRequest(exampleItemChanged, exampleNow.Add(-10*time.Minute)))
+
+		if rw.Code != http.StatusUnauthorized {
+			t.Errorf("ServeHTTP() is expected to reject deliveries outside the tolerance window! Got status %d.", rw.Code)
+		}
+	}
+	{
+		h := newTestHandler()
+		h.Tolerance = 0
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, newSignedRequest(exampleItemChanged, exampleNow.Add(-10*time.Minute)))
+
+		if rw.Code != http.StatusOK {
+			t.Errorf("ServeHTTP() is expected to skip the timestamp check when Tolerance is zero! Got status %d.", rw.Code)
+		}
+	}
+	{
+		h := newTestHandler()
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, newSignedRequest(`{"nope":true}`, exampleNow))
+
+		if rw.Code != http.StatusBadRequest {
+			t.Errorf("ServeHTTP() is expected to reject payloads without a trigger type! Got status %d.", rw.Code)
+		}
+	}
+}
+
+func TestServeHTTPDispatch(t *testing.T) {
+	{
+		h := newTestHandler()
+		changed := []ItemEvent{}
+		deleted := []ItemEvent{}
+		forms := []FormSubmissionEvent{}
+		events := 0
+		h.OnItemChanged(func(ev ItemEvent) {
+			changed = append(changed, ev)
+		})
+		h.OnItemDeleted(func(ev ItemEvent) {
+			deleted = append(deleted, ev)
+		})
+		h.OnFormSubmission(func(ev FormSubmissionEvent) {
+			forms = append(forms, ev)
+		})
+		h.OnEvent(func(ev Event) {
+			events++
+		})
+
+		for _, body := range []string{exampleItemChanged, exampleItemDeleted, exampleForm} {
+			rw := httptest.NewRecorder()
+			h.ServeHTTP(rw, newSignedRequest(body, exampleNow.Add(-1*time.Minute)))
+			if rw.Code != http.StatusOK {
+				t.Errorf("ServeHTTP() is expected to accept a properly signed delivery! Got status %d: %s", rw.Code, rw.Body)
+			}
+		}
+
+		if events != 3 {
+			t.Errorf("ServeHTTP() is expected to call OnEvent for every delivery! Called %d times.", events)
+		}
+
+		if len(changed) != 1 || changed[0].ID != "1" || changed[0].CollectionID != "10" || !changed[0].Draft {
+			t.Errorf("ServeHTTP() did not decode the item changed payload properly! Got %+v.", changed)
+		}
+
+		if len(changed) == 1 && changed[0].TriggerType != TriggerItemChanged {
+			t.Errorf("ServeHTTP() is expected to set the trigger type on item events! Got '%s'.", changed[0].TriggerType)
+		}
+
+		if len(deleted) != 1 || deleted[0].ID != "2" {
+			t.Errorf("ServeHTTP() did not decode the item deleted payload properly! Got %+v.", deleted)
+		}
+
+		if len(forms) != 1 || forms[0].Name != "Contact" || forms[0].Data["email"] != "a@b.c" {
+			t.Errorf("ServeHTTP() did not decode the form submission payload properly! Got %+v.", forms)
+		}
+	}
+	{
+		h := newTestHandler()
+		received := []Event{}
+		h.Receive = func(ev Event) error {
+			received = append(received, ev)
+			return nil
+		}
+		h.OnItemChanged(func(ev ItemEvent) {
+			t.Error("ServeHTTP() is not expected to dispatch when Receive is set.")
+		})
+
+		rw := httptest.NewRecorder()
+		h.ServeHTTP(rw, newSignedRequest(exampleItemChanged, exampleNow))
+
+		if rw.Code != http.StatusOK || len(received) != 1 || received[0].TriggerType != TriggerItemChanged {
+			t.Errorf("ServeHTTP() is expected to hand the event to Receive! Got status %d; events %+v.", rw.Code, received)
+		}
+	}
+}